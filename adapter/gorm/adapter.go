@@ -3,28 +3,29 @@
 package gorm
 
 import (
+	"database/sql"
 	"fmt"
 	"strings"
 	"sync"
 
+	"github.com/next-trace/scg-database/adapter/internal/poolstats"
 	"github.com/next-trace/scg-database/config"
 	"github.com/next-trace/scg-database/contract"
 	"github.com/next-trace/scg-database/db"
-	"gorm.io/driver/mysql"
-	"gorm.io/driver/postgres"
-	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
 
 // Database driver constants
 const (
-	DriverMySQL    = "mysql"
-	DriverPostgres = "postgres"
-	DriverSQLite   = "sqlite"
+	DriverMySQL      = "mysql"
+	DriverPostgres   = "postgres"
+	DriverSQLite     = "sqlite"
+	DriverSQLitePure = "sqlite-pure"
 
-	GormDriverMySQL    = "gorm:mysql"
-	GormDriverPostgres = "gorm:postgres"
-	GormDriverSQLite   = "gorm:sqlite"
+	GormDriverMySQL      = "gorm:mysql"
+	GormDriverPostgres   = "gorm:postgres"
+	GormDriverSQLite     = "gorm:sqlite"
+	GormDriverSQLitePure = "gorm:sqlite-pure"
 )
 
 //nolint:grouper // Only One Global Variable
@@ -45,7 +46,8 @@ type (
 func Register() {
 	registerOnce.Do(func() {
 		// Register this adapter with the central registry for CLI use.
-		db.RegisterAdapter(&Adapter{}, "gorm", GormDriverMySQL, GormDriverPostgres, GormDriverSQLite)
+		db.RegisterAdapter(&Adapter{}, "gorm", GormDriverMySQL, GormDriverPostgres, GormDriverSQLite,
+			GormDriverSQLitePure)
 
 		// Register the GORM query builder factory
 		db.RegisterQueryBuilderFactory("gorm", &GormQueryBuilderFactory{})
@@ -67,32 +69,52 @@ func NewDialectStrategy(driver string) (*DialectStrategy, error) {
 
 // CreateDialector creates a GORM dialector for the specific database type
 func (g *DialectStrategy) CreateDialector(dsn string) (interface{}, error) {
-	switch g.dialectName {
-	case DriverMySQL:
-		return mysql.Open(dsn), nil
-	case DriverPostgres:
-		return postgres.Open(dsn), nil
-	case DriverSQLite:
-		return sqlite.Open(dsn), nil
-	default:
-		return nil, fmt.Errorf("unsupported gorm dialect: %s", g.dialectName)
+	dialect, err := g.dialect()
+	if err != nil {
+		return nil, err
 	}
+	return dialect.Open(dsn), nil
+}
+
+// CreateDialectorWithConn creates a GORM dialector over an existing connection
+// pool. The dsn is only read for dialect settings, such as the MySQL loc parameter.
+func (g *DialectStrategy) CreateDialectorWithConn(conn gorm.ConnPool, dsn string) (gorm.Dialector, error) {
+	dialect, err := g.dialect()
+	if err != nil {
+		return nil, err
+	}
+	if dialect.OpenConn == nil {
+		return nil, fmt.Errorf("gorm dialect %s cannot use an existing connection pool", g.dialectName)
+	}
+	return dialect.OpenConn(conn, dsn), nil
+}
+
+// SQLDriverName returns the name of the database/sql driver used by the dialect.
+func (g *DialectStrategy) SQLDriverName() string {
+	dialect, err := g.dialect()
+	if err != nil {
+		return g.dialectName
+	}
+	return dialect.SQLDriver
 }
 
 // ValidateDriver validates the driver format
 func (g *DialectStrategy) ValidateDriver(driver string) error {
-	driverParts := strings.Split(driver, ":")
-	if len(driverParts) != 2 {
-		return fmt.Errorf("invalid gorm driver format: %s (expected 'gorm:dialect')", driver)
+	strategy, err := NewDialectStrategy(driver)
+	if err != nil {
+		return err
 	}
+	_, err = strategy.dialect()
+	return err
+}
 
-	dialectName := driverParts[1]
-	switch dialectName {
-	case DriverMySQL, DriverPostgres, DriverSQLite:
-		return nil
-	default:
-		return fmt.Errorf("unsupported gorm dialect: %s", dialectName)
+// dialect looks the dialect up in the registry (see RegisterDialect).
+func (g *DialectStrategy) dialect() (Dialect, error) {
+	dialect, ok := LookupDialect(g.dialectName)
+	if !ok {
+		return Dialect{}, fmt.Errorf("unsupported gorm dialect: %s", g.dialectName)
 	}
+	return dialect, nil
 }
 
 // GetDriverName returns the driver name
@@ -107,6 +129,10 @@ func (g *DialectStrategy) GetDriverName() string {
 // GORM plugins that implement gorm.Plugin. Each plugin is registered via db.Use
 // after the base connection is established.
 //
+// New does not ping the database, as the ping of gorm.Open cannot be cancelled:
+// db.ConnectContext pings the connection with its context instead. Callers of
+// New ping the returned DB themselves when they need to know it is reachable.
+//
 // Example (OpenTelemetry tracing):
 //
 //	package main
@@ -148,15 +174,9 @@ func New(cfg *config.Config, plugins ...gorm.Plugin) (*gorm.DB, error) {
 	}
 
 	// Create dialector using strategy
-	dialectorInterface, err := dialectStrategy.CreateDialector(cfg.DSN)
+	dialector, pool, err := newDialector(dialectStrategy, cfg)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create dialector: %w", err)
-	}
-
-	// Type assert to GORM dialector
-	dialector, ok := dialectorInterface.(gorm.Dialector)
-	if !ok {
-		return nil, fmt.Errorf("invalid dialector type")
+		return nil, err
 	}
 
 	// Extract GORM config using helper
@@ -165,9 +185,19 @@ func New(cfg *config.Config, plugins ...gorm.Plugin) (*gorm.DB, error) {
 	// Create GORM database connection
 	gdb, err := gorm.Open(dialector, gormConfig)
 	if err != nil {
+		if pool != nil {
+			_ = pool.Close()
+		}
 		return nil, fmt.Errorf("gorm connection failed: %w", err)
 	}
 
+	// Register the query observers configured with db.WithQueryObserver
+	if observer := newQueryObserver(cfg); observer != nil {
+		if err := gdb.Use(observer); err != nil {
+			return nil, err
+		}
+	}
+
 	// Register provided plugins
 	for _, plugin := range plugins {
 		if plugin == nil {
@@ -181,6 +211,36 @@ func New(cfg *config.Config, plugins ...gorm.Plugin) (*gorm.DB, error) {
 	return gdb, nil
 }
 
+// newDialector creates the dialector for cfg. A DSN with secret references is
+// opened through db.OpenDB, which resolves them for every new pool connection;
+// the pool is then returned so it can be closed if GORM fails to initialize.
+func newDialector(strategy *DialectStrategy, cfg *config.Config) (gorm.Dialector, *sql.DB, error) {
+	if config.HasSecretRefs(cfg.DSN) {
+		pool, err := db.OpenDB(strategy.SQLDriverName(), cfg)
+		if err != nil {
+			return nil, nil, err
+		}
+		dialector, err := strategy.CreateDialectorWithConn(pool, cfg.DSN)
+		if err != nil {
+			_ = pool.Close()
+			return nil, nil, fmt.Errorf("failed to create dialector: %w", err)
+		}
+		return dialector, pool, nil
+	}
+
+	dialectorInterface, err := strategy.CreateDialector(cfg.DSN)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create dialector: %w", err)
+	}
+
+	// Type assert to GORM dialector
+	dialector, ok := dialectorInterface.(gorm.Dialector)
+	if !ok {
+		return nil, nil, fmt.Errorf("invalid dialector type")
+	}
+	return dialector, nil, nil
+}
+
 // Connect establishes a new database connection using GORM and wraps it into the
 // library's contract.Connection. This maintains backward compatibility for
 // existing consumers of the adapter while internally using New for creation.
@@ -201,5 +261,5 @@ func (a *Adapter) Connect(cfg *config.Config) (contract.Connection, error) {
 	poolOptions := configFromOptions(cfg)
 	applyConnectionPoolOptions(sqlDB, poolOptions...)
 
-	return &connection{db: gormDB, config: *cfg}, nil
+	return &connection{db: gormDB, config: *cfg, health: &poolstats.Health{}}, nil
 }
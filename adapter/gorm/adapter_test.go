@@ -1,18 +1,23 @@
 package gorm
 
 import (
+	"context"
 	"fmt"
+	"net"
+	"os"
+	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/next-trace/scg-database/config"
 	"github.com/next-trace/scg-database/contract"
+	"github.com/next-trace/scg-database/db"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"gorm.io/driver/mysql"
 	"gorm.io/driver/postgres"
-	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 )
@@ -56,7 +61,7 @@ func (m *TestModel) SetUpdatedAt(t time.Time) { m.UpdatedAt = t }
 func TestGormAdapter_Connect(t *testing.T) {
 	t.Run("SuccessSQLite", func(t *testing.T) {
 		cfg := config.Config{
-			Driver: "gorm:sqlite",
+			Driver: testSQLiteDriver,
 			DSN:    ":memory:",
 		}
 		gormAdapter := &Adapter{}
@@ -80,7 +85,7 @@ func TestGormAdapter_Connect(t *testing.T) {
 
 	t.Run("WithOptions", func(t *testing.T) {
 		cfg := config.Config{
-			Driver: "gorm:sqlite",
+			Driver: testSQLiteDriver,
 			DSN:    ":memory:",
 		}
 		opt := WithLogger(logger.Default.LogMode(logger.Silent))
@@ -103,7 +108,7 @@ func TestGormAdapter_Name(t *testing.T) {
 
 func TestGormAdapter_ConnectWithConnectionPool(t *testing.T) {
 	cfg := config.Config{
-		Driver:          "gorm:sqlite",
+		Driver:          testSQLiteDriver,
 		DSN:             ":memory:",
 		MaxOpenConns:    20,
 		MaxIdleConns:    10,
@@ -130,7 +135,7 @@ func TestGormAdapter_ConnectWithGormConfig(t *testing.T) {
 	}
 
 	cfg := config.Config{
-		Driver: "gorm:sqlite",
+		Driver: testSQLiteDriver,
 		DSN:    ":memory:",
 		Settings: map[string]any{
 			"gorm_config": gormConfig,
@@ -142,13 +147,14 @@ func TestGormAdapter_ConnectWithGormConfig(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, conn)
 	require.NoError(t, conn.Close())
+	require.False(t, gormConfig.DisableAutomaticPing, "the caller's config is left untouched")
 }
 
 func TestGormAdapter_ConnectWithGormLogger(t *testing.T) {
 	customLogger := logger.Default.LogMode(logger.Info)
 
 	cfg := config.Config{
-		Driver: "gorm:sqlite",
+		Driver: testSQLiteDriver,
 		DSN:    ":memory:",
 		Settings: map[string]any{
 			"gorm_logger": customLogger,
@@ -198,6 +204,65 @@ func TestGormAdapter_ConnectPostgresDialect(t *testing.T) {
 	// Should fail due to invalid DSN, but we test the Postgres dialect path
 }
 
+// silentServer accepts TCP connections and never answers, like a database
+// host whose network drops every reply.
+func silentServer(t *testing.T) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	var (
+		mu    sync.Mutex
+		conns []net.Conn
+	)
+	go func() {
+		for {
+			c, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			mu.Lock()
+			conns = append(conns, c)
+			mu.Unlock()
+		}
+	}()
+	t.Cleanup(func() {
+		_ = listener.Close()
+		mu.Lock()
+		defer mu.Unlock()
+		for _, c := range conns {
+			_ = c.Close()
+		}
+	})
+	return listener.Addr().String()
+}
+
+func TestConnectContext_DeadlineWhileConnecting(t *testing.T) {
+	Register()
+	cfg := config.Config{
+		Driver: GormDriverPostgres,
+		DSN:    "postgres://user:secret@" + silentServer(t) + "/app?sslmode=disable",
+	}
+	ctx, cancel := context.WithTimeout(t.Context(), 100*time.Millisecond)
+	defer cancel()
+
+	errs := make(chan error, 1)
+	go func() {
+		conn, err := db.ConnectContext(ctx, &cfg)
+		if conn != nil {
+			_ = conn.Close()
+		}
+		errs <- err
+	}()
+
+	select {
+	case err := <-errs:
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	case <-time.After(5 * time.Second):
+		t.Fatal("ConnectContext ignored the deadline of its context")
+	}
+}
+
 // connectToGorm is a helper function for testing direct GORM connections
 func connectToGorm(cfg *config.Config) (*gorm.DB, error) {
 	driverParts := strings.Split(cfg.Driver, ":")
@@ -213,8 +278,8 @@ func connectToGorm(cfg *config.Config) (*gorm.DB, error) {
 		dialector = mysql.Open(cfg.DSN)
 	case DriverPostgres:
 		dialector = postgres.Open(cfg.DSN)
-	case DriverSQLite:
-		dialector = sqlite.Open(cfg.DSN)
+	case DriverSQLite, DriverSQLitePure:
+		dialector = openTestSQLite(cfg.DSN)
 	default:
 		return nil, fmt.Errorf("unsupported gorm dialect: %s", dialectName)
 	}
@@ -231,7 +296,7 @@ func TestConnectToGorm_AllDialects(t *testing.T) {
 	}{
 		{
 			name:      "SQLite success",
-			driver:    "gorm:sqlite",
+			driver:    testSQLiteDriver,
 			dsn:       ":memory:",
 			shouldErr: false,
 		},
@@ -292,7 +357,7 @@ func TestConnectToGorm_AllDialects(t *testing.T) {
 
 func TestGormAdapter_ConnectDBError(t *testing.T) {
 	cfg := config.Config{
-		Driver: "gorm:sqlite",
+		Driver: testSQLiteDriver,
 		DSN:    ":memory:",
 	}
 
@@ -310,7 +375,7 @@ func TestGormAdapter_ConnectDBError(t *testing.T) {
 
 func TestConnection_CloseError(t *testing.T) {
 	cfg := config.Config{
-		Driver: "gorm:sqlite",
+		Driver: testSQLiteDriver,
 		DSN:    ":memory:",
 	}
 
@@ -331,7 +396,7 @@ func TestConnection_CloseError(t *testing.T) {
 
 func TestConnection_NewRepositoryNilModel(t *testing.T) {
 	cfg := config.Config{
-		Driver: "gorm:sqlite",
+		Driver: testSQLiteDriver,
 		DSN:    ":memory:",
 	}
 
@@ -350,7 +415,7 @@ func TestConnection_NewRepositoryNilModel(t *testing.T) {
 
 func TestConnection_StatementError(t *testing.T) {
 	cfg := config.Config{
-		Driver: "gorm:sqlite",
+		Driver: testSQLiteDriver,
 		DSN:    ":memory:",
 	}
 
@@ -367,7 +432,7 @@ func TestConnection_StatementError(t *testing.T) {
 
 func TestRepository_WithRelationships(t *testing.T) {
 	cfg := config.Config{
-		Driver: "gorm:sqlite",
+		Driver: testSQLiteDriver,
 		DSN:    ":memory:",
 	}
 
@@ -392,7 +457,7 @@ func TestRepository_WithRelationships(t *testing.T) {
 
 func TestRepository_OrderByEdgeCases(t *testing.T) {
 	cfg := config.Config{
-		Driver: "gorm:sqlite",
+		Driver: testSQLiteDriver,
 		DSN:    ":memory:",
 	}
 
@@ -421,7 +486,7 @@ func TestRepository_OrderByEdgeCases(t *testing.T) {
 
 func TestRepository_LimitOffsetEdgeCases(t *testing.T) {
 	cfg := config.Config{
-		Driver: "gorm:sqlite",
+		Driver: testSQLiteDriver,
 		DSN:    ":memory:",
 	}
 
@@ -453,7 +518,7 @@ func TestRepository_LimitOffsetEdgeCases(t *testing.T) {
 
 func TestRepository_ConvertModelsToSliceErrors(t *testing.T) {
 	cfg := config.Config{
-		Driver: "gorm:sqlite",
+		Driver: testSQLiteDriver,
 		DSN:    ":memory:",
 	}
 
@@ -480,7 +545,7 @@ func TestRepository_ConvertModelsToSliceErrors(t *testing.T) {
 
 func TestRepository_CreateInBatchesEdgeCases(t *testing.T) {
 	cfg := config.Config{
-		Driver: "gorm:sqlite",
+		Driver: testSQLiteDriver,
 		DSN:    ":memory:",
 	}
 
@@ -512,7 +577,7 @@ func TestRepository_CreateInBatchesEdgeCases(t *testing.T) {
 
 func TestRepository_UpdateMultipleModels(t *testing.T) {
 	cfg := config.Config{
-		Driver: "gorm:sqlite",
+		Driver: testSQLiteDriver,
 		DSN:    ":memory:",
 	}
 
@@ -545,3 +610,62 @@ func TestRepository_UpdateMultipleModels(t *testing.T) {
 	err = repo.Update(t.Context(), model1, model2)
 	require.NoError(t, err)
 }
+
+func TestGormAdapter_ConnectWithSecretDSN(t *testing.T) {
+	dir := t.TempDir()
+	secretFile := filepath.Join(dir, "dsn")
+	require.NoError(t, os.WriteFile(secretFile, []byte(filepath.Join(dir, "first.db")), 0o600))
+
+	cfg := config.Config{Driver: testSQLiteDriver, DSN: "secret://file/dsn"}
+	config.WithSecretProvider(config.SecretProviderFile, config.FileSecretProvider{Dir: dir})(&cfg)
+
+	conn, err := (&Adapter{}).Connect(&cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	gormDB, ok := conn.GetConnection().(*gorm.DB)
+	require.True(t, ok)
+	sqlDB, err := gormDB.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxIdleConns(0)
+
+	databaseFile := func() string {
+		var file string
+		require.NoError(t, gormDB.Raw("SELECT file FROM pragma_database_list WHERE name = 'main'").Scan(&file).Error)
+		return filepath.Base(file)
+	}
+	require.Equal(t, "first.db", databaseFile())
+
+	require.NoError(t, os.WriteFile(secretFile, []byte(filepath.Join(dir, "second.db")), 0o600))
+	require.Equal(t, "second.db", databaseFile(), "new connections use the rotated secret")
+}
+
+func TestGormAdapter_ConnectWithMissingSecret(t *testing.T) {
+	cfg := config.Config{Driver: testSQLiteDriver, DSN: "secret://env/TEST_GORM_SURELY_UNSET"}
+
+	_, err := (&Adapter{}).Connect(&cfg)
+	require.ErrorContains(t, err, `"TEST_GORM_SURELY_UNSET" is not set`)
+}
+
+func TestDialectStrategy_CreateDialectorWithConn(t *testing.T) {
+	tests := []struct {
+		driver, sqlDriver, name string
+	}{
+		{GormDriverMySQL, "mysql", "mysql"},
+		{GormDriverPostgres, "pgx", "postgres"},
+		{GormDriverSQLite, "sqlite3", "sqlite"},
+		{GormDriverSQLitePure, "sqlite", "sqlite"},
+	}
+	for _, tt := range tests {
+		strategy, err := NewDialectStrategy(tt.driver)
+		require.NoError(t, err)
+		require.Equal(t, tt.sqlDriver, strategy.SQLDriverName())
+
+		dialector, err := strategy.CreateDialectorWithConn(nil, "")
+		require.NoError(t, err)
+		require.Equal(t, tt.name, dialector.Name())
+	}
+
+	_, err := (&DialectStrategy{dialectName: "oracle"}).CreateDialectorWithConn(nil, "")
+	require.EqualError(t, err, "unsupported gorm dialect: oracle")
+}
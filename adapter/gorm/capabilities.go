@@ -0,0 +1,41 @@
+package gorm
+
+import (
+	"github.com/next-trace/scg-database/contract"
+)
+
+var (
+	// Ensure Adapter reports its capabilities and dialects
+	_ contract.Capabilities = (*Adapter)(nil)
+	_ contract.Dialects     = (*Adapter)(nil)
+)
+
+// Supports reports whether the dialect of driver supports capability,
+// fulfilling contract.Capabilities.
+func (a *Adapter) Supports(driver string, capability contract.Capability) bool {
+	strategy, err := NewDialectStrategy(driver)
+	if err != nil {
+		return false
+	}
+	return dialectSupports(strategy.GetDriverName(), capability)
+}
+
+// DialectInfo returns the migration driver and truncate statement of the
+// dialect of driver, fulfilling contract.Dialects.
+func (a *Adapter) DialectInfo(driver string) (contract.DialectInfo, bool) {
+	strategy, err := NewDialectStrategy(driver)
+	if err != nil {
+		return contract.DialectInfo{}, false
+	}
+	dialect, err := strategy.dialect()
+	if err != nil {
+		return contract.DialectInfo{}, false
+	}
+	return dialect.info(), true
+}
+
+// dialectSupports reports whether the registered dialect name supports capability.
+func dialectSupports(name string, capability contract.Capability) bool {
+	dialect, ok := LookupDialect(name)
+	return ok && dialect.Supports(capability)
+}
@@ -0,0 +1,100 @@
+package gorm
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/next-trace/scg-database/config"
+	"github.com/next-trace/scg-database/contract"
+	"github.com/next-trace/scg-database/db"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestAdapter_Supports(t *testing.T) {
+	adapter := &Adapter{}
+
+	for _, capability := range contract.AllCapabilities() {
+		require.True(t, adapter.Supports(GormDriverPostgres, capability), capability)
+	}
+	require.False(t, adapter.Supports(GormDriverMySQL, contract.CapabilityReturning))
+	require.True(t, adapter.Supports(GormDriverMySQL, contract.CapabilityUpsert))
+	require.False(t, adapter.Supports(GormDriverSQLite, contract.CapabilityRowLocking))
+	require.True(t, adapter.Supports(GormDriverSQLite, contract.CapabilitySavepoints))
+
+	require.False(t, adapter.Supports("gorm:oracle", contract.CapabilitySavepoints))
+	require.False(t, adapter.Supports("gorm", contract.CapabilitySavepoints))
+	require.False(t, adapter.Supports(GormDriverPostgres, "time_travel"))
+}
+
+func TestAdapter_SupportsThroughRegistry(t *testing.T) {
+	Register()
+
+	require.True(t, db.Supports(GormDriverPostgres, contract.CapabilityRowLocking))
+	require.False(t, db.Supports(GormDriverSQLite, contract.CapabilityRowLocking))
+
+	for _, info := range db.ListAdapters() {
+		if info.Name == GormDriverMySQL {
+			require.NotContains(t, info.Capabilities, contract.CapabilityReturning)
+			require.Contains(t, info.Capabilities, contract.CapabilityMigrations)
+			return
+		}
+	}
+	t.Fatal("gorm:mysql is not listed")
+}
+
+// renamedDialector reports another dialect name.
+type renamedDialector struct {
+	gorm.Dialector
+	name string
+}
+
+func (d renamedDialector) Name() string { return d.name }
+
+func (d renamedDialector) SavePoint(tx *gorm.DB, name string) error {
+	return d.Dialector.(gorm.SavePointerDialectorInterface).SavePoint(tx, name) //nolint:forcetypeassert // SQLite's
+}
+
+func (d renamedDialector) RollbackTo(tx *gorm.DB, name string) error {
+	return d.Dialector.(gorm.SavePointerDialectorInterface).RollbackTo(tx, name) //nolint:forcetypeassert // SQLite's
+}
+
+// nestedTransaction connects to a new SQLite database through driver and runs
+// an empty transaction nested in another. It reports whether the inner one ran.
+func nestedTransaction(t *testing.T, driver string) (bool, error) {
+	t.Helper()
+	conn, err := db.Connect(&config.Config{Driver: driver, DSN: filepath.Join(t.TempDir(), "nested.db")})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	called := false
+	err = conn.Transaction(t.Context(), func(tx contract.Connection) error {
+		return tx.Transaction(t.Context(), func(contract.Connection) error {
+			called = true
+			return nil
+		})
+	})
+	return called, err
+}
+
+func TestConnection_SavepointUnsupported(t *testing.T) {
+	// The dialector is SQLite's, whose dialect supports savepoints, but the
+	// dialect registered under this name does not declare them.
+	require.NoError(t, RegisterDialect("nosavepoints", openTestSQLite))
+
+	called, err := nestedTransaction(t, "gorm:nosavepoints")
+	require.ErrorIs(t, err, db.ErrUnsupportedCapability)
+	require.ErrorContains(t, err, `driver "gorm:nosavepoints" does not support savepoints`)
+	require.False(t, called)
+}
+
+func TestConnection_SavepointsOfRenamedDialect(t *testing.T) {
+	// No dialect is registered under the name of this dialector.
+	require.NoError(t, RegisterDialect("renamed", func(dsn string) gorm.Dialector {
+		return renamedDialector{Dialector: openTestSQLite(dsn), name: "oracle"}
+	}, WithDialectCapabilities(contract.CapabilitySavepoints)))
+
+	called, err := nestedTransaction(t, "gorm:renamed")
+	require.NoError(t, err)
+	require.True(t, called)
+}
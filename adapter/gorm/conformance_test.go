@@ -0,0 +1,34 @@
+package gorm
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/next-trace/scg-database/config"
+	"github.com/next-trace/scg-database/contract"
+	"github.com/next-trace/scg-database/db"
+	"github.com/next-trace/scg-database/testing/conformance"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestConformance(t *testing.T) {
+	Register()
+	for _, driver := range testSQLiteDrivers {
+		t.Run(driver, func(t *testing.T) {
+			conformance.RunConformance(t, func(t *testing.T) contract.Connection {
+				conn, err := db.Connect(&config.Config{Driver: driver, DSN: filepath.Join(t.TempDir(), "test.db")})
+				require.NoError(t, err)
+				t.Cleanup(func() { _ = conn.Close() })
+
+				models := conformance.Models()
+				tables := make([]any, len(models))
+				for i, model := range models {
+					tables[i] = model
+				}
+				require.NoError(t, conn.GetConnection().(*gorm.DB).AutoMigrate(tables...))
+				return conn
+			})
+		})
+	}
+}
@@ -3,11 +3,18 @@ package gorm
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"reflect"
+	"strings"
+	"time"
 
+	"github.com/next-trace/scg-database/adapter/internal/poolstats"
+	"github.com/next-trace/scg-database/adapter/internal/txhooks"
+	"github.com/next-trace/scg-database/adapter/internal/txrun"
 	"github.com/next-trace/scg-database/config"
 	"github.com/next-trace/scg-database/contract"
+	"github.com/next-trace/scg-database/db"
 	"gorm.io/gorm"
 )
 
@@ -15,12 +22,25 @@ type (
 	connection struct {
 		db     *gorm.DB
 		config config.Config
+		// depth is the transaction nesting level: 0 for the root connection,
+		// 1 inside Transaction, and one more for every nested savepoint.
+		depth int
+		// savepoints numbers the savepoints of the root transaction so that each
+		// gets a unique name, also when nesting goes through the ambient
+		// transaction; it is nil outside a transaction.
+		savepoints *txrun.Savepoints
+		// hooks collects OnCommit and OnRollback callbacks of the current
+		// transaction scope; it is nil outside a transaction.
+		hooks *txhooks.Hooks
+		// health records the last Ping for Stats.
+		health *poolstats.Health
 	}
 )
 
 // Ensure the implementation satisfies the interface at compile time.
 var (
 	_ contract.Connection = (*connection)(nil)
+	_ contract.TxHooks    = (*connection)(nil)
 )
 
 func (c *connection) NewRepository(model contract.Model) (contract.Repository, error) {
@@ -34,12 +54,18 @@ func (c *connection) GetConnection() any {
 	return c.db
 }
 
+// Ping checks the database and records the latency and outcome for Stats.
 func (c *connection) Ping(ctx context.Context) error {
 	sqlDB, err := c.db.DB()
 	if err != nil {
 		return err
 	}
-	return sqlDB.PingContext(ctx)
+	start := time.Now()
+	err = sqlDB.PingContext(ctx)
+	if c.health != nil {
+		c.health.Record(start, err)
+	}
+	return err
 }
 
 func (c *connection) Close() error {
@@ -52,25 +78,196 @@ func (c *connection) Close() error {
 	return sqlDB.Close()
 }
 
-func (c *connection) Transaction(ctx context.Context, fn func(txConnection contract.Connection) error) error {
-	return c.db.WithContext(ctx).Transaction(func(txGorm *gorm.DB) error {
-		txConn := &connection{db: txGorm, config: c.config}
-		return fn(txConn)
+// Transaction runs fn in a database transaction configured by opts. Called on a
+// connection that is already inside a transaction, it runs fn inside a SAVEPOINT
+// instead, so a failing inner function only undoes its own work; the same happens
+// when ctx carries a transaction of this database (see db.WithTx). Savepoints share
+// the outer transaction, so opts only apply to the outermost call.
+//
+// With a retry policy, the whole transaction is run again after deadlocks and
+// serialization failures, unless the policy has its own classifier.
+func (c *connection) Transaction(
+	ctx context.Context,
+	fn func(txConnection contract.Connection) error,
+	opts ...contract.TxOption,
+) error {
+	if c.depth > 0 {
+		return c.savepoint(ctx, fn)
+	}
+	if tx := ambientTx(ctx, c.db); tx != nil {
+		return tx.savepoint(ctx, fn)
+	}
+
+	txOpts := contract.NewTxOptions(opts...)
+	return txrun.Retry(ctx, txOpts, isRetryable, func(ctx context.Context) error {
+		return c.transaction(ctx, fn, txOpts)
 	})
 }
 
-// Select executes a raw read query, fulfilling the contract.
+// transaction runs one attempt of a root transaction.
+func (c *connection) transaction(
+	ctx context.Context,
+	fn func(txConnection contract.Connection) error,
+	txOpts contract.TxOptions,
+) (err error) {
+	if txOpts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, txOpts.Timeout)
+		defer cancel()
+	}
+
+	var sqlOpts []*sql.TxOptions
+	if o := txOpts.SQL(); o != nil {
+		sqlOpts = append(sqlOpts, o)
+	}
+
+	// GORM rolls the transaction back when fn panics.
+	err = txrun.Run(func(hooks *txhooks.Hooks) error {
+		return translateError(c.db.WithContext(ctx).Transaction(func(txGorm *gorm.DB) error {
+			txConn := &connection{
+				db:         txGorm,
+				config:     c.config,
+				depth:      1,
+				savepoints: &txrun.Savepoints{},
+				hooks:      hooks,
+				health:     c.health,
+			}
+			return fn(txConn)
+		}, sqlOpts...))
+	}, nil)
+
+	// database/sql rolls the transaction back when its deadline passes; make the
+	// timeout visible instead of the resulting "transaction has already been committed
+	// or rolled back" error.
+	timedOut := txOpts.Timeout > 0 && errors.Is(ctx.Err(), context.DeadlineExceeded)
+	if err != nil && timedOut && !errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("transaction timed out after %s: %w", txOpts.Timeout, errors.Join(ctx.Err(), err))
+	}
+	return err
+}
+
+// savepoint runs fn inside a savepoint of the current transaction. The savepoint
+// is rolled back to when fn fails and released when it succeeds. The dialect
+// registered under the name in the connection's driver, not the name of its GORM
+// dialector, must support savepoints; otherwise savepoint fails with
+// db.ErrUnsupportedCapability before any SQL is sent. A panic in fn propagates
+// to the outermost Transaction, which rolls everything back.
+func (c *connection) savepoint(ctx context.Context, fn func(txConnection contract.Connection) error) error {
+	dialect := strings.TrimPrefix(c.config.Driver, "gorm:")
+	if !dialectSupports(dialect, contract.CapabilitySavepoints) {
+		return db.NewUnsupportedCapabilityError("Transaction", c.config.Driver, contract.CapabilitySavepoints)
+	}
+	name := c.savepoints.Next()
+	tx := c.db.WithContext(ctx)
+
+	if err := tx.SavePoint(name).Error; err != nil {
+		return fmt.Errorf("failed to create savepoint %s: %w", name, err)
+	}
+
+	hooks := &txhooks.Hooks{}
+	rolledBack := false
+	defer func() {
+		if rolledBack {
+			c.hooks.RollbackTo(hooks)
+		} else {
+			c.hooks.Release(hooks)
+		}
+	}()
+
+	inner := &connection{
+		db:         c.db,
+		config:     c.config,
+		depth:      c.depth + 1,
+		savepoints: c.savepoints,
+		hooks:      hooks,
+		health:     c.health,
+	}
+	if err := fn(inner); err != nil {
+		rolledBack = true
+		if rbErr := tx.RollbackTo(name).Error; rbErr != nil {
+			return errors.Join(err, fmt.Errorf("failed to roll back to savepoint %s: %w", name, rbErr))
+		}
+		return err
+	}
+
+	if err := tx.Exec("RELEASE SAVEPOINT " + name).Error; err != nil {
+		return fmt.Errorf("failed to release savepoint %s: %w", name, err)
+	}
+	return nil
+}
+
+// OnCommit registers fn to run after the outermost transaction commits, fulfilling
+// contract.TxHooks. Outside a transaction there is nothing to wait for, so fn runs
+// immediately.
+func (c *connection) OnCommit(fn func()) {
+	if c.hooks == nil {
+		fn()
+		return
+	}
+	c.hooks.OnCommit(fn)
+}
+
+// OnRollback registers fn to run once the work of the current transaction scope
+// has been rolled back, fulfilling contract.TxHooks. Hooks of a rolled back
+// savepoint run when the outermost transaction finishes. Outside a transaction
+// fn never runs.
+func (c *connection) OnRollback(fn func()) {
+	if c.hooks != nil {
+		c.hooks.OnRollback(fn)
+	}
+}
+
+// Select executes a raw read query, fulfilling the contract. Like Statement, it
+// runs on the transaction when called on, or with a context carrying, one.
 func (c *connection) Select(ctx context.Context, query string, bindings ...any) ([]map[string]any, error) {
 	var results []map[string]any
-	err := c.db.WithContext(ctx).Raw(query, bindings...).Scan(&results).Error
+	err := translateError(withContext(ctx, c.db).Raw(query, bindings...).Scan(&results).Error)
 	return results, err
 }
 
-// Statement executes a raw write query, fulfilling the contract.
+// Statement executes a raw write query, fulfilling the contract. On a transaction
+// connection, or with a context carrying one, it runs on the transaction, so a
+// rollback undoes it.
 func (c *connection) Statement(ctx context.Context, query string, bindings ...any) (sql.Result, error) {
-	sqlDB, err := c.db.DB()
-	if err != nil {
-		return nil, err
+	start := time.Now()
+	result, err := withContext(ctx, c.db).Statement.ConnPool.ExecContext(ctx, query, bindings...)
+	err = translateError(err)
+
+	if observer := queryObserverOf(c.db); observer != nil {
+		event := contract.QueryEvent{Operation: "exec", SQL: query, Args: bindings, Duration: time.Since(start), Err: err}
+		if result != nil {
+			event.RowsAffected, _ = result.RowsAffected()
+		}
+		observer.observe(ctx, event)
+	}
+	return result, err
+}
+
+// withContext returns a session of database bound to ctx. When ctx carries a
+// transaction of the same database (see db.WithTx) and database is not already
+// a transaction handle, the session runs on that transaction.
+func withContext(ctx context.Context, database *gorm.DB) *gorm.DB {
+	sess := database.WithContext(ctx)
+	if _, inTx := sess.Statement.ConnPool.(gorm.TxCommitter); inTx {
+		return sess
+	}
+	if tx := ambientTx(ctx, database); tx != nil {
+		sess.Statement.ConnPool = tx.db.Statement.ConnPool
+	}
+	return sess
+}
+
+// ambientTx returns the transaction stored in ctx when it belongs to the same
+// database as database, and nil otherwise. Sessions copy the gorm.Config, so the
+// database is identified by the connection pool it was opened with.
+func ambientTx(ctx context.Context, database *gorm.DB) *connection {
+	ambient, ok := db.TxFromContext(ctx)
+	if !ok {
+		return nil
+	}
+	tx, ok := ambient.(*connection)
+	if !ok || tx.depth == 0 || tx.db.Config.ConnPool != database.Config.ConnPool {
+		return nil
 	}
-	return sqlDB.ExecContext(ctx, query, bindings...)
+	return tx
 }
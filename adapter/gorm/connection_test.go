@@ -1,12 +1,21 @@
 package gorm
 
 import (
+	"context"
+	"database/sql"
 	"errors"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	mysqldriver "github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/next-trace/scg-database/adapter/internal/poolstats"
 	"github.com/next-trace/scg-database/config"
 	"github.com/next-trace/scg-database/contract"
+	"github.com/next-trace/scg-database/db"
 	"github.com/stretchr/testify/require"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
@@ -15,8 +24,19 @@ import (
 type (
 	// dummyModel is a minimal model for connection tests.
 	dummyModel struct{ contract.BaseModel }
+
+	// recordingPool records the options passed to BeginTx.
+	recordingPool struct {
+		*sql.DB
+		opts *sql.TxOptions
+	}
 )
 
+func (p *recordingPool) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	p.opts = opts
+	return p.DB.BeginTx(ctx, opts)
+}
+
 func (d *dummyModel) TableName() string { return "dummy_models" }
 
 // setupConnTestDB is a helper to create a GORM DB with a mock for connection-level tests.
@@ -35,6 +55,25 @@ func setupConnTestDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
 	return gormDB, mock
 }
 
+// openTestSQLite creates the dialector of testSQLiteDriver.
+func openTestSQLite(dsn string) gorm.Dialector {
+	dialect, _ := LookupDialect(strings.TrimPrefix(testSQLiteDriver, "gorm:"))
+	return dialect.Open(dsn)
+}
+
+// newSQLiteTestConn connects to a file-backed SQLite database with the test_models table.
+func newSQLiteTestConn(t *testing.T) contract.Connection {
+	t.Helper()
+	conn, err := (&Adapter{}).Connect(&config.Config{
+		Driver: testSQLiteDriver,
+		DSN:    filepath.Join(t.TempDir(), "test.db"),
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	require.NoError(t, conn.GetConnection().(*gorm.DB).AutoMigrate(&TestModel{}))
+	return conn
+}
+
 func TestConnection_Ping(t *testing.T) {
 	gormDB, mock := setupConnTestDB(t)
 	conn := &connection{db: gormDB}
@@ -106,6 +145,259 @@ func TestConnection_Transaction(t *testing.T) {
 		require.ErrorIs(t, err, txErr)
 		require.NoError(t, mock.ExpectationsWereMet())
 	})
+
+	t.Run("NestedRelease", func(t *testing.T) {
+		gormDB, mock := setupConnTestDB(t)
+		conn := &connection{db: gormDB, config: config.Config{Driver: GormDriverMySQL}}
+		mock.ExpectBegin()
+		mock.ExpectExec("SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("SAVEPOINT sp_2").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("RELEASE SAVEPOINT sp_2").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("RELEASE SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		err := conn.Transaction(t.Context(), func(tx contract.Connection) error {
+			return tx.Transaction(t.Context(), func(inner contract.Connection) error {
+				return inner.Transaction(t.Context(), func(contract.Connection) error { return nil })
+			})
+		})
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("NestedRollbackToSavepoint", func(t *testing.T) {
+		gormDB, mock := setupConnTestDB(t)
+		conn := &connection{db: gormDB, config: config.Config{Driver: GormDriverMySQL}}
+		innerErr := errors.New("inner error")
+		mock.ExpectBegin()
+		mock.ExpectExec("SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("ROLLBACK TO SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		err := conn.Transaction(t.Context(), func(tx contract.Connection) error {
+			err := tx.Transaction(t.Context(), func(contract.Connection) error { return innerErr })
+			require.ErrorIs(t, err, innerErr)
+			return nil // The outer transaction handles the failure and still commits.
+		})
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("NestedSavepointError", func(t *testing.T) {
+		gormDB, mock := setupConnTestDB(t)
+		conn := &connection{db: gormDB, config: config.Config{Driver: GormDriverMySQL}}
+		spErr := errors.New("savepoint error")
+		mock.ExpectBegin()
+		mock.ExpectExec("SAVEPOINT sp_1").WillReturnError(spErr)
+		mock.ExpectRollback()
+
+		err := conn.Transaction(t.Context(), func(tx contract.Connection) error {
+			return tx.Transaction(t.Context(), func(contract.Connection) error {
+				t.Fatal("inner function must not run when the savepoint fails")
+				return nil
+			})
+		})
+		require.ErrorIs(t, err, spErr)
+		require.Contains(t, err.Error(), "failed to create savepoint sp_1")
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestConnection_TransactionOptions(t *testing.T) {
+	t.Run("IsolationAndReadOnly", func(t *testing.T) {
+		sqlDB, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		pool := &recordingPool{DB: sqlDB}
+		gormDB, err := gorm.Open(mysql.New(mysql.Config{Conn: pool, SkipInitializeWithVersion: true}), &gorm.Config{})
+		require.NoError(t, err)
+		conn := &connection{db: gormDB}
+
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+		err = conn.Transaction(t.Context(), func(contract.Connection) error { return nil },
+			contract.WithIsolation(sql.LevelSerializable), contract.WithReadOnly())
+		require.NoError(t, err)
+		require.Equal(t, &sql.TxOptions{Isolation: sql.LevelSerializable, ReadOnly: true}, pool.opts)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		conn := newSQLiteTestConn(t)
+
+		err := conn.Transaction(t.Context(), func(tx contract.Connection) error {
+			time.Sleep(50 * time.Millisecond)
+			repo, err := tx.NewRepository(&TestModel{})
+			require.NoError(t, err)
+			return repo.Create(context.Background(), &TestModel{Name: "late"})
+		}, contract.WithTxTimeout(10*time.Millisecond))
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+
+		rows, err := conn.Select(t.Context(), "SELECT name FROM test_models")
+		require.NoError(t, err)
+		require.Empty(t, rows, "the timed out transaction should be rolled back")
+	})
+}
+
+func TestConnection_TransactionRetry(t *testing.T) {
+	policy := contract.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}
+	update := func(calls *int) func(contract.Connection) error {
+		return func(tx contract.Connection) error {
+			*calls++
+			_, err := tx.Statement(t.Context(), "UPDATE accounts SET balance = balance - 1")
+			return err
+		}
+	}
+
+	t.Run("RetriesSerializationFailure", func(t *testing.T) {
+		gormDB, mock := setupConnTestDB(t)
+		conn := &connection{db: gormDB}
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE accounts").WillReturnError(&pgconn.PgError{Code: "40001"})
+		mock.ExpectRollback()
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE accounts").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		calls := 0
+		err := conn.Transaction(t.Context(), update(&calls), contract.WithRetry(policy))
+		require.NoError(t, err)
+		require.Equal(t, 2, calls)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("ReportsAttempts", func(t *testing.T) {
+		gormDB, mock := setupConnTestDB(t)
+		conn := &connection{db: gormDB}
+		for range 3 {
+			mock.ExpectBegin()
+			mock.ExpectExec("UPDATE accounts").WillReturnError(&mysqldriver.MySQLError{Number: 1213})
+			mock.ExpectRollback()
+		}
+
+		calls := 0
+		err := conn.Transaction(t.Context(), update(&calls), contract.WithRetry(policy))
+		var retryErr *db.RetryError
+		require.ErrorAs(t, err, &retryErr)
+		require.Equal(t, 3, retryErr.Attempts)
+		require.Contains(t, err.Error(), "failed after 3 attempt(s)")
+		var mysqlErr *mysqldriver.MySQLError
+		require.ErrorAs(t, err, &mysqlErr)
+		require.Equal(t, 3, calls)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("DoesNotRetryOtherErrors", func(t *testing.T) {
+		gormDB, mock := setupConnTestDB(t)
+		conn := &connection{db: gormDB}
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE accounts").WillReturnError(errors.New("syntax error"))
+		mock.ExpectRollback()
+
+		calls := 0
+		err := conn.Transaction(t.Context(), update(&calls), contract.WithRetry(policy))
+		var retryErr *db.RetryError
+		require.ErrorAs(t, err, &retryErr)
+		require.Equal(t, 1, retryErr.Attempts)
+		require.Equal(t, 1, calls)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("CustomClassifier", func(t *testing.T) {
+		gormDB, mock := setupConnTestDB(t)
+		conn := &connection{db: gormDB}
+		busy := errors.New("database is locked")
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE accounts").WillReturnError(busy)
+		mock.ExpectRollback()
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE accounts").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		custom := policy
+		custom.Retryable = func(err error) bool { return errors.Is(err, busy) }
+		calls := 0
+		err := conn.Transaction(t.Context(), update(&calls), contract.WithRetry(custom))
+		require.NoError(t, err)
+		require.Equal(t, 2, calls)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestConnection_RawQueriesInTransaction(t *testing.T) {
+	t.Run("UsesTransactionHandle", func(t *testing.T) {
+		gormDB, mock := setupConnTestDB(t)
+		conn := &connection{db: gormDB}
+		txErr := errors.New("abort")
+
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE dummy_models").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery("SELECT id FROM dummy_models").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectRollback()
+
+		err := conn.Transaction(t.Context(), func(tx contract.Connection) error {
+			_, err := tx.Statement(t.Context(), "UPDATE dummy_models SET name = 'foo'")
+			require.NoError(t, err)
+			_, err = tx.Select(t.Context(), "SELECT id FROM dummy_models")
+			require.NoError(t, err)
+			return txErr
+		})
+		require.ErrorIs(t, err, txErr)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("RollbackUndoesRawStatements", func(t *testing.T) {
+		conn := newSQLiteTestConn(t)
+
+		err := conn.Transaction(t.Context(), func(tx contract.Connection) error {
+			repo, err := tx.NewRepository(&TestModel{})
+			require.NoError(t, err)
+			require.NoError(t, repo.Create(t.Context(), &TestModel{Name: "repository"}))
+
+			_, err = tx.Statement(t.Context(), "INSERT INTO test_models (name) VALUES (?)", "raw")
+			require.NoError(t, err)
+
+			rows, err := tx.Select(t.Context(), "SELECT name FROM test_models")
+			require.NoError(t, err)
+			require.Len(t, rows, 2, "the transaction should see its own raw writes")
+			return errors.New("abort")
+		})
+		require.Error(t, err)
+
+		rows, err := conn.Select(t.Context(), "SELECT name FROM test_models")
+		require.NoError(t, err)
+		require.Empty(t, rows, "the rollback should undo repository and raw writes")
+	})
+}
+
+func TestConnection_NestedTransactionSQLite(t *testing.T) {
+	conn := newSQLiteTestConn(t)
+
+	create := func(tx contract.Connection, name string) error {
+		repo, err := tx.NewRepository(&TestModel{})
+		require.NoError(t, err)
+		return repo.Create(t.Context(), &TestModel{Name: name})
+	}
+
+	err := conn.Transaction(t.Context(), func(tx contract.Connection) error {
+		require.NoError(t, create(tx, "outer"))
+
+		innerErr := tx.Transaction(t.Context(), func(inner contract.Connection) error {
+			require.NoError(t, create(inner, "discarded"))
+			return errors.New("inner failure")
+		})
+		require.Error(t, innerErr)
+
+		return tx.Transaction(t.Context(), func(inner contract.Connection) error {
+			return create(inner, "kept")
+		})
+	})
+	require.NoError(t, err)
+
+	rows, err := conn.Select(t.Context(), "SELECT name FROM test_models ORDER BY id")
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	require.Equal(t, "outer", rows[0]["name"])
+	require.Equal(t, "kept", rows[1]["name"])
 }
 
 func TestConnection_RawQueries(t *testing.T) {
@@ -147,3 +439,135 @@ func TestConnection_GetConnection(t *testing.T) {
 	_, ok := rawDB.(*gorm.DB)
 	require.True(t, ok, "GetConnection should return the underlying *gorm.DB instance")
 }
+
+func TestConnection_AmbientTransaction(t *testing.T) {
+	Register()
+	conn := newSQLiteTestConn(t)
+	repo, err := conn.NewRepository(&TestModel{})
+	require.NoError(t, err)
+
+	countRows := func(ctx context.Context) int {
+		rows, err := conn.Select(ctx, "SELECT name FROM test_models")
+		require.NoError(t, err)
+		return len(rows)
+	}
+
+	t.Run("RootRepositoryJoinsTransaction", func(t *testing.T) {
+		err := db.InTransaction(t.Context(), conn, func(ctx context.Context) error {
+			require.NoError(t, repo.Create(ctx, &TestModel{Name: "repository"}))
+			require.NoError(t, repo.QueryBuilder().Create(ctx, &TestModel{Name: "builder"}))
+			_, err := conn.Statement(ctx, "INSERT INTO test_models (name) VALUES (?)", "raw")
+			require.NoError(t, err)
+
+			count, err := repo.QueryBuilder().Count(ctx)
+			require.NoError(t, err)
+			require.Equal(t, int64(3), count, "reads with the context should see the transaction's writes")
+			require.Equal(t, 3, countRows(ctx))
+			return errors.New("abort")
+		})
+		require.Error(t, err)
+		require.Zero(t, countRows(t.Context()), "the rollback should undo every write made with the context")
+	})
+
+	t.Run("RootTransactionNestsAsSavepoint", func(t *testing.T) {
+		err := db.InTransaction(t.Context(), conn, func(ctx context.Context) error {
+			require.NoError(t, repo.Create(ctx, &TestModel{Name: "outer"}))
+
+			innerErr := conn.Transaction(ctx, func(inner contract.Connection) error {
+				innerRepo, err := inner.NewRepository(&TestModel{})
+				require.NoError(t, err)
+				require.NoError(t, innerRepo.Create(ctx, &TestModel{Name: "inner"}))
+				return errors.New("inner failure")
+			})
+			require.Error(t, innerErr)
+			return nil
+		})
+		require.NoError(t, err)
+
+		rows, err := conn.Select(t.Context(), "SELECT name FROM test_models")
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		require.Equal(t, "outer", rows[0]["name"])
+	})
+
+	t.Run("SavepointsNestedThroughContextAreDistinct", func(t *testing.T) {
+		before := countRows(t.Context())
+		err := db.InTransaction(t.Context(), conn, func(ctx context.Context) error {
+			// Both savepoints are started from the ambient transaction of ctx.
+			middleErr := conn.Transaction(ctx, func(middle contract.Connection) error {
+				middleRepo, err := middle.NewRepository(&TestModel{})
+				require.NoError(t, err)
+				require.NoError(t, middleRepo.Create(ctx, &TestModel{Name: "middle"}))
+
+				innerErr := conn.Transaction(ctx, func(contract.Connection) error {
+					return errors.New("inner failure")
+				})
+				require.Error(t, innerErr)
+				return errors.New("middle failure")
+			})
+			require.Error(t, middleErr)
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, before, countRows(t.Context()), "the middle savepoint should be rolled back to, not the inner one")
+	})
+
+	t.Run("OtherDatabaseIsNotJoined", func(t *testing.T) {
+		other := newSQLiteTestConn(t)
+		err := db.InTransaction(t.Context(), other, func(ctx context.Context) error {
+			_, err := conn.Statement(ctx, "INSERT INTO test_models (name) VALUES (?)", "independent")
+			return errors.Join(err, errors.New("abort"))
+		})
+		require.Error(t, err)
+		require.Equal(t, 2, countRows(t.Context()), "a transaction of another database should not be joined")
+	})
+}
+
+func TestConnection_Stats(t *testing.T) {
+	t.Run("PoolAndPing", func(t *testing.T) {
+		conn, err := (&Adapter{}).Connect(&config.Config{
+			Driver:       testSQLiteDriver,
+			DSN:          filepath.Join(t.TempDir(), "stats.db"),
+			MaxOpenConns: 4,
+		})
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = conn.Close() })
+
+		stats := conn.Stats()
+		require.Len(t, stats.Pools, 1)
+		pool := stats.Pools[0]
+		require.Equal(t, "primary", pool.Name)
+		require.Equal(t, 4, pool.MaxOpenConnections)
+		require.True(t, pool.LastPingAt.IsZero(), "no ping has been recorded yet")
+
+		require.NoError(t, conn.Ping(t.Context()))
+		err = conn.Transaction(t.Context(), func(tx contract.Connection) error {
+			pool := tx.Stats().Pools[0]
+			require.Equal(t, 1, pool.InUse, "the transaction holds a connection")
+			require.False(t, pool.LastPingAt.IsZero(), "transaction connections share the ping record")
+			return nil
+		})
+		require.NoError(t, err)
+
+		pool = conn.Stats().Pools[0]
+		require.Zero(t, pool.InUse)
+		require.Positive(t, pool.OpenConnections)
+		require.Equal(t, pool.OpenConnections, pool.Idle)
+		require.False(t, pool.LastPingAt.IsZero())
+		require.Positive(t, pool.LastPingLatency)
+		require.NoError(t, pool.LastPingError)
+		require.True(t, conn.Stats().Healthy())
+	})
+
+	t.Run("PingError", func(t *testing.T) {
+		gormDB, mock := setupConnTestDB(t)
+		conn := &connection{db: gormDB, health: &poolstats.Health{}}
+		pingErr := errors.New("ping failed")
+		mock.ExpectPing().WillReturnError(pingErr)
+
+		require.ErrorIs(t, conn.Ping(t.Context()), pingErr)
+		stats := conn.Stats()
+		require.ErrorIs(t, stats.Pools[0].LastPingError, pingErr)
+		require.False(t, stats.Healthy())
+	})
+}
@@ -0,0 +1,246 @@
+package gorm
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/next-trace/scg-database/contract"
+	"github.com/next-trace/scg-database/db"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	_ "modernc.org/sqlite" // pure-Go database/sql driver of the sqlite-pure dialect
+)
+
+type (
+	// Dialect is a GORM dialect served by the adapter as the driver `gorm:<Name>`.
+	Dialect struct {
+		// Name is the dialect part of the driver name.
+		Name string
+		// Open creates the dialector of a DSN.
+		Open func(dsn string) gorm.Dialector
+		// OpenConn creates a dialector over an existing pool, which is needed for
+		// DSNs with secret references. Nil when the dialect cannot do it.
+		OpenConn func(conn gorm.ConnPool, dsn string) gorm.Dialector
+		// SQLDriver is the database/sql driver used to open such pools.
+		SQLDriver string
+		// MigrationDriver is the driver the migration package uses; empty when
+		// the dialect does not support migrations.
+		MigrationDriver string
+		// Truncate returns the statement that empties a table.
+		Truncate func(table string) string
+		// Capabilities lists the optional features of the dialect.
+		Capabilities []contract.Capability
+	}
+
+	// DialectOption configures a dialect registered with RegisterDialect.
+	DialectOption func(*Dialect)
+)
+
+// pureSQLiteDriverName is the database/sql driver registered by modernc.org/sqlite.
+const pureSQLiteDriverName = "sqlite"
+
+var (
+	sqliteCapabilities = []contract.Capability{
+		contract.CapabilitySavepoints,
+		contract.CapabilityReturning,
+		contract.CapabilityUpsert,
+		contract.CapabilityJSON,
+	}
+
+	dialectsMu sync.RWMutex
+	dialects   = map[string]*Dialect{
+		DriverMySQL: {
+			Name:            DriverMySQL,
+			Open:            mysql.Open,
+			OpenConn:        openMySQLConn,
+			SQLDriver:       mysql.DefaultDriverName,
+			MigrationDriver: "mysql",
+			Truncate:        truncateTable,
+			// MySQL has no RETURNING clause.
+			Capabilities: []contract.Capability{
+				contract.CapabilitySavepoints,
+				contract.CapabilityUpsert,
+				contract.CapabilityJSON,
+				contract.CapabilityRowLocking,
+			},
+		},
+		DriverPostgres: {
+			Name:            DriverPostgres,
+			Open:            postgres.Open,
+			OpenConn:        openPostgresConn,
+			SQLDriver:       "pgx",
+			MigrationDriver: "postgres",
+			Truncate:        truncateTableCascade,
+			Capabilities: []contract.Capability{
+				contract.CapabilitySavepoints,
+				contract.CapabilityReturning,
+				contract.CapabilityUpsert,
+				contract.CapabilityJSON,
+				contract.CapabilityRowLocking,
+			},
+		},
+		DriverSQLite: {
+			Name:            DriverSQLite,
+			Open:            sqlite.Open,
+			OpenConn:        openSQLiteConn,
+			SQLDriver:       sqlite.DriverName,
+			MigrationDriver: "sqlite3",
+			Truncate:        deleteFromTable,
+			// SQLite has no row-level locks.
+			Capabilities: sqliteCapabilities,
+		},
+		DriverSQLitePure: {
+			Name:            DriverSQLitePure,
+			Open:            openSQLitePure,
+			OpenConn:        openSQLitePureConn,
+			SQLDriver:       pureSQLiteDriverName,
+			MigrationDriver: "sqlite",
+			Truncate:        deleteFromTable,
+			Capabilities:    sqliteCapabilities,
+		},
+	}
+)
+
+// WithDialectConnPool sets how the dialect is created over an existing pool.
+func WithDialectConnPool(openConn func(conn gorm.ConnPool, dsn string) gorm.Dialector) DialectOption {
+	return func(d *Dialect) {
+		d.OpenConn = openConn
+	}
+}
+
+// WithDialectSQLDriver sets the database/sql driver of the dialect. It defaults
+// to the dialect name.
+func WithDialectSQLDriver(name string) DialectOption {
+	return func(d *Dialect) {
+		d.SQLDriver = name
+	}
+}
+
+// WithDialectMigrationDriver enables migrations for the dialect with the given
+// driver of the migration package, such as "postgres" for a Postgres-compatible database.
+func WithDialectMigrationDriver(name string) DialectOption {
+	return func(d *Dialect) {
+		d.MigrationDriver = name
+	}
+}
+
+// WithDialectTruncate sets the statement that empties a table. It defaults to
+// TRUNCATE TABLE.
+func WithDialectTruncate(truncate func(table string) string) DialectOption {
+	return func(d *Dialect) {
+		d.Truncate = truncate
+	}
+}
+
+// WithDialectCapabilities declares the optional features of the dialect;
+// CapabilityMigrations is implied by WithDialectMigrationDriver.
+func WithDialectCapabilities(capabilities ...contract.Capability) DialectOption {
+	return func(d *Dialect) {
+		d.Capabilities = capabilities
+	}
+}
+
+// RegisterDialect makes the driver `gorm:<name>` available to db.Connect,
+// using open to create the dialector of a DSN. Registering a name again
+// replaces the previous dialect, built-in ones included.
+//
+//	gormadapter.RegisterDialect("clickhouse", clickhouse.Open,
+//		gormadapter.WithDialectCapabilities(contract.CapabilityJSON))
+func RegisterDialect(name string, open func(dsn string) gorm.Dialector, opts ...DialectOption) error {
+	if name == "" || strings.Contains(name, ":") {
+		return fmt.Errorf("invalid gorm dialect name %q", name)
+	}
+	if open == nil {
+		return errors.New("cannot register a gorm dialect without a dialector factory")
+	}
+
+	dialect := &Dialect{Name: name, Open: open, SQLDriver: name, Truncate: truncateTable}
+	for _, opt := range opts {
+		opt(dialect)
+	}
+
+	dialectsMu.Lock()
+	dialects[name] = dialect
+	dialectsMu.Unlock()
+
+	return db.TryRegisterAdapter(&Adapter{}, "gorm:"+name) //nolint:wrapcheck // already a *db.Error
+}
+
+// LookupDialect returns a copy of the dialect registered under name, e.g. "postgres".
+func LookupDialect(name string) (Dialect, bool) {
+	dialectsMu.RLock()
+	defer dialectsMu.RUnlock()
+	dialect, ok := dialects[name]
+	if !ok {
+		return Dialect{}, false
+	}
+	return *dialect, true
+}
+
+// Dialects returns the names of the registered dialects, sorted.
+func Dialects() []string {
+	dialectsMu.RLock()
+	defer dialectsMu.RUnlock()
+	names := make([]string, 0, len(dialects))
+	for name := range dialects {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Supports reports whether the dialect supports capability.
+func (d *Dialect) Supports(capability contract.Capability) bool {
+	if capability == contract.CapabilityMigrations {
+		return d.MigrationDriver != ""
+	}
+	return slices.Contains(d.Capabilities, capability)
+}
+
+// info returns the dialect specifics shared with the other packages.
+func (d *Dialect) info() contract.DialectInfo {
+	return contract.DialectInfo{MigrationDriver: d.MigrationDriver, TruncateStatement: d.Truncate}
+}
+
+// --- Built-in dialect helpers ---
+
+// openMySQLConn passes the DSN along for settings such as the loc parameter.
+func openMySQLConn(conn gorm.ConnPool, dsn string) gorm.Dialector {
+	return mysql.New(mysql.Config{Conn: conn, DSN: dsn})
+}
+
+func openPostgresConn(conn gorm.ConnPool, _ string) gorm.Dialector {
+	return postgres.New(postgres.Config{Conn: conn})
+}
+
+func openSQLiteConn(conn gorm.ConnPool, _ string) gorm.Dialector {
+	return sqlite.New(sqlite.Config{Conn: conn})
+}
+
+// openSQLitePure uses the GORM SQLite dialector over the pure-Go driver, which
+// needs no cgo.
+func openSQLitePure(dsn string) gorm.Dialector {
+	return sqlite.New(sqlite.Config{DriverName: pureSQLiteDriverName, DSN: dsn})
+}
+
+func openSQLitePureConn(conn gorm.ConnPool, _ string) gorm.Dialector {
+	return sqlite.New(sqlite.Config{DriverName: pureSQLiteDriverName, Conn: conn})
+}
+
+func truncateTable(table string) string {
+	return "TRUNCATE TABLE " + table
+}
+
+func truncateTableCascade(table string) string {
+	return "TRUNCATE TABLE " + table + " RESTART IDENTITY CASCADE"
+}
+
+func deleteFromTable(table string) string {
+	return "DELETE FROM " + table
+}
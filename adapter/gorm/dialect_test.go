@@ -0,0 +1,135 @@
+package gorm
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/next-trace/scg-database/config"
+	"github.com/next-trace/scg-database/contract"
+	"github.com/next-trace/scg-database/db"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestRegisterDialect(t *testing.T) {
+	var opened []string
+	require.NoError(t, RegisterDialect("lite", func(dsn string) gorm.Dialector {
+		opened = append(opened, dsn)
+		return openTestSQLite(dsn)
+	},
+		WithDialectSQLDriver(sqlite.DriverName),
+		WithDialectMigrationDriver("sqlite3"),
+		WithDialectTruncate(deleteFromTable),
+		WithDialectCapabilities(contract.CapabilitySavepoints),
+	))
+
+	dsn := filepath.Join(t.TempDir(), "lite.db")
+	conn, err := db.Connect(&config.Config{Driver: "gorm:lite", DSN: dsn})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	require.Equal(t, []string{dsn}, opened, "the registered factory creates the dialector")
+
+	require.True(t, db.Supports("gorm:lite", contract.CapabilitySavepoints))
+	require.True(t, db.Supports("gorm:lite", contract.CapabilityMigrations), "implied by the migration driver")
+	require.False(t, db.Supports("gorm:lite", contract.CapabilityRowLocking))
+
+	info, ok := db.DialectInfo("gorm:lite")
+	require.True(t, ok)
+	require.Equal(t, "sqlite3", info.MigrationDriver)
+	require.Equal(t, "DELETE FROM users", info.TruncateStatement("users"))
+
+	require.Contains(t, Dialects(), "lite")
+}
+
+func TestRegisterDialect_Defaults(t *testing.T) {
+	require.NoError(t, RegisterDialect("plain", sqlite.Open))
+
+	dialect, ok := LookupDialect("plain")
+	require.True(t, ok)
+	require.Equal(t, "plain", dialect.SQLDriver)
+	require.Equal(t, "TRUNCATE TABLE users", dialect.Truncate("users"))
+	require.False(t, dialect.Supports(contract.CapabilityMigrations))
+	require.Nil(t, dialect.OpenConn)
+
+	strategy, err := NewDialectStrategy("gorm:plain")
+	require.NoError(t, err)
+	_, err = strategy.CreateDialectorWithConn(nil, "")
+	require.EqualError(t, err, "gorm dialect plain cannot use an existing connection pool")
+}
+
+func TestRegisterDialect_Errors(t *testing.T) {
+	require.EqualError(t, RegisterDialect("", sqlite.Open), `invalid gorm dialect name ""`)
+	require.EqualError(t, RegisterDialect("a:b", sqlite.Open), `invalid gorm dialect name "a:b"`)
+	require.EqualError(t, RegisterDialect("nil", nil), "cannot register a gorm dialect without a dialector factory")
+
+	_, ok := LookupDialect("nil")
+	require.False(t, ok)
+}
+
+func TestBuiltinDialects(t *testing.T) {
+	require.Subset(t, Dialects(), []string{DriverMySQL, DriverPostgres, DriverSQLite, DriverSQLitePure})
+
+	adapter := &Adapter{}
+	tests := []struct {
+		driver, migrationDriver, truncate string
+	}{
+		{GormDriverMySQL, "mysql", "TRUNCATE TABLE users"},
+		{GormDriverPostgres, "postgres", "TRUNCATE TABLE users RESTART IDENTITY CASCADE"},
+		{GormDriverSQLite, "sqlite3", "DELETE FROM users"},
+		{GormDriverSQLitePure, "sqlite", "DELETE FROM users"},
+	}
+	for _, tt := range tests {
+		info, ok := adapter.DialectInfo(tt.driver)
+		require.True(t, ok, tt.driver)
+		require.Equal(t, tt.migrationDriver, info.MigrationDriver)
+		require.Equal(t, tt.truncate, info.TruncateStatement("users"))
+	}
+
+	_, ok := adapter.DialectInfo("gorm:oracle")
+	require.False(t, ok)
+	_, ok = adapter.DialectInfo("gorm")
+	require.False(t, ok)
+}
+
+func TestSQLitePure(t *testing.T) {
+	Register()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "dsn"), []byte(filepath.Join(dir, "pure.db")), 0o600))
+
+	// The secret reference makes the adapter open the pool itself.
+	cfg := config.Config{Driver: GormDriverSQLitePure, DSN: "secret://file/dsn"}
+	config.WithSecretProvider(config.SecretProviderFile, config.FileSecretProvider{Dir: dir})(&cfg)
+	conn, err := db.Connect(&cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	require.FileExists(t, filepath.Join(dir, "pure.db"))
+
+	ctx := t.Context()
+	require.NoError(t, conn.GetConnection().(*gorm.DB).AutoMigrate(&TestModel{}))
+	repo, err := conn.NewRepository(&TestModel{})
+	require.NoError(t, err)
+	require.NoError(t, repo.Create(ctx, &TestModel{ID: 1, Name: "first"}))
+
+	// Nested transactions use savepoints.
+	err = conn.Transaction(ctx, func(tx contract.Connection) error {
+		return tx.Transaction(ctx, func(inner contract.Connection) error {
+			innerRepo, err := inner.NewRepository(&TestModel{})
+			require.NoError(t, err)
+			return innerRepo.Create(ctx, &TestModel{ID: 2, Name: "second"})
+		})
+	})
+	require.NoError(t, err)
+
+	found, err := repo.Find(ctx, 2)
+	require.NoError(t, err)
+	require.Equal(t, "second", found.(*TestModel).Name)
+
+	err = repo.Create(ctx, &TestModel{ID: 1, Name: "duplicate"})
+	require.ErrorIs(t, err, db.ErrUniqueViolation)
+
+	require.True(t, db.Supports(GormDriverSQLitePure, contract.CapabilitySavepoints))
+	require.False(t, db.Supports(GormDriverSQLitePure, contract.CapabilityRowLocking))
+	require.Equal(t, config.DialectSQLite, config.Dialect(GormDriverSQLitePure))
+}
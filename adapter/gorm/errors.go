@@ -0,0 +1,16 @@
+package gorm
+
+import (
+	"github.com/next-trace/scg-database/adapter/internal/sqlerr"
+)
+
+// translateError classifies MySQL, Postgres and SQLite driver errors into a
+// *db.DatabaseError. Other errors, nil included, are returned unchanged.
+func translateError(err error) error {
+	return sqlerr.Translate(err)
+}
+
+// isRetryable reports whether err is a deadlock or serialization failure.
+func isRetryable(err error) bool {
+	return sqlerr.IsRetryable(err)
+}
@@ -0,0 +1,105 @@
+package gorm
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/next-trace/scg-database/config"
+	"github.com/next-trace/scg-database/db"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestTranslateError_SQLite(t *testing.T) {
+	// Each driver spells the DSN parameter that enables foreign keys differently.
+	foreignKeys := map[string]string{
+		GormDriverSQLite:     "?_foreign_keys=on",
+		GormDriverSQLitePure: "?_pragma=foreign_keys(1)",
+	}
+	for _, driver := range testSQLiteDrivers {
+		t.Run(driver, func(t *testing.T) {
+			testTranslateSQLiteErrors(t, driver, foreignKeys[driver])
+		})
+	}
+}
+
+// testTranslateSQLiteErrors checks the translation of the errors of a SQLite
+// driver; params enable foreign keys, which each driver spells differently.
+func testTranslateSQLiteErrors(t *testing.T, driver, params string) {
+	t.Helper()
+	conn, err := (&Adapter{}).Connect(&config.Config{
+		Driver: driver,
+		DSN:    filepath.Join(t.TempDir(), "errors.db") + params,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	ctx := t.Context()
+	for _, stmt := range []string{
+		"CREATE TABLE owners (id INTEGER PRIMARY KEY)",
+		"CREATE TABLE accounts (id INTEGER PRIMARY KEY, email TEXT NOT NULL UNIQUE, " +
+			"balance INTEGER CONSTRAINT balance_positive CHECK (balance >= 0), owner_id INTEGER REFERENCES owners(id))",
+		"INSERT INTO accounts (id, email, balance) VALUES (1, 'a@example.com', 0)",
+	} {
+		_, err := conn.Statement(ctx, stmt)
+		require.NoError(t, err)
+	}
+
+	tests := []struct {
+		name   string
+		query  string
+		kind   error
+		expect db.DatabaseError
+	}{
+		{
+			name:   "Unique",
+			query:  "INSERT INTO accounts (email, balance) VALUES ('a@example.com', 0)",
+			kind:   db.ErrUniqueViolation,
+			expect: db.DatabaseError{Table: "accounts", Column: "email"},
+		},
+		{
+			name:   "PrimaryKey",
+			query:  "INSERT INTO accounts (id, email, balance) VALUES (1, 'b@example.com', 0)",
+			kind:   db.ErrUniqueViolation,
+			expect: db.DatabaseError{Table: "accounts", Column: "id"},
+		},
+		{
+			name:   "NotNull",
+			query:  "INSERT INTO accounts (email, balance) VALUES (NULL, 0)",
+			kind:   db.ErrNotNullViolation,
+			expect: db.DatabaseError{Table: "accounts", Column: "email"},
+		},
+		{
+			name:   "Check",
+			query:  "INSERT INTO accounts (email, balance) VALUES ('c@example.com', -1)",
+			kind:   db.ErrCheckViolation,
+			expect: db.DatabaseError{Constraint: "balance_positive"},
+		},
+		{
+			name:  "ForeignKey",
+			query: "INSERT INTO accounts (email, balance, owner_id) VALUES ('d@example.com', 0, 42)",
+			kind:  db.ErrForeignKeyViolation,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := conn.Statement(ctx, tt.query)
+			require.ErrorIs(t, err, tt.kind)
+
+			var dbErr *db.DatabaseError
+			require.ErrorAs(t, err, &dbErr)
+			require.Equal(t, tt.expect.Constraint, dbErr.Constraint)
+			require.Equal(t, tt.expect.Table, dbErr.Table)
+			require.Equal(t, tt.expect.Column, dbErr.Column)
+		})
+	}
+
+	t.Run("Repository", func(t *testing.T) {
+		repo, err := conn.NewRepository(&TestModel{})
+		require.NoError(t, err)
+		require.NoError(t, conn.GetConnection().(*gorm.DB).AutoMigrate(&TestModel{}))
+		require.NoError(t, repo.Create(ctx, &TestModel{ID: 7, Name: "first"}))
+		err = repo.Create(ctx, &TestModel{ID: 7, Name: "second"})
+		require.ErrorIs(t, err, db.ErrUniqueViolation)
+	})
+}
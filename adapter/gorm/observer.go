@@ -0,0 +1,119 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/next-trace/scg-database/config"
+	"github.com/next-trace/scg-database/contract"
+	"github.com/next-trace/scg-database/db"
+	"gorm.io/gorm"
+)
+
+const (
+	// queryObserverName is the name the observer plugin is registered under.
+	queryObserverName = "scg:query_observer"
+	// queryStartKey stores the start time of a statement on the gorm instance.
+	queryStartKey = "scg:query_start"
+)
+
+type (
+	// queryObserver is a GORM plugin that reports every statement to the
+	// observers registered with db.WithQueryObserver.
+	queryObserver struct {
+		observers []contract.QueryObserver
+		redact    func(arg any) any
+	}
+
+	// callbackRegistrar is the part of a GORM callback used to register a function.
+	callbackRegistrar interface {
+		Register(name string, fn func(*gorm.DB)) error
+	}
+)
+
+// Ensure the implementation satisfies the interface at compile time.
+var _ gorm.Plugin = (*queryObserver)(nil)
+
+// newQueryObserver returns the observer plugin for cfg, or nil when no observer is registered.
+func newQueryObserver(cfg *config.Config) *queryObserver {
+	observers := db.QueryObservers(cfg)
+	if len(observers) == 0 {
+		return nil
+	}
+	return &queryObserver{observers: observers, redact: db.QueryArgRedactor(cfg)}
+}
+
+// queryObserverOf returns the observer plugin registered on gdb, if any.
+func queryObserverOf(gdb *gorm.DB) *queryObserver {
+	observer, _ := gdb.Config.Plugins[queryObserverName].(*queryObserver)
+	return observer
+}
+
+// Name implements gorm.Plugin.
+func (o *queryObserver) Name() string {
+	return queryObserverName
+}
+
+// Initialize registers timing callbacks around every GORM processor.
+func (o *queryObserver) Initialize(gdb *gorm.DB) error {
+	callbacks := gdb.Callback()
+	processors := []struct {
+		operation     string
+		before, after callbackRegistrar
+	}{
+		{"create", callbacks.Create().Before("gorm:create"), callbacks.Create().After("gorm:create")},
+		{"query", callbacks.Query().Before("gorm:query"), callbacks.Query().After("gorm:query")},
+		{"update", callbacks.Update().Before("gorm:update"), callbacks.Update().After("gorm:update")},
+		{"delete", callbacks.Delete().Before("gorm:delete"), callbacks.Delete().After("gorm:delete")},
+		{"row", callbacks.Row().Before("gorm:row"), callbacks.Row().After("gorm:row")},
+		{"raw", callbacks.Raw().Before("gorm:raw"), callbacks.Raw().After("gorm:raw")},
+	}
+
+	for _, p := range processors {
+		if err := p.before.Register(queryObserverName+":before_"+p.operation, o.start); err != nil {
+			return fmt.Errorf("failed to register query observer: %w", err)
+		}
+		if err := p.after.Register(queryObserverName+":after_"+p.operation, o.finish(p.operation)); err != nil {
+			return fmt.Errorf("failed to register query observer: %w", err)
+		}
+	}
+	return nil
+}
+
+func (o *queryObserver) start(gdb *gorm.DB) {
+	gdb.InstanceSet(queryStartKey, time.Now())
+}
+
+func (o *queryObserver) finish(operation string) func(*gorm.DB) {
+	return func(gdb *gorm.DB) {
+		var duration time.Duration
+		if start, ok := gdb.InstanceGet(queryStartKey); ok {
+			duration = time.Since(start.(time.Time)) //nolint:forcetypeassert // only start stores this key
+		}
+		o.observe(gdb.Statement.Context, contract.QueryEvent{
+			Operation:    operation,
+			SQL:          gdb.Statement.SQL.String(),
+			Args:         gdb.Statement.Vars,
+			Duration:     duration,
+			RowsAffected: gdb.RowsAffected,
+			Err:          translateError(gdb.Error),
+		})
+	}
+}
+
+// observe redacts the event arguments and notifies every observer.
+func (o *queryObserver) observe(ctx context.Context, event contract.QueryEvent) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	args := make([]any, len(event.Args))
+	for i, arg := range event.Args {
+		args[i] = o.redact(arg)
+	}
+	event.Args = args
+
+	for _, observer := range o.observers {
+		observer.ObserveQuery(ctx, event)
+	}
+}
@@ -0,0 +1,142 @@
+package gorm
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/next-trace/scg-database/config"
+	"github.com/next-trace/scg-database/contract"
+	"github.com/next-trace/scg-database/db"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type (
+	// recordingObserver keeps every observed event.
+	recordingObserver struct {
+		mu     sync.Mutex
+		events []contract.QueryEvent
+	}
+)
+
+func (r *recordingObserver) ObserveQuery(_ context.Context, event contract.QueryEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+// take returns the recorded events and resets the recorder.
+func (r *recordingObserver) take() []contract.QueryEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	events := r.events
+	r.events = nil
+	return events
+}
+
+// newObservedConn connects to SQLite with the given options and migrates test_models.
+func newObservedConn(t *testing.T, opts ...config.Option) contract.Connection {
+	t.Helper()
+	cfg := &config.Config{Driver: testSQLiteDriver, DSN: filepath.Join(t.TempDir(), "observed.db")}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	conn, err := (&Adapter{}).Connect(cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	require.NoError(t, conn.GetConnection().(*gorm.DB).AutoMigrate(&TestModel{}))
+	return conn
+}
+
+func TestQueryObserver(t *testing.T) {
+	Register()
+	observer := &recordingObserver{}
+	conn := newObservedConn(t, db.WithQueryObserver(observer))
+	ctx := t.Context()
+	observer.take() // discard migration statements
+
+	repo, err := conn.NewRepository(&TestModel{})
+	require.NoError(t, err)
+
+	t.Run("Repository", func(t *testing.T) {
+		require.NoError(t, repo.Create(ctx, &TestModel{Name: "secret-name"}))
+		events := observer.take()
+		require.Len(t, events, 1)
+		require.Equal(t, "create", events[0].Operation)
+		require.Contains(t, events[0].SQL, "INSERT INTO `test_models`")
+		require.Contains(t, events[0].Args, db.RedactedArg)
+		require.NotContains(t, events[0].Args, "secret-name", "arguments should be redacted")
+		require.Equal(t, int64(1), events[0].RowsAffected)
+		require.NoError(t, events[0].Err)
+		require.Positive(t, events[0].Duration)
+
+		_, err := repo.Where("name = ?", "secret-name").Get(ctx)
+		require.NoError(t, err)
+		events = observer.take()
+		require.Len(t, events, 1)
+		require.Equal(t, "query", events[0].Operation)
+		require.Equal(t, []any{db.RedactedArg}, events[0].Args)
+	})
+
+	t.Run("QueryBuilder", func(t *testing.T) {
+		_, err := repo.QueryBuilder().Count(ctx)
+		require.NoError(t, err)
+		events := observer.take()
+		require.Len(t, events, 1)
+		require.Equal(t, "query", events[0].Operation)
+		require.Contains(t, events[0].SQL, "count(*)")
+	})
+
+	t.Run("RawMethods", func(t *testing.T) {
+		_, err := conn.Select(ctx, "SELECT name FROM test_models WHERE id > ?", 0)
+		require.NoError(t, err)
+		_, err = conn.Statement(ctx, "UPDATE test_models SET name = ?", "renamed")
+		require.NoError(t, err)
+
+		events := observer.take()
+		require.Len(t, events, 2)
+		require.Equal(t, "row", events[0].Operation)
+		require.Equal(t, "SELECT name FROM test_models WHERE id > ?", events[0].SQL)
+		require.Equal(t, "exec", events[1].Operation)
+		require.Equal(t, []any{db.RedactedArg}, events[1].Args)
+		require.Equal(t, int64(1), events[1].RowsAffected)
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		_, err := conn.Statement(ctx, "INSERT INTO test_models (id, name) VALUES (?, ?)", 1, "duplicate")
+		require.ErrorIs(t, err, db.ErrUniqueViolation)
+		events := observer.take()
+		require.Len(t, events, 1)
+		require.ErrorIs(t, events[0].Err, db.ErrUniqueViolation)
+
+		_, err = repo.QueryBuilder().Where("missing_column = ?", 1).Count(ctx)
+		require.Error(t, err)
+		events = observer.take()
+		require.Len(t, events, 1)
+		require.Error(t, events[0].Err)
+	})
+}
+
+func TestQueryObserver_CustomRedactor(t *testing.T) {
+	observer := &recordingObserver{}
+	conn := newObservedConn(t,
+		db.WithQueryObserver(observer),
+		db.WithQueryArgRedactor(func(arg any) any { return arg }),
+	)
+	observer.take()
+
+	_, err := conn.Select(t.Context(), "SELECT name FROM test_models WHERE name = ?", "visible")
+	require.NoError(t, err)
+	events := observer.take()
+	require.Len(t, events, 1)
+	require.Equal(t, []any{"visible"}, events[0].Args)
+}
+
+func TestQueryObserver_NotRegistered(t *testing.T) {
+	conn := newObservedConn(t)
+	require.Nil(t, queryObserverOf(conn.GetConnection().(*gorm.DB)))
+	_, err := conn.Statement(t.Context(), "DELETE FROM test_models")
+	require.NoError(t, err)
+}
@@ -2,10 +2,12 @@ package gorm
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 
 	"github.com/next-trace/scg-database/contract"
+	"github.com/next-trace/scg-database/db"
 	"gorm.io/gorm"
 )
 
@@ -36,97 +38,68 @@ func newGormQueryBuilder(model contract.Model, connection any) *gormQueryBuilder
 	}
 
 	return &gormQueryBuilder{
-		db:    gormDB.Model(model),
+		db:    gormDB.Model(model).Session(&gorm.Session{}),
 		model: model,
 	}
 }
 
+// with returns a query builder of the same model on tx. The session makes tx
+// safe to share: every call on it works on a copy of its statement, so
+// builders derived from the same one do not see each other's clauses.
+func (q *gormQueryBuilder) with(tx *gorm.DB) contract.QueryBuilder {
+	return &gormQueryBuilder{db: tx.Session(&gorm.Session{}), model: q.model}
+}
+
 // Query building methods
 
 func (q *gormQueryBuilder) Select(columns ...string) contract.QueryBuilder {
-	return &gormQueryBuilder{
-		db:    q.db.Select(columns),
-		model: q.model,
-	}
+	return q.with(q.db.Select(columns))
 }
 
 func (q *gormQueryBuilder) Where(condition string, args ...any) contract.QueryBuilder {
-	return &gormQueryBuilder{
-		db:    q.db.Where(condition, args...),
-		model: q.model,
-	}
+	return q.with(q.db.Where(condition, args...))
 }
 
 func (q *gormQueryBuilder) WhereIn(column string, values []any) contract.QueryBuilder {
-	return &gormQueryBuilder{
-		db:    q.db.Where(fmt.Sprintf("%s IN ?", column), values),
-		model: q.model,
-	}
+	return q.with(q.db.Where(fmt.Sprintf("%s IN ?", column), values))
 }
 
 func (q *gormQueryBuilder) WhereNotIn(column string, values []any) contract.QueryBuilder {
-	return &gormQueryBuilder{
-		db:    q.db.Where(fmt.Sprintf("%s NOT IN ?", column), values),
-		model: q.model,
-	}
+	return q.with(q.db.Where(fmt.Sprintf("%s NOT IN ?", column), values))
 }
 
 func (q *gormQueryBuilder) WhereNull(column string) contract.QueryBuilder {
-	return &gormQueryBuilder{
-		db:    q.db.Where(fmt.Sprintf("%s IS NULL", column)),
-		model: q.model,
-	}
+	return q.with(q.db.Where(fmt.Sprintf("%s IS NULL", column)))
 }
 
 func (q *gormQueryBuilder) WhereNotNull(column string) contract.QueryBuilder {
-	return &gormQueryBuilder{
-		db:    q.db.Where(fmt.Sprintf("%s IS NOT NULL", column)),
-		model: q.model,
-	}
+	return q.with(q.db.Where(fmt.Sprintf("%s IS NOT NULL", column)))
 }
 
 func (q *gormQueryBuilder) WhereBetween(column string, start, end any) contract.QueryBuilder {
-	return &gormQueryBuilder{
-		db:    q.db.Where(fmt.Sprintf("%s BETWEEN ? AND ?", column), start, end),
-		model: q.model,
-	}
+	return q.with(q.db.Where(fmt.Sprintf("%s BETWEEN ? AND ?", column), start, end))
 }
 
 func (q *gormQueryBuilder) OrWhere(condition string, args ...any) contract.QueryBuilder {
-	return &gormQueryBuilder{
-		db:    q.db.Or(condition, args...),
-		model: q.model,
-	}
+	return q.with(q.db.Or(condition, args...))
 }
 
 // Join methods
 
 func (q *gormQueryBuilder) Join(table, condition string) contract.QueryBuilder {
-	return &gormQueryBuilder{
-		db:    q.db.Joins(fmt.Sprintf("JOIN %s ON %s", table, condition)),
-		model: q.model,
-	}
+	return q.with(q.db.Joins(fmt.Sprintf("JOIN %s ON %s", table, condition)))
 }
 
 func (q *gormQueryBuilder) LeftJoin(table, condition string) contract.QueryBuilder {
-	return &gormQueryBuilder{
-		db:    q.db.Joins(fmt.Sprintf("LEFT JOIN %s ON %s", table, condition)),
-		model: q.model,
-	}
+	return q.with(q.db.Joins(fmt.Sprintf("LEFT JOIN %s ON %s", table, condition)))
 }
 
 func (q *gormQueryBuilder) RightJoin(table, condition string) contract.QueryBuilder {
-	return &gormQueryBuilder{
-		db:    q.db.Joins(fmt.Sprintf("RIGHT JOIN %s ON %s", table, condition)),
-		model: q.model,
-	}
+	return q.with(q.db.Joins(fmt.Sprintf("RIGHT JOIN %s ON %s", table, condition)))
 }
 
 func (q *gormQueryBuilder) InnerJoin(table, condition string) contract.QueryBuilder {
-	return &gormQueryBuilder{
-		db:    q.db.Joins(fmt.Sprintf("INNER JOIN %s ON %s", table, condition)),
-		model: q.model,
-	}
+	return q.with(q.db.Joins(fmt.Sprintf("INNER JOIN %s ON %s", table, condition)))
 }
 
 // Ordering and grouping
@@ -138,24 +111,15 @@ func (q *gormQueryBuilder) OrderBy(column, direction string) contract.QueryBuild
 		direction = OrderDirectionASC
 	}
 
-	return &gormQueryBuilder{
-		db:    q.db.Order(fmt.Sprintf("%s %s", column, direction)),
-		model: q.model,
-	}
+	return q.with(q.db.Order(fmt.Sprintf("%s %s", column, direction)))
 }
 
 func (q *gormQueryBuilder) GroupBy(columns ...string) contract.QueryBuilder {
-	return &gormQueryBuilder{
-		db:    q.db.Group(strings.Join(columns, ", ")),
-		model: q.model,
-	}
+	return q.with(q.db.Group(strings.Join(columns, ", ")))
 }
 
 func (q *gormQueryBuilder) Having(condition string, args ...any) contract.QueryBuilder {
-	return &gormQueryBuilder{
-		db:    q.db.Having(condition, args...),
-		model: q.model,
-	}
+	return q.with(q.db.Having(condition, args...))
 }
 
 // Limiting and pagination
@@ -164,33 +128,24 @@ func (q *gormQueryBuilder) Limit(limit int) contract.QueryBuilder {
 	if limit < 0 {
 		return q
 	}
-	return &gormQueryBuilder{
-		db:    q.db.Limit(limit),
-		model: q.model,
-	}
+	return q.with(q.db.Limit(limit))
 }
 
 func (q *gormQueryBuilder) Offset(offset int) contract.QueryBuilder {
 	if offset < 0 {
 		return q
 	}
-	return &gormQueryBuilder{
-		db:    q.db.Offset(offset),
-		model: q.model,
-	}
+	return q.with(q.db.Offset(offset))
 }
 
 // Relationships
 
 func (q *gormQueryBuilder) With(relations ...string) contract.QueryBuilder {
-	db := q.db
+	tx := q.db
 	for _, relation := range relations {
-		db = db.Preload(relation)
-	}
-	return &gormQueryBuilder{
-		db:    db,
-		model: q.model,
+		tx = tx.Preload(relation)
 	}
+	return q.with(tx)
 }
 
 func (q *gormQueryBuilder) WithCount(...string) contract.QueryBuilder {
@@ -202,36 +157,36 @@ func (q *gormQueryBuilder) WithCount(...string) contract.QueryBuilder {
 // Scopes and advanced features
 
 func (q *gormQueryBuilder) Scoped() contract.QueryBuilder {
-	return &gormQueryBuilder{
-		db:    q.db.Scopes(),
-		model: q.model,
-	}
+	return q.with(q.db.Scopes())
 }
 
 func (q *gormQueryBuilder) Unscoped() contract.QueryBuilder {
-	return &gormQueryBuilder{
-		db:    q.db.Unscoped(),
-		model: q.model,
-	}
+	return q.with(q.db.Unscoped())
 }
 
 // Execution methods
 
 func (q *gormQueryBuilder) Find(ctx context.Context, dest any) error {
-	return q.db.WithContext(ctx).Find(dest).Error
+	return translateError(withContext(ctx, q.db).Find(dest).Error)
 }
 
+// First reads the first matching row into dest. A missing row fails with an
+// error matching both db.ErrRecordNotFound and gorm.ErrRecordNotFound.
 func (q *gormQueryBuilder) First(ctx context.Context, dest any) error {
-	return q.db.WithContext(ctx).First(dest).Error
+	err := translateError(withContext(ctx, q.db).First(dest).Error)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("%w: %w", db.ErrRecordNotFound, err)
+	}
+	return err
 }
 
 func (q *gormQueryBuilder) Get(ctx context.Context, dest any) error {
-	return q.db.WithContext(ctx).Find(dest).Error
+	return translateError(withContext(ctx, q.db).Find(dest).Error)
 }
 
 func (q *gormQueryBuilder) Count(ctx context.Context) (int64, error) {
 	var count int64
-	err := q.db.WithContext(ctx).Count(&count).Error
+	err := translateError(withContext(ctx, q.db).Count(&count).Error)
 	return count, err
 }
 
@@ -243,28 +198,25 @@ func (q *gormQueryBuilder) Exists(ctx context.Context) (bool, error) {
 // Mutation methods
 
 func (q *gormQueryBuilder) Create(ctx context.Context, value any) error {
-	return q.db.WithContext(ctx).Create(value).Error
+	return translateError(withContext(ctx, q.db).Create(value).Error)
 }
 
 func (q *gormQueryBuilder) Update(ctx context.Context, values any) error {
-	return q.db.WithContext(ctx).Updates(values).Error
+	return translateError(withContext(ctx, q.db).Updates(values).Error)
 }
 
 func (q *gormQueryBuilder) Delete(ctx context.Context) error {
-	return q.db.WithContext(ctx).Delete(q.model).Error
+	return translateError(withContext(ctx, q.db).Delete(q.model).Error)
 }
 
 // Raw query methods
 
 func (q *gormQueryBuilder) Raw(sql string, args ...any) contract.QueryBuilder {
-	return &gormQueryBuilder{
-		db:    q.db.Raw(sql, args...),
-		model: q.model,
-	}
+	return q.with(q.db.Raw(sql, args...))
 }
 
 func (q *gormQueryBuilder) Exec(ctx context.Context, sql string, args ...any) error {
-	return q.db.WithContext(ctx).Exec(sql, args...).Error
+	return translateError(withContext(ctx, q.db).Exec(sql, args...).Error)
 }
 
 // Utility methods
@@ -276,10 +228,7 @@ func (q *gormQueryBuilder) ToSQL() (sql string, args []any, err error) {
 }
 
 func (q *gormQueryBuilder) Clone() contract.QueryBuilder {
-	return &gormQueryBuilder{
-		db:    q.db.Session(&gorm.Session{}),
-		model: q.model,
-	}
+	return q.with(q.db.Session(&gorm.Session{}))
 }
 
 func (q *gormQueryBuilder) Reset() contract.QueryBuilder {
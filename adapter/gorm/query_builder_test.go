@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/next-trace/scg-database/config"
+	"github.com/next-trace/scg-database/db"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"gorm.io/gorm"
@@ -16,7 +17,7 @@ func TestQueryBuilder(t *testing.T) {
 
 	// Setup test database
 	cfg := config.Config{
-		Driver: "gorm:sqlite",
+		Driver: testSQLiteDriver,
 		DSN:    ":memory:",
 	}
 
@@ -71,6 +72,16 @@ func TestQueryBuilder(t *testing.T) {
 		assert.False(t, exists)
 	})
 
+	t.Run("Derived builders are independent", func(t *testing.T) {
+		named := qb.Where("name = ?", "test")
+		_, err := named.Where("id < ?", 0).Count(t.Context())
+		require.NoError(t, err)
+
+		count, err := named.Count(t.Context())
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), count, "conditions of a derived builder do not leak into its parent")
+	})
+
 	t.Run("Clone functionality", func(t *testing.T) {
 		qb2 := qb.Where("name = ?", "test")
 		qb3 := qb2.Clone()
@@ -102,7 +113,7 @@ func TestQueryBuilderFactory(t *testing.T) {
 	t.Run("Create query builder", func(t *testing.T) {
 		// Setup test database
 		cfg := config.Config{
-			Driver: "gorm:sqlite",
+			Driver: testSQLiteDriver,
 			DSN:    ":memory:",
 		}
 
@@ -131,7 +142,7 @@ func TestQueryBuilderFactory(t *testing.T) {
 func TestQueryBuilderMethods(t *testing.T) {
 	// Setup test database
 	cfg := config.Config{
-		Driver: "gorm:sqlite",
+		Driver: testSQLiteDriver,
 		DSN:    ":memory:",
 	}
 
@@ -250,7 +261,7 @@ func TestQueryBuilderMethods(t *testing.T) {
 func TestQueryBuilderExecution(t *testing.T) {
 	// Setup test database
 	cfg := config.Config{
-		Driver: "gorm:sqlite",
+		Driver: testSQLiteDriver,
 		DSN:    ":memory:",
 	}
 
@@ -309,6 +320,13 @@ func TestQueryBuilderExecution(t *testing.T) {
 		assert.GreaterOrEqual(t, len(results), 2)
 	})
 
+	t.Run("First without match", func(t *testing.T) {
+		var result TestModel
+		err := newGormQueryBuilder(model, gormDB).Where("name = ?", "missing").First(ctx, &result)
+		assert.ErrorIs(t, err, db.ErrRecordNotFound)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
 	t.Run("Delete", func(t *testing.T) {
 		// Create fresh query builder for delete
 		deleteQB := newGormQueryBuilder(model, gormDB)
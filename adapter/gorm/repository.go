@@ -21,36 +21,43 @@ type (
 var _ contract.Repository = (*repository)(nil)
 
 func newGormRepository(database *gorm.DB, mdl contract.Model) contract.Repository {
-	return &repository{db: database.Model(mdl), mdl: mdl}
+	return &repository{db: database.Model(mdl).Session(&gorm.Session{}), mdl: mdl}
+}
+
+// with returns a repository of the same model on tx. The session makes tx safe
+// to share: every call on it works on a copy of its statement, so repositories
+// derived from the same one do not see each other's conditions.
+func (r *repository) with(tx *gorm.DB) contract.Repository {
+	return &repository{db: tx.Session(&gorm.Session{}), mdl: r.mdl}
 }
 
 // --- Query Building ---
 func (r *repository) With(relations ...string) contract.Repository {
 	tx := handleRelationshipPreload(r.db, r.mdl, relations)
-	return &repository{db: tx, mdl: r.mdl}
+	return r.with(tx)
 }
 
 func (r *repository) Where(query any, args ...any) contract.Repository {
-	return &repository{db: r.db.Where(query, args...), mdl: r.mdl}
+	return r.with(r.db.Where(query, args...))
 }
 
 func (r *repository) Unscoped() contract.Repository {
-	return &repository{db: r.db.Unscoped(), mdl: r.mdl}
+	return r.with(r.db.Unscoped())
 }
 
 func (r *repository) Limit(limit int) contract.Repository {
 	tx := validateAndApplyLimit(r.db, limit)
-	return &repository{db: tx, mdl: r.mdl}
+	return r.with(tx)
 }
 
 func (r *repository) Offset(offset int) contract.Repository {
 	tx := validateAndApplyOffset(r.db, offset)
-	return &repository{db: tx, mdl: r.mdl}
+	return r.with(tx)
 }
 
 func (r *repository) OrderBy(column, direction string) contract.Repository {
 	tx := applyOrderBy(r.db, column, direction)
-	return &repository{db: tx, mdl: r.mdl}
+	return r.with(tx)
 }
 
 // --- Helper Functions ---
@@ -67,7 +74,7 @@ func (r *repository) Find(ctx context.Context, id any) (contract.Model, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to create entity from model: %w", err)
 	}
-	err = r.db.WithContext(ctx).First(entity, id).Error
+	err = translateError(withContext(ctx, r.db).First(entity, id).Error)
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, nil
 	}
@@ -84,7 +91,7 @@ func (r *repository) First(ctx context.Context) (contract.Model, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to create entity from model: %w", err)
 	}
-	err = r.db.WithContext(ctx).First(entity).Error
+	err = translateError(withContext(ctx, r.db).First(entity).Error)
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, nil
 	}
@@ -99,14 +106,14 @@ func (r *repository) FirstOrFail(ctx context.Context) (contract.Model, error) {
 func (r *repository) Get(ctx context.Context) ([]contract.Model, error) {
 	// Use reflection helper with dependency injection
 	queryExecutor := func(dest interface{}) error {
-		return r.db.WithContext(ctx).Find(dest).Error
+		return translateError(withContext(ctx, r.db).Find(dest).Error)
 	}
 
 	return executeQueryAndConvertToModels(r.mdl, queryExecutor)
 }
 
 func (r *repository) Pluck(ctx context.Context, column string, dest any) error {
-	return r.db.WithContext(ctx).Pluck(column, dest).Error
+	return translateError(withContext(ctx, r.db).Pluck(column, dest).Error)
 }
 
 // --- Write Operations ---
@@ -124,7 +131,7 @@ func (r *repository) Create(ctx context.Context, models ...contract.Model) error
 
 	// Single model optimization
 	if useSingleOptimization {
-		return r.db.WithContext(ctx).Create(singleModel).Error
+		return translateError(withContext(ctx, r.db).Create(singleModel).Error)
 	}
 
 	// Multiple models - use helper function
@@ -133,7 +140,7 @@ func (r *repository) Create(ctx context.Context, models ...contract.Model) error
 		return fmt.Errorf("failed to convert models: %w", err)
 	}
 
-	return r.db.WithContext(ctx).Create(slice).Error
+	return translateError(withContext(ctx, r.db).Create(slice).Error)
 }
 
 func (r *repository) CreateInBatches(ctx context.Context, models []contract.Model, batchSize int) error {
@@ -150,7 +157,7 @@ func (r *repository) CreateInBatches(ctx context.Context, models []contract.Mode
 		return fmt.Errorf("failed to convert models: %w", err)
 	}
 
-	return r.db.WithContext(ctx).CreateInBatches(slice, batchSize).Error
+	return translateError(withContext(ctx, r.db).CreateInBatches(slice, batchSize).Error)
 }
 
 func (r *repository) Update(ctx context.Context, models ...contract.Model) error {
@@ -165,7 +172,9 @@ func (r *repository) Update(ctx context.Context, models ...contract.Model) error
 		}
 		model := models[0]
 		// Use Updates with WHERE condition based on primary key
-		return r.db.WithContext(ctx).Model(model).Where(model.PrimaryKey()+" = ?", model.GetID()).Updates(model).Error
+		return translateError(withContext(ctx, r.db).Model(model).
+			Where(model.PrimaryKey()+" = ?", model.GetID()).
+			Updates(model).Error)
 	}
 
 	// For multiple models, update each one individually
@@ -173,11 +182,11 @@ func (r *repository) Update(ctx context.Context, models ...contract.Model) error
 		if model == nil {
 			return errors.New("model cannot be nil")
 		}
-		err := r.db.WithContext(ctx).Model(model).
+		err := withContext(ctx, r.db).Model(model).
 			Where(model.PrimaryKey()+" = ?", model.GetID()).
 			Updates(model).Error
 		if err != nil {
-			return err
+			return translateError(err)
 		}
 	}
 
@@ -194,7 +203,7 @@ func (r *repository) Delete(ctx context.Context, models ...contract.Model) error
 		if models[0] == nil {
 			return errors.New("model cannot be nil")
 		}
-		return r.db.WithContext(ctx).Delete(models[0]).Error
+		return translateError(withContext(ctx, r.db).Delete(models[0]).Error)
 	}
 
 	// Multiple models - use helper function
@@ -203,7 +212,7 @@ func (r *repository) Delete(ctx context.Context, models ...contract.Model) error
 		return fmt.Errorf("failed to convert models: %w", err)
 	}
 
-	return r.db.WithContext(ctx).Delete(slice).Error
+	return translateError(withContext(ctx, r.db).Delete(slice).Error)
 }
 
 func (r *repository) ForceDelete(ctx context.Context, models ...contract.Model) error {
@@ -216,7 +225,7 @@ func (r *repository) ForceDelete(ctx context.Context, models ...contract.Model)
 		if models[0] == nil {
 			return errors.New("model cannot be nil")
 		}
-		return r.db.WithContext(ctx).Unscoped().Delete(models[0]).Error
+		return translateError(withContext(ctx, r.db).Unscoped().Delete(models[0]).Error)
 	}
 
 	// Multiple models - use helper function
@@ -225,7 +234,7 @@ func (r *repository) ForceDelete(ctx context.Context, models ...contract.Model)
 		return fmt.Errorf("failed to convert models: %w", err)
 	}
 
-	return r.db.WithContext(ctx).Unscoped().Delete(slice).Error
+	return translateError(withContext(ctx, r.db).Unscoped().Delete(slice).Error)
 }
 
 // --- Upsert Operations ---
@@ -241,13 +250,13 @@ func (r *repository) FirstOrCreate(
 		toCreate = condition
 	}
 	// GORM mutates the first argument, which must be the condition
-	err := r.db.WithContext(ctx).Where(condition).FirstOrCreate(toCreate).Error
+	err := translateError(withContext(ctx, r.db).Where(condition).FirstOrCreate(toCreate).Error)
 	return toCreate, err
 }
 
 func (r *repository) UpdateOrCreate(ctx context.Context, condition contract.Model, values any) (contract.Model, error) {
 	// GORM mutates the condition model in this case
-	err := r.db.WithContext(ctx).Where(condition).Assign(values).FirstOrCreate(condition).Error
+	err := translateError(withContext(ctx, r.db).Where(condition).Assign(values).FirstOrCreate(condition).Error)
 	return condition, err
 }
 
@@ -8,7 +8,6 @@ import (
 	"github.com/next-trace/scg-database/contract"
 	"github.com/next-trace/scg-database/db"
 	"github.com/stretchr/testify/require"
-	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 )
@@ -52,7 +51,7 @@ func (m *testModel) SetUpdatedAt(t time.Time) { m.UpdatedAt = t }
 
 // Test Helper to create an isolated DB for each test
 func setupTest(t *testing.T) contract.Repository {
-	gormDB, err := gorm.Open(sqlite.Open("file::memory:?cache=shared"), &gorm.Config{
+	gormDB, err := gorm.Open(openTestSQLite("file::memory:?cache=shared"), &gorm.Config{
 		Logger: logger.Default.LogMode(logger.Silent),
 	})
 	require.NoError(t, err)
@@ -74,12 +73,25 @@ func TestRepository_CreateInBatches(t *testing.T) {
 		models = append(models, &testModel{Name: fmt.Sprintf("User %d", i), Email: fmt.Sprintf("user%d@example.com", i)})
 	}
 	require.NoError(t, repo.CreateInBatches(t.Context(), models, 2))
+	for _, model := range models {
+		require.NotZero(t, model.(*testModel).ID, "generated keys are written back to the models")
+	}
 
 	all, err := repo.Get(t.Context())
 	require.NoError(t, err)
 	require.Len(t, all, 5)
 }
 
+func TestRepository_Create_Many(t *testing.T) {
+	repo := setupTest(t)
+	alice := &testModel{Name: "Alice", Email: "alice@example.com"}
+	bob := &testModel{Name: "Bob", Email: "bob@example.com"}
+	require.NoError(t, repo.Create(t.Context(), alice, bob))
+	require.NotZero(t, alice.ID, "generated keys are written back to the models")
+	require.NotZero(t, bob.ID)
+	require.NotEqual(t, alice.ID, bob.ID)
+}
+
 func TestRepository_Create(t *testing.T) {
 	repo := setupTest(t)
 	user := &testModel{Name: "Alice", Email: "alice@example.com"}
@@ -110,6 +122,23 @@ func TestRepository_Where(t *testing.T) {
 	require.Equal(t, "Alice", found.(*testModel).Name)
 }
 
+func TestRepository_DerivedQueriesAreIndependent(t *testing.T) {
+	repo := setupTest(t)
+	require.NoError(t, repo.Create(t.Context(),
+		&testModel{Name: "Alice", Email: "alice1@example.com"},
+		&testModel{Name: "Alice", Email: "alice2@example.com"},
+	))
+
+	alices := repo.Where("name = ?", "Alice")
+	first, err := alices.Where("email = ?", "alice1@example.com").Get(t.Context())
+	require.NoError(t, err)
+	require.Len(t, first, 1)
+
+	all, err := alices.Get(t.Context())
+	require.NoError(t, err)
+	require.Len(t, all, 2, "conditions of a derived query do not leak into its parent")
+}
+
 func TestRepository_OrderBy(t *testing.T) {
 	t.Run("ASC order", func(t *testing.T) {
 		repo := setupTest(t)
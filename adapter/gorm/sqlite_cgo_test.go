@@ -0,0 +1,10 @@
+//go:build cgo
+
+package gorm
+
+// testSQLiteDriver is the SQLite dialect of the tests that need a database;
+// testSQLiteDrivers are the dialects the conformance and error tests run against.
+const testSQLiteDriver = GormDriverSQLite
+
+//nolint:grouper // Only One Global Variable
+var testSQLiteDrivers = []string{GormDriverSQLite, GormDriverSQLitePure}
@@ -0,0 +1,13 @@
+package gorm
+
+import (
+	"github.com/next-trace/scg-database/adapter/internal/poolstats"
+	"github.com/next-trace/scg-database/contract"
+)
+
+// Stats reports the pool configured by applyConnectionPoolOptions together
+// with the outcome of the last Ping, fulfilling the contract.
+func (c *connection) Stats() contract.Stats {
+	sqlDB, _ := c.db.DB()
+	return contract.Stats{Pools: []contract.PoolStats{poolstats.Pool("primary", sqlDB, c.health)}}
+}
@@ -0,0 +1,133 @@
+package gorm
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/next-trace/scg-database/config"
+	"github.com/next-trace/scg-database/contract"
+	"github.com/stretchr/testify/require"
+)
+
+// hookRecorder returns a hook that appends name to events when it runs.
+func hookRecorder(events *[]string, name string) func() {
+	return func() { *events = append(*events, name) }
+}
+
+func TestConnection_TxHooks(t *testing.T) {
+	t.Run("Commit", func(t *testing.T) {
+		gormDB, mock := setupConnTestDB(t)
+		conn := &connection{db: gormDB}
+		var events []string
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+
+		err := conn.Transaction(t.Context(), func(tx contract.Connection) error {
+			hooks := tx.(contract.TxHooks)
+			hooks.OnCommit(hookRecorder(&events, "commit"))
+			hooks.OnRollback(hookRecorder(&events, "rollback"))
+			require.Empty(t, events, "hooks must not run inside the transaction")
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"commit"}, events)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Rollback", func(t *testing.T) {
+		gormDB, mock := setupConnTestDB(t)
+		conn := &connection{db: gormDB}
+		var events []string
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+
+		err := conn.Transaction(t.Context(), func(tx contract.Connection) error {
+			tx.(contract.TxHooks).OnCommit(hookRecorder(&events, "commit"))
+			tx.(contract.TxHooks).OnRollback(hookRecorder(&events, "rollback"))
+			return errors.New("abort")
+		})
+		require.Error(t, err)
+		require.Equal(t, []string{"rollback"}, events)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("CommitFailure", func(t *testing.T) {
+		gormDB, mock := setupConnTestDB(t)
+		conn := &connection{db: gormDB}
+		var events []string
+		mock.ExpectBegin()
+		mock.ExpectCommit().WillReturnError(errors.New("commit failed"))
+
+		err := conn.Transaction(t.Context(), func(tx contract.Connection) error {
+			tx.(contract.TxHooks).OnCommit(hookRecorder(&events, "commit"))
+			tx.(contract.TxHooks).OnRollback(hookRecorder(&events, "rollback"))
+			return nil
+		})
+		require.Error(t, err)
+		require.Equal(t, []string{"rollback"}, events)
+	})
+
+	t.Run("Panic", func(t *testing.T) {
+		gormDB, mock := setupConnTestDB(t)
+		conn := &connection{db: gormDB, config: config.Config{Driver: GormDriverMySQL}}
+		var events []string
+		mock.ExpectBegin()
+		mock.ExpectExec("SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		require.Panics(t, func() {
+			_ = conn.Transaction(t.Context(), func(tx contract.Connection) error {
+				return tx.Transaction(t.Context(), func(inner contract.Connection) error {
+					inner.(contract.TxHooks).OnCommit(hookRecorder(&events, "commit"))
+					inner.(contract.TxHooks).OnRollback(hookRecorder(&events, "rollback"))
+					panic("boom")
+				})
+			})
+		})
+		require.Equal(t, []string{"rollback"}, events)
+	})
+
+	t.Run("Nested", func(t *testing.T) {
+		gormDB, mock := setupConnTestDB(t)
+		conn := &connection{db: gormDB, config: config.Config{Driver: GormDriverMySQL}}
+		var events []string
+		mock.ExpectBegin()
+		mock.ExpectExec("SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("RELEASE SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("SAVEPOINT sp_2").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("ROLLBACK TO SAVEPOINT sp_2").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		err := conn.Transaction(t.Context(), func(tx contract.Connection) error {
+			tx.(contract.TxHooks).OnCommit(hookRecorder(&events, "outer commit"))
+
+			require.NoError(t, tx.Transaction(t.Context(), func(inner contract.Connection) error {
+				inner.(contract.TxHooks).OnCommit(hookRecorder(&events, "released commit"))
+				inner.(contract.TxHooks).OnRollback(hookRecorder(&events, "released rollback"))
+				return nil
+			}))
+
+			require.Error(t, tx.Transaction(t.Context(), func(inner contract.Connection) error {
+				inner.(contract.TxHooks).OnCommit(hookRecorder(&events, "discarded commit"))
+				inner.(contract.TxHooks).OnRollback(hookRecorder(&events, "savepoint rollback"))
+				return errors.New("inner failure")
+			}))
+
+			require.Empty(t, events, "hooks must wait for the outermost transaction")
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"savepoint rollback", "outer commit", "released commit"}, events)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("OutsideTransaction", func(t *testing.T) {
+		gormDB, _ := setupConnTestDB(t)
+		conn := &connection{db: gormDB}
+		var events []string
+		conn.OnCommit(hookRecorder(&events, "commit"))
+		conn.OnRollback(hookRecorder(&events, "rollback"))
+		require.Equal(t, []string{"commit"}, events)
+	})
+}
@@ -122,7 +122,9 @@ func createEntityFromModel(model contract.Model) (contract.Model, error) {
 	return nil, fmt.Errorf("failed to assert created instance to contract.Model")
 }
 
-// convertModelsToSlice converts a slice of contract.Model to a concrete slice for GORM operations
+// convertModelsToSlice converts a slice of contract.Model to a concrete slice of
+// pointers for GORM operations. The pointers are the models themselves, so the
+// primary keys and timestamps GORM sets are visible to the caller.
 func convertModelsToSlice(models []contract.Model, modelType reflect.Type) (interface{}, error) {
 	if len(models) == 0 {
 		return nil, fmt.Errorf("models slice cannot be empty")
@@ -133,7 +135,7 @@ func convertModelsToSlice(models []contract.Model, modelType reflect.Type) (inte
 		modelType = modelType.Elem()
 	}
 
-	slice := reflect.MakeSlice(reflect.SliceOf(modelType), len(models), len(models))
+	slice := reflect.MakeSlice(reflect.SliceOf(reflect.PointerTo(modelType)), len(models), len(models))
 
 	for i, model := range models {
 		if model == nil {
@@ -141,15 +143,18 @@ func convertModelsToSlice(models []contract.Model, modelType reflect.Type) (inte
 		}
 
 		modelValue := reflect.ValueOf(model)
-		if modelValue.Kind() == reflect.Ptr {
-			modelValue = modelValue.Elem()
+		if modelValue.Kind() != reflect.Ptr {
+			// Models passed by value are copied, as there is nothing to write back to
+			copied := reflect.New(modelValue.Type())
+			copied.Elem().Set(modelValue)
+			modelValue = copied
 		}
 
-		if !modelValue.Type().AssignableTo(modelType) {
+		if !modelValue.Type().Elem().AssignableTo(modelType) {
 			return nil, fmt.Errorf("model at index %d is not assignable to expected type %s", i, modelType)
 		}
 
-		slice.Index(i).Set(modelValue)
+		slice.Index(i).Set(modelValue.Convert(slice.Type().Elem()))
 	}
 
 	return slice.Interface(), nil
@@ -201,14 +206,18 @@ func executeQueryAndConvertToModels(
 
 // Config utility functions
 
-// extractGormConfig extracts GORM configuration from settings
+// extractGormConfig extracts GORM configuration from settings. The automatic
+// ping of gorm.Open is disabled: it cannot be cancelled, so db.Connect pings
+// the connection itself with the caller's context.
 func extractGormConfig(cfg *config.Config) *gorm.Config {
 	gormConfig := &gorm.Config{}
 
-	// Extract gorm_config if present
+	// Extract gorm_config if present, copied to leave the caller's untouched
 	if c, ok := cfg.Settings["gorm_config"].(*gorm.Config); ok {
-		gormConfig = c
+		copied := *c
+		gormConfig = &copied
 	}
+	gormConfig.DisableAutomaticPing = true
 
 	// Extract gorm_logger if present
 	if l, ok := cfg.Settings["gorm_logger"].(logger.Interface); ok {
@@ -0,0 +1,59 @@
+// Package poolstats reports the connection pools of the adapters of this
+// module as contract.PoolStats.
+package poolstats
+
+import (
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/next-trace/scg-database/contract"
+)
+
+type (
+	// Health remembers the outcome of the last Ping of a pool. It is shared
+	// by a connection and the transaction connections created from it.
+	Health struct {
+		mu      sync.Mutex
+		at      time.Time
+		latency time.Duration
+		err     error
+	}
+)
+
+// Record stores the outcome of a Ping that started at start.
+func (h *Health) Record(start time.Time, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.at = time.Now()
+	h.latency = h.at.Sub(start)
+	h.err = err
+}
+
+// fill copies the last Ping outcome into stats.
+func (h *Health) fill(stats *contract.PoolStats) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	stats.LastPingAt = h.at
+	stats.LastPingLatency = h.latency
+	stats.LastPingError = h.err
+}
+
+// Pool reports the saturation of sqlDB together with the outcome of the last
+// Ping recorded in health. Either may be nil.
+func Pool(name string, sqlDB *sql.DB, health *Health) contract.PoolStats {
+	pool := contract.PoolStats{Name: name}
+	if sqlDB != nil {
+		dbStats := sqlDB.Stats()
+		pool.MaxOpenConnections = dbStats.MaxOpenConnections
+		pool.OpenConnections = dbStats.OpenConnections
+		pool.InUse = dbStats.InUse
+		pool.Idle = dbStats.Idle
+		pool.WaitCount = dbStats.WaitCount
+		pool.WaitDuration = dbStats.WaitDuration
+	}
+	if health != nil {
+		health.fill(&pool)
+	}
+	return pool
+}
@@ -0,0 +1,163 @@
+package schema
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/next-trace/scg-database/contract"
+)
+
+type (
+	// Relation is a relationship of a model resolved to its tables and columns.
+	Relation struct {
+		Name    string
+		Owner   *Schema
+		Related *Schema
+		// Field is the index of the owner field holding the related models.
+		Field []int
+		// OwnerKey and RelatedKey are the columns matched between the owner and
+		// the related rows. For many-to-many relationships they are the primary
+		// keys, matched through the OwnerJoinKey and RelatedJoinKey columns of
+		// JoinTable.
+		OwnerKey, RelatedKey         string
+		JoinTable                    string
+		OwnerJoinKey, RelatedJoinKey string
+	}
+)
+
+// Relation returns the tables and columns of the relationship name of s. Keys
+// left empty default to GORM's conventions: the primary key on the owning side
+// and "<model>_<primary key>" on the other.
+func (s *Schema) Relation(name string) (*Relation, error) {
+	r, ok := s.Relationships[name]
+	field, hasField := s.Relations[name]
+	if !ok || !hasField {
+		return nil, fmt.Errorf("unknown relationship %q of %s", name, s.Type)
+	}
+	related, err := Of(r.RelatedModel())
+	if err != nil {
+		return nil, fmt.Errorf("invalid relationship %q of %s: %w", name, s.Type, err)
+	}
+	rel := &Relation{Name: name, Owner: s, Related: related, Field: field}
+
+	switch r.Type() {
+	case contract.HasOne, contract.HasMany:
+		rel.OwnerKey = keyOrDefault(r.OwnerKey(), s.PrimaryKey.Name)
+		rel.RelatedKey = keyOrDefault(r.ForeignKey(), foreignKeyOf(s))
+	case contract.BelongsTo:
+		rel.OwnerKey = keyOrDefault(r.ForeignKey(), foreignKeyOf(related))
+		rel.RelatedKey = keyOrDefault(r.OwnerKey(), related.PrimaryKey.Name)
+	case contract.BelongsToMany, contract.Many2Many:
+		rel.OwnerKey = s.PrimaryKey.Name
+		rel.RelatedKey = related.PrimaryKey.Name
+		rel.JoinTable = r.ManyToManyJoinTable()
+		rel.OwnerJoinKey = foreignKeyOf(s)
+		rel.RelatedJoinKey = foreignKeyOf(related)
+	default:
+		return nil, fmt.Errorf("unsupported relationship type %s of %q", r.Type(), name)
+	}
+
+	if s.ByName[rel.OwnerKey] == nil {
+		return nil, fmt.Errorf("relationship %q: %s has no column %s", name, s.Type, rel.OwnerKey)
+	}
+	if related.ByName[rel.RelatedKey] == nil {
+		return nil, fmt.Errorf("relationship %q: %s has no column %s", name, related.Type, rel.RelatedKey)
+	}
+	return rel, nil
+}
+
+// Assign stores items, pointers to related structs, in the relationship field
+// of the owner row. The field may be a struct, a pointer, or a slice of either.
+func (rel *Relation) Assign(row reflect.Value, items []reflect.Value) error {
+	field, err := row.Elem().FieldByIndexErr(rel.Field)
+	if err != nil {
+		return fmt.Errorf("cannot set relationship %q: %w", rel.Name, err)
+	}
+
+	typ := field.Type()
+	if typ.Kind() == reflect.Slice {
+		typ = typ.Elem()
+	}
+	byPointer := typ.Kind() == reflect.Pointer
+	if (byPointer && typ.Elem() != rel.Related.Type) || (!byPointer && typ != rel.Related.Type) {
+		return fmt.Errorf("cannot store %s in the %s field of relationship %q", rel.Related.Type, field.Type(), rel.Name)
+	}
+
+	item := func(i int) reflect.Value {
+		if byPointer {
+			return items[i]
+		}
+		return items[i].Elem()
+	}
+	switch {
+	case field.Kind() == reflect.Slice:
+		slice := reflect.MakeSlice(field.Type(), len(items), len(items))
+		for i := range items {
+			slice.Index(i).Set(item(i))
+		}
+		field.Set(slice)
+	case len(items) > 0:
+		field.Set(item(0))
+	default:
+		field.SetZero()
+	}
+	return nil
+}
+
+// DistinctKeys returns the distinct non-nil values of col in rows, pointers to
+// structs.
+func DistinctKeys(rows []reflect.Value, col *Column) []any {
+	seen := make(map[string]bool, len(rows))
+	keys := make([]any, 0, len(rows))
+	for _, row := range rows {
+		value := col.Value(row.Elem())
+		key := KeyOf(value)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		keys = append(keys, Indirect(value))
+	}
+	return keys
+}
+
+// KeyOf returns a comparable form of a key value, so that keys read into
+// different Go types, such as int64 and uint, match. Nil keys return "".
+func KeyOf(value any) string {
+	value = Indirect(value)
+	switch value := value.(type) {
+	case nil:
+		return ""
+	case []byte:
+		return string(value)
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// Indirect dereferences pointer values; nil pointers become nil.
+func Indirect(value any) any {
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return nil
+	}
+	return v.Interface()
+}
+
+// foreignKeyOf returns the conventional foreign key column referencing s.
+func foreignKeyOf(s *Schema) string {
+	return SnakeCase(s.Type.Name()) + "_" + s.PrimaryKey.Name
+}
+
+func keyOrDefault(key, fallback string) string {
+	if key == "" {
+		return fallback
+	}
+	return key
+}
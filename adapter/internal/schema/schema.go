@@ -0,0 +1,334 @@
+// Package schema maps model structs to tables and columns for the adapters that
+// do not rely on GORM. It follows GORM's default naming, so the same models work
+// with every adapter.
+package schema
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/next-trace/scg-database/contract"
+)
+
+type (
+	// Column maps a struct field to a table column.
+	Column struct {
+		Name  string
+		Index []int
+		Type  reflect.Type
+	}
+
+	// Schema describes how a model struct is stored. Columns are the exported
+	// fields, named by their `db` tag, the `column` of their `gorm` tag, or the
+	// snake case of the field name, as GORM does. Embedded structs are flattened;
+	// relationship fields and fields tagged `db:"-"` are skipped.
+	Schema struct {
+		Type       reflect.Type
+		Table      string
+		PrimaryKey *Column
+		Columns    []*Column
+		ByName     map[string]*Column
+		// Relationships are the relationships of the model, and Relations maps
+		// their names to the field holding the related models.
+		Relationships map[string]contract.Relationship
+		Relations     map[string][]int
+		// SoftDelete and Timestamps report whether the model implements
+		// contract.SoftDelete and contract.Timestamps and has their columns.
+		SoftDelete bool
+		Timestamps bool
+	}
+)
+
+// Column names of the soft delete and timestamp fields.
+const (
+	DeletedAtColumn = "deleted_at"
+	CreatedAtColumn = "created_at"
+	UpdatedAtColumn = "updated_at"
+)
+
+//nolint:grouper // Reflection caches used only by Of and OfStruct
+var (
+	schemas sync.Map // reflect.Type -> *Schema
+
+	scannerType = reflect.TypeFor[sql.Scanner]()
+	valuerType  = reflect.TypeFor[driver.Valuer]()
+	timeType    = reflect.TypeFor[time.Time]()
+)
+
+// Of returns the schema of the struct model points to.
+func Of(model contract.Model) (*Schema, error) {
+	typ := reflect.TypeOf(model)
+	if typ == nil || typ.Kind() != reflect.Pointer || typ.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("model must be a pointer to a struct, got %T", model)
+	}
+	if cached, ok := schemas.Load(typ.Elem()); ok {
+		return cached.(*Schema), nil //nolint:forcetypeassert // only Of and OfStruct store values
+	}
+
+	s := newSchema(typ.Elem(), model.Relationships())
+	if s.Table = model.TableName(); s.Table == "" {
+		s.Table = Pluralize(SnakeCase(s.Type.Name()))
+	}
+	_, softDelete := model.(contract.SoftDelete)
+	s.SoftDelete = softDelete && s.ByName[DeletedAtColumn] != nil
+	_, timestamps := model.(contract.Timestamps)
+	s.Timestamps = timestamps && s.ByName[CreatedAtColumn] != nil && s.ByName[UpdatedAtColumn] != nil
+
+	pk, ok := s.ByName[model.PrimaryKey()]
+	if !ok {
+		return nil, fmt.Errorf("model %T has no field for primary key %q", model, model.PrimaryKey())
+	}
+	s.PrimaryKey = pk
+
+	schemas.Store(s.Type, s)
+	return s, nil
+}
+
+// OfStruct returns the columns of a struct that is not a model, such as the
+// destination of a query builder with custom selects.
+func OfStruct(typ reflect.Type) (*Schema, error) {
+	if model, ok := reflect.New(typ).Interface().(contract.Model); ok {
+		return Of(model)
+	}
+	if cached, ok := schemas.Load(typ); ok {
+		return cached.(*Schema), nil //nolint:forcetypeassert // only Of and OfStruct store values
+	}
+	s := newSchema(typ, nil)
+	schemas.Store(typ, s)
+	return s, nil
+}
+
+// newSchema returns the schema of the struct type typ without table details.
+func newSchema(typ reflect.Type, relationships map[string]contract.Relationship) *Schema {
+	s := &Schema{
+		Type:          typ,
+		ByName:        make(map[string]*Column),
+		Relationships: relationships,
+		Relations:     make(map[string][]int),
+	}
+	s.addFields(typ, nil, relationships)
+	return s
+}
+
+// addFields adds the columns and relationship fields of the struct type typ,
+// found at index within the model.
+func (s *Schema) addFields(typ reflect.Type, index []int, relationships map[string]contract.Relationship) {
+	for i := range typ.NumField() {
+		f := typ.Field(i)
+		fieldIndex := append(append(make([]int, 0, len(index)+1), index...), i)
+
+		if f.Anonymous && isEmbeddable(f.Type) {
+			embedded := f.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			s.addFields(embedded, fieldIndex, relationships)
+			continue
+		}
+		if !f.IsExported() || f.Tag.Get("db") == "-" || f.Tag.Get("gorm") == "-" {
+			continue
+		}
+		if _, ok := relationships[f.Name]; ok {
+			s.Relations[f.Name] = fieldIndex
+			continue
+		}
+		if !isColumnType(f.Type) {
+			continue
+		}
+
+		col := &Column{Name: ColumnName(f), Index: fieldIndex, Type: f.Type}
+		if _, exists := s.ByName[col.Name]; exists {
+			continue // the first field of a column wins
+		}
+		s.Columns = append(s.Columns, col)
+		s.ByName[col.Name] = col
+	}
+}
+
+// ColumnNames returns the names of the columns, skipping the primary key when
+// withPrimaryKey is false.
+func (s *Schema) ColumnNames(withPrimaryKey bool) []string {
+	names := make([]string, 0, len(s.Columns))
+	for _, col := range s.Columns {
+		if col == s.PrimaryKey && !withPrimaryKey {
+			continue
+		}
+		names = append(names, col.Name)
+	}
+	return names
+}
+
+// Lookup returns the column of a column or field name, such as "user_id" or
+// "UserID".
+func (s *Schema) Lookup(name string) (*Column, bool) {
+	if col, ok := s.ByName[name]; ok {
+		return col, true
+	}
+	col, ok := s.ByName[SnakeCase(name)]
+	return col, ok
+}
+
+// Field returns the field of col in the struct v, or false when it sits behind
+// a nil embedded pointer.
+func (col *Column) Field(v reflect.Value) (reflect.Value, bool) {
+	f, err := v.FieldByIndexErr(col.Index)
+	return f, err == nil
+}
+
+// Value returns the value of col in the struct v.
+func (col *Column) Value(v reflect.Value) any {
+	f, ok := col.Field(v)
+	if !ok {
+		return nil
+	}
+	return f.Interface()
+}
+
+// IsZero reports whether col holds its zero value in the struct v.
+func (col *Column) IsZero(v reflect.Value) bool {
+	f, ok := col.Field(v)
+	return !ok || f.IsZero()
+}
+
+// Set assigns value to col in the struct v, converting numeric values such as
+// the int64 of LastInsertId to the field type, values to pointer fields, and
+// values to sql.Scanner fields.
+func (col *Column) Set(v reflect.Value, value any) error {
+	f, err := v.FieldByIndexErr(col.Index)
+	if err != nil {
+		return fmt.Errorf("cannot set column %s: %w", col.Name, err)
+	}
+	if !assign(f, reflect.ValueOf(value)) {
+		return fmt.Errorf("cannot assign %T to column %s of type %s", value, col.Name, f.Type())
+	}
+	return nil
+}
+
+// assign sets f to src, converting between numeric types and between values
+// and pointers to them, and scanning into sql.Scanner fields such as
+// sql.NullString. It reports false when the types do not match.
+func assign(f, src reflect.Value) bool {
+	switch {
+	case !src.IsValid():
+		f.SetZero()
+	case src.Type().AssignableTo(f.Type()):
+		f.Set(src)
+	case src.Type().ConvertibleTo(f.Type()) && IsNumeric(src.Kind()) && IsNumeric(f.Kind()):
+		f.Set(src.Convert(f.Type()))
+	case src.Kind() == reflect.Pointer:
+		if src.IsNil() {
+			f.SetZero()
+			return true
+		}
+		return assign(f, src.Elem())
+	case IsScanner(f.Type()):
+		scanner := f.Addr().Interface().(sql.Scanner) //nolint:forcetypeassert // checked by IsScanner
+		return scanner.Scan(src.Interface()) == nil
+	case f.Kind() == reflect.Pointer:
+		p := reflect.New(f.Type().Elem())
+		if !assign(p.Elem(), src) {
+			return false
+		}
+		f.Set(p)
+	default:
+		return false
+	}
+	return true
+}
+
+// IsScanner reports whether a pointer to values of typ implements sql.Scanner.
+func IsScanner(typ reflect.Type) bool {
+	return reflect.PointerTo(typ).Implements(scannerType)
+}
+
+// isEmbeddable reports whether an anonymous field is flattened into the model.
+func isEmbeddable(typ reflect.Type) bool {
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	return typ.Kind() == reflect.Struct && IsStructOfColumns(typ)
+}
+
+// IsStructOfColumns reports whether typ is a plain struct rather than a value
+// type such as time.Time or sql.NullString.
+func IsStructOfColumns(typ reflect.Type) bool {
+	return typ != timeType && !IsScanner(typ) && !typ.Implements(valuerType)
+}
+
+// isColumnType reports whether a field of type typ is stored in a column:
+// scalars, []byte, time.Time, scanners and valuers, or pointers to them.
+// Structs and slices of structs are associations and are skipped.
+func isColumnType(typ reflect.Type) bool {
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	switch typ.Kind() {
+	case reflect.Struct:
+		return !IsStructOfColumns(typ)
+	case reflect.Slice, reflect.Map, reflect.Chan, reflect.Func, reflect.Array:
+		if typ.Kind() == reflect.Slice && typ.Elem().Kind() == reflect.Uint8 {
+			return true
+		}
+		return IsScanner(typ) || typ.Implements(valuerType)
+	default:
+		return true
+	}
+}
+
+// IsNumeric reports whether kind is an integer or floating point kind.
+func IsNumeric(kind reflect.Kind) bool {
+	return (kind >= reflect.Int && kind <= reflect.Uint64) || kind == reflect.Float32 || kind == reflect.Float64
+}
+
+// ColumnName returns the column of a struct field.
+func ColumnName(f reflect.StructField) string {
+	if name, _, _ := strings.Cut(f.Tag.Get("db"), ","); name != "" {
+		return name
+	}
+	for _, setting := range strings.Split(f.Tag.Get("gorm"), ";") {
+		if name, found := strings.CutPrefix(strings.TrimSpace(setting), "column:"); found {
+			return name
+		}
+	}
+	return SnakeCase(f.Name)
+}
+
+// SnakeCase converts a Go name to snake case, keeping initialisms together:
+// "UserID" becomes "user_id" and "HTTPStatus" becomes "http_status".
+func SnakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			prevLower := i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]))
+			nextLower := i > 0 && i+1 < len(runes) && unicode.IsUpper(runes[i-1]) && unicode.IsLower(runes[i+1])
+			if prevLower || nextLower {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// Pluralize returns the English plural of a snake case table name.
+func Pluralize(name string) string {
+	switch {
+	case name == "":
+		return name
+	case strings.HasSuffix(name, "y") && !strings.HasSuffix(name, "ey") && !strings.HasSuffix(name, "ay"):
+		return strings.TrimSuffix(name, "y") + "ies"
+	case strings.HasSuffix(name, "s"), strings.HasSuffix(name, "x"),
+		strings.HasSuffix(name, "ch"), strings.HasSuffix(name, "sh"):
+		return name + "es"
+	default:
+		return name + "s"
+	}
+}
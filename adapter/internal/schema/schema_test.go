@@ -0,0 +1,180 @@
+package schema
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/next-trace/scg-database/contract"
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	testAuthor struct {
+		contract.SoftDeletableModel
+		Name   string         `db:"full_name"`
+		Email  sql.NullString `gorm:"column:email_address"`
+		Secret string         `db:"-"`
+		Books  []*testBook
+	}
+
+	testBook struct {
+		ID           int
+		TestAuthorID int
+		Title        string
+		Author       testAuthor
+	}
+)
+
+func (a *testAuthor) TableName() string { return "" }
+func (a *testAuthor) Relationships() map[string]contract.Relationship {
+	return map[string]contract.Relationship{"Books": contract.NewHasMany(&testBook{}, "", "")}
+}
+
+func (b *testBook) PrimaryKey() string { return "id" }
+func (b *testBook) TableName() string  { return "books" }
+func (b *testBook) GetID() any         { return b.ID }
+func (b *testBook) SetID(id any)       { b.ID, _ = id.(int) }
+func (b *testBook) Relationships() map[string]contract.Relationship {
+	return map[string]contract.Relationship{"Author": contract.NewBelongsTo(&testAuthor{}, "", "")}
+}
+
+func TestOf(t *testing.T) {
+	s, err := Of(&testAuthor{})
+	require.NoError(t, err)
+	require.Equal(t, "test_authors", s.Table, "empty table names are derived from the type")
+	require.Equal(t, []string{"id", "created_at", "updated_at", "deleted_at", "full_name", "email_address"},
+		s.ColumnNames(true))
+	require.Equal(t, []string{"created_at", "updated_at", "deleted_at", "full_name", "email_address"},
+		s.ColumnNames(false))
+	require.Equal(t, "id", s.PrimaryKey.Name)
+	require.True(t, s.SoftDelete)
+	require.True(t, s.Timestamps)
+	require.Contains(t, s.Relations, "Books")
+
+	cached, err := Of(&testAuthor{})
+	require.NoError(t, err)
+	require.Same(t, s, cached)
+
+	book, err := Of(&testBook{})
+	require.NoError(t, err)
+	require.False(t, book.SoftDelete)
+	require.False(t, book.Timestamps)
+
+	_, err = Of(nil)
+	require.Error(t, err)
+}
+
+func TestOfStruct(t *testing.T) {
+	type summary struct {
+		Age   int
+		Total int `db:"total"`
+		When  *time.Time
+	}
+	s, err := OfStruct(reflect.TypeFor[summary]())
+	require.NoError(t, err)
+	require.Equal(t, []string{"age", "total", "when"}, s.ColumnNames(true))
+	require.Nil(t, s.PrimaryKey)
+
+	col, ok := s.Lookup("Total")
+	require.True(t, ok)
+	require.Equal(t, "total", col.Name)
+	_, ok = s.Lookup("missing")
+	require.False(t, ok)
+}
+
+func TestColumn_Set(t *testing.T) {
+	s, err := Of(&testBook{})
+	require.NoError(t, err)
+	book := &testBook{}
+	v := reflect.ValueOf(book).Elem()
+
+	require.NoError(t, s.PrimaryKey.Set(v, int64(7)), "numeric values are converted")
+	require.Equal(t, 7, book.ID)
+	require.False(t, s.PrimaryKey.IsZero(v))
+	require.Equal(t, 7, s.PrimaryKey.Value(v))
+	require.NoError(t, s.ByName["title"].Set(v, "Go"))
+	require.Equal(t, "Go", book.Title)
+	require.ErrorContains(t, s.ByName["title"].Set(v, 1), "cannot assign int to column title")
+	require.NoError(t, s.ByName["title"].Set(v, nil))
+	require.Empty(t, book.Title)
+
+	authors, err := Of(&testAuthor{})
+	require.NoError(t, err)
+	author := &testAuthor{}
+	deletedAt := authors.ByName[DeletedAtColumn]
+	now := time.Now()
+	require.NoError(t, deletedAt.Set(reflect.ValueOf(author).Elem(), now), "values are set through pointers")
+	require.Equal(t, now, *author.DeletedAt)
+	require.NoError(t, deletedAt.Set(reflect.ValueOf(author).Elem(), (*time.Time)(nil)))
+	require.Nil(t, author.DeletedAt)
+	require.NoError(t, authors.ByName["email_address"].Set(reflect.ValueOf(author).Elem(), "ada@example.com"),
+		"scanners scan the value")
+	require.Equal(t, sql.NullString{String: "ada@example.com", Valid: true}, author.Email)
+	title := "Rust"
+	require.NoError(t, s.ByName["title"].Set(v, &title), "pointers are dereferenced")
+	require.Equal(t, "Rust", book.Title)
+}
+
+func TestRelation(t *testing.T) {
+	author, err := Of(&testAuthor{})
+	require.NoError(t, err)
+	rel, err := author.Relation("Books")
+	require.NoError(t, err)
+	require.Equal(t, "id", rel.OwnerKey)
+	require.Equal(t, "test_author_id", rel.RelatedKey)
+
+	book, err := Of(&testBook{})
+	require.NoError(t, err)
+	rel, err = book.Relation("Author")
+	require.NoError(t, err)
+	require.Equal(t, "test_author_id", rel.OwnerKey)
+	require.Equal(t, "id", rel.RelatedKey)
+
+	owner := reflect.ValueOf(&testBook{})
+	related := &testAuthor{Name: "Ann"}
+	require.NoError(t, rel.Assign(owner, []reflect.Value{reflect.ValueOf(related)}))
+	require.Equal(t, "Ann", owner.Interface().(*testBook).Author.Name)
+
+	_, err = author.Relation("Missing")
+	require.ErrorContains(t, err, `unknown relationship "Missing"`)
+}
+
+func TestKeys(t *testing.T) {
+	one := 1
+	require.Equal(t, "1", KeyOf(int64(1)))
+	require.Equal(t, KeyOf(uint(1)), KeyOf(&one))
+	require.Empty(t, KeyOf((*int)(nil)))
+	require.Equal(t, "a", KeyOf([]byte("a")))
+
+	s, err := Of(&testBook{})
+	require.NoError(t, err)
+	rows := []reflect.Value{
+		reflect.ValueOf(&testBook{TestAuthorID: 1}),
+		reflect.ValueOf(&testBook{TestAuthorID: 2}),
+		reflect.ValueOf(&testBook{TestAuthorID: 1}),
+	}
+	require.Equal(t, []any{1, 2}, DistinctKeys(rows, s.ByName["test_author_id"]))
+}
+
+func TestNaming(t *testing.T) {
+	for name, want := range map[string]string{
+		"UserID":     "user_id",
+		"HTTPStatus": "http_status",
+		"CreatedAt":  "created_at",
+		"Name":       "name",
+		"Address2":   "address2",
+	} {
+		require.Equal(t, want, SnakeCase(name), name)
+	}
+	for name, want := range map[string]string{
+		"user":     "users",
+		"category": "categories",
+		"key":      "keys",
+		"box":      "boxes",
+		"address":  "addresses",
+	} {
+		require.Equal(t, want, Pluralize(name), name)
+	}
+}
@@ -0,0 +1,268 @@
+// Package sqlerr classifies the errors of the MySQL, Postgres and SQLite
+// database/sql drivers into *db.DatabaseError values. It is shared by the
+// adapters of this module.
+package sqlerr
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net"
+	"reflect"
+	"regexp"
+	"strings"
+
+	mysqldriver "github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/next-trace/scg-database/db"
+)
+
+// SQLite result codes, as defined by the C library.
+const (
+	sqliteBusy                 = 5
+	sqliteLocked               = 6
+	sqliteConstraintCheck      = 275
+	sqliteConstraintForeignKey = 787
+	sqliteConstraintNotNull    = 1299
+	sqliteConstraintPrimaryKey = 1555
+	sqliteConstraintUnique     = 2067
+)
+
+//nolint:grouper // Lookup tables and message patterns used only by Translate
+var (
+	// pgErrorKinds maps Postgres SQLSTATE codes to error kinds.
+	pgErrorKinds = map[string]error{
+		"23505": db.ErrUniqueViolation,
+		"23503": db.ErrForeignKeyViolation,
+		"23502": db.ErrNotNullViolation,
+		"23514": db.ErrCheckViolation,
+		"40001": db.ErrSerializationFailure,
+		"40P01": db.ErrDeadlock,
+		"57014": db.ErrTimeout, // query_canceled, raised by statement_timeout
+		"55P03": db.ErrTimeout, // lock_not_available, raised by lock_timeout
+		"57P01": db.ErrConnectionLost,
+	}
+
+	// mysqlErrorKinds maps MySQL server error numbers to error kinds.
+	mysqlErrorKinds = map[uint16]error{
+		1062: db.ErrUniqueViolation,
+		1451: db.ErrForeignKeyViolation, // cannot delete or update a parent row
+		1452: db.ErrForeignKeyViolation, // cannot add or update a child row
+		1048: db.ErrNotNullViolation,    // column cannot be null
+		1364: db.ErrNotNullViolation,    // field doesn't have a default value
+		3819: db.ErrCheckViolation,
+		1213: db.ErrDeadlock,
+		1205: db.ErrTimeout, // lock wait timeout exceeded
+		3024: db.ErrTimeout, // max_execution_time exceeded
+	}
+
+	// sqliteErrorKinds maps SQLite extended result codes to error kinds. Both
+	// go-sqlite3 and the pure-Go driver report the codes of the C library.
+	sqliteErrorKinds = map[int]error{
+		sqliteConstraintUnique:     db.ErrUniqueViolation,
+		sqliteConstraintPrimaryKey: db.ErrUniqueViolation,
+		sqliteConstraintForeignKey: db.ErrForeignKeyViolation,
+		sqliteConstraintNotNull:    db.ErrNotNullViolation,
+		sqliteConstraintCheck:      db.ErrCheckViolation,
+	}
+
+	mysqlDuplicateKey = regexp.MustCompile(`for key '([^']+)'`)
+	mysqlForeignKey   = regexp.MustCompile("`([^`]+)`, CONSTRAINT `([^`]+)` FOREIGN KEY \\(`([^`]+)`\\)")
+	mysqlColumn       = regexp.MustCompile(`^(?:Column|Field) '([^']+)'`)
+	mysqlCheck        = regexp.MustCompile(`^Check constraint '([^']+)'`)
+	// The pure-Go driver prefixes the message with the generic "constraint
+	// failed" text and suffixes it with the result code.
+	sqliteConstraint = regexp.MustCompile(`constraint failed: ([^:]+?)(?: \(\d+\))?$`)
+)
+
+type (
+	// sqliteError holds the result codes and message of a SQLite driver error.
+	sqliteError struct {
+		code         int
+		extendedCode int
+		msg          string
+	}
+
+	// codedError is implemented by the errors of the pure-Go SQLite driver,
+	// modernc.org/sqlite, whose Code returns the extended result code.
+	codedError interface {
+		error
+		Code() int
+	}
+)
+
+// pureSQLitePackage is the import path of the pure-Go SQLite driver.
+const pureSQLitePackage = "modernc.org/sqlite"
+
+// Translate classifies MySQL, Postgres and SQLite driver errors into a
+// *db.DatabaseError. Other errors, nil included, are returned unchanged.
+func Translate(err error) error {
+	if err == nil {
+		return nil
+	}
+	var dbErr *db.DatabaseError
+	if errors.As(err, &dbErr) {
+		return err
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return translatePostgresError(pgErr, err)
+	}
+	var mysqlErr *mysqldriver.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return translateMySQLError(mysqlErr, err)
+	}
+	if sqliteErr, ok := asSQLiteError(err); ok {
+		return translateSQLiteError(sqliteErr, err)
+	}
+	return translateConnectionError(err)
+}
+
+func translatePostgresError(pgErr *pgconn.PgError, err error) error {
+	kind, ok := pgErrorKinds[pgErr.Code]
+	if !ok && strings.HasPrefix(pgErr.Code, "08") { // connection exception class
+		kind, ok = db.ErrConnectionLost, true
+	}
+	if !ok {
+		return err
+	}
+	return &db.DatabaseError{
+		Kind:       kind,
+		Constraint: pgErr.ConstraintName,
+		Table:      pgErr.TableName,
+		Column:     pgErr.ColumnName,
+		Err:        err,
+	}
+}
+
+func translateMySQLError(mysqlErr *mysqldriver.MySQLError, err error) error {
+	kind, ok := mysqlErrorKinds[mysqlErr.Number]
+	if !ok {
+		return err
+	}
+
+	dbErr := db.NewDatabaseError(kind, err)
+	switch kind {
+	case db.ErrUniqueViolation:
+		// MySQL 8 reports the key as "table.key".
+		if m := mysqlDuplicateKey.FindStringSubmatch(mysqlErr.Message); m != nil {
+			dbErr.Constraint = m[1]
+			if table, key, found := strings.Cut(m[1], "."); found {
+				dbErr.Table, dbErr.Constraint = table, key
+			}
+		}
+	case db.ErrForeignKeyViolation:
+		if m := mysqlForeignKey.FindStringSubmatch(mysqlErr.Message); m != nil {
+			dbErr.Table, dbErr.Constraint, dbErr.Column = m[1], m[2], m[3]
+		}
+	case db.ErrNotNullViolation:
+		if m := mysqlColumn.FindStringSubmatch(mysqlErr.Message); m != nil {
+			dbErr.Column = m[1]
+		}
+	case db.ErrCheckViolation:
+		if m := mysqlCheck.FindStringSubmatch(mysqlErr.Message); m != nil {
+			dbErr.Constraint = m[1]
+		}
+	}
+	return dbErr
+}
+
+// asSQLiteError extracts the SQLite error of either driver from err.
+func asSQLiteError(err error) (sqliteError, bool) {
+	if pureErr, ok := asPureSQLiteError(err); ok {
+		// The driver reports the extended code; the primary code is its low byte.
+		return sqliteError{code: pureErr.Code() & 0xff, extendedCode: pureErr.Code(), msg: pureErr.Error()}, true
+	}
+	return asCgoSQLiteError(err)
+}
+
+// asPureSQLiteError finds an error of modernc.org/sqlite in the tree of err,
+// like errors.As. The driver is recognized by the package of the error type
+// rather than imported, which keeps it out of the builds that do not use it;
+// errors of other packages that happen to have a Code method are ignored.
+func asPureSQLiteError(err error) (codedError, bool) {
+	if coded, ok := err.(codedError); ok {
+		typ := reflect.TypeOf(err)
+		if typ.Kind() == reflect.Pointer {
+			typ = typ.Elem()
+		}
+		if typ.PkgPath() == pureSQLitePackage {
+			return coded, true
+		}
+	}
+
+	switch wrapper := err.(type) {
+	case interface{ Unwrap() error }:
+		if inner := wrapper.Unwrap(); inner != nil {
+			return asPureSQLiteError(inner)
+		}
+	case interface{ Unwrap() []error }:
+		for _, inner := range wrapper.Unwrap() {
+			if coded, ok := asPureSQLiteError(inner); ok {
+				return coded, true
+			}
+		}
+	}
+	return nil, false
+}
+
+func translateSQLiteError(sqliteErr sqliteError, err error) error {
+	if sqliteErr.code == sqliteBusy || sqliteErr.code == sqliteLocked {
+		return db.NewDatabaseError(db.ErrTimeout, err)
+	}
+	kind, ok := sqliteErrorKinds[sqliteErr.extendedCode]
+	if !ok {
+		return err
+	}
+
+	dbErr := db.NewDatabaseError(kind, err)
+	m := sqliteConstraint.FindStringSubmatch(sqliteErr.msg)
+	if m == nil {
+		return dbErr
+	}
+	if kind == db.ErrCheckViolation {
+		dbErr.Constraint = m[1]
+		return dbErr
+	}
+
+	// Unique and not-null failures list "table.column" pairs, comma separated.
+	var columns []string
+	for _, qualified := range strings.Split(m[1], ", ") {
+		table, column, found := strings.Cut(qualified, ".")
+		if !found {
+			continue
+		}
+		dbErr.Table = table
+		columns = append(columns, column)
+	}
+	dbErr.Column = strings.Join(columns, ", ")
+	return dbErr
+}
+
+// translateConnectionError classifies driver-independent timeout and
+// connection errors.
+func translateConnectionError(err error) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return db.NewDatabaseError(db.ErrTimeout, err)
+	}
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone) || errors.Is(err, mysqldriver.ErrInvalidConn) {
+		return db.NewDatabaseError(db.ErrConnectionLost, err)
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		if netErr.Timeout() {
+			return db.NewDatabaseError(db.ErrTimeout, err)
+		}
+		return db.NewDatabaseError(db.ErrConnectionLost, err)
+	}
+	return err
+}
+
+// IsRetryable reports whether err is a deadlock or serialization failure: the
+// transaction was aborted because of contention and may succeed if run again.
+func IsRetryable(err error) bool {
+	err = Translate(err)
+	return errors.Is(err, db.ErrDeadlock) || errors.Is(err, db.ErrSerializationFailure)
+}
@@ -0,0 +1,22 @@
+//go:build cgo
+
+package sqlerr
+
+import (
+	"errors"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// asCgoSQLiteError extracts a go-sqlite3 error, from err.
+func asCgoSQLiteError(err error) (sqliteError, bool) {
+	var sqliteErr sqlite3.Error
+	if !errors.As(err, &sqliteErr) {
+		return sqliteError{}, false
+	}
+	return sqliteError{
+		code:         int(sqliteErr.Code),
+		extendedCode: int(sqliteErr.ExtendedCode),
+		msg:          sqliteErr.Error(),
+	}, true
+}
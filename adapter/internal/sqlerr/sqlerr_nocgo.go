@@ -0,0 +1,9 @@
+//go:build !cgo
+
+package sqlerr
+
+// asCgoSQLiteError reports false: without cgo, go-sqlite3 cannot open a
+// database and never returns an error worth translating.
+func asCgoSQLiteError(error) (sqliteError, bool) {
+	return sqliteError{}, false
+}
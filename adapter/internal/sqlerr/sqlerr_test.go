@@ -0,0 +1,178 @@
+package sqlerr
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	mysqldriver "github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/next-trace/scg-database/db"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+)
+
+type (
+	// otherCodedError has a Code method like the errors of modernc.org/sqlite,
+	// but belongs to another package.
+	otherCodedError struct {
+		code int
+	}
+)
+
+func (e *otherCodedError) Error() string { return fmt.Sprintf("error %d", e.code) }
+func (e *otherCodedError) Code() int     { return e.code }
+
+// pureSQLiteError runs stmts in order on the modernc.org/sqlite database at
+// path and returns the error of the last one.
+func pureSQLiteError(t *testing.T, path string, stmts ...string) error {
+	t.Helper()
+	conn, err := sql.Open("sqlite", path)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	last := len(stmts) - 1
+	for _, stmt := range stmts[:last] {
+		_, err := conn.Exec(stmt)
+		require.NoError(t, err)
+	}
+	_, err = conn.Exec(stmts[last])
+	require.Error(t, err)
+	return err
+}
+
+// pureSQLiteBusyError returns the error of a write to a modernc.org/sqlite
+// database locked by another connection.
+func pureSQLiteBusyError(t *testing.T) error {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "busy.db")
+	locker, err := sql.Open("sqlite", path)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = locker.Close() })
+	conn, err := locker.Conn(t.Context())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	_, err = conn.ExecContext(t.Context(), "BEGIN EXCLUSIVE")
+	require.NoError(t, err)
+
+	return pureSQLiteError(t, path, "CREATE TABLE users (id INTEGER)")
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"PostgresSerializationFailure", &pgconn.PgError{Code: "40001"}, true},
+		{"PostgresDeadlock", &pgconn.PgError{Code: "40P01"}, true},
+		{"PostgresUniqueViolation", &pgconn.PgError{Code: "23505"}, false},
+		{"MySQLDeadlock", &mysqldriver.MySQLError{Number: 1213}, true},
+		{"MySQLDuplicateEntry", &mysqldriver.MySQLError{Number: 1062}, false},
+		{"Wrapped", fmt.Errorf("exec: %w", &pgconn.PgError{Code: "40001"}), true},
+		{"Other", errors.New("boom"), false},
+		{"Nil", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, IsRetryable(tt.err))
+		})
+	}
+}
+
+func TestTranslateError(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		kind       error
+		constraint string
+		table      string
+		column     string
+	}{
+		{
+			name: "PostgresUnique",
+			err: &pgconn.PgError{
+				Code: "23505", ConstraintName: "users_email_key", TableName: "users",
+			},
+			kind: db.ErrUniqueViolation, constraint: "users_email_key", table: "users",
+		},
+		{
+			name: "PostgresNotNull",
+			err:  &pgconn.PgError{Code: "23502", TableName: "users", ColumnName: "name"},
+			kind: db.ErrNotNullViolation, table: "users", column: "name",
+		},
+		{name: "PostgresDeadlock", err: &pgconn.PgError{Code: "40P01"}, kind: db.ErrDeadlock},
+		{name: "PostgresStatementTimeout", err: &pgconn.PgError{Code: "57014"}, kind: db.ErrTimeout},
+		{name: "PostgresConnectionFailure", err: &pgconn.PgError{Code: "08006"}, kind: db.ErrConnectionLost},
+		{
+			name: "MySQLDuplicateEntry",
+			err:  &mysqldriver.MySQLError{Number: 1062, Message: "Duplicate entry 'a@b.c' for key 'users.email_unique'"},
+			kind: db.ErrUniqueViolation, constraint: "email_unique", table: "users",
+		},
+		{
+			name: "MySQLForeignKey",
+			err: &mysqldriver.MySQLError{Number: 1452, Message: "Cannot add or update a child row: a foreign key " +
+				"constraint fails (`shop`.`orders`, CONSTRAINT `fk_orders_user` FOREIGN KEY (`user_id`) " +
+				"REFERENCES `users` (`id`))"},
+			kind: db.ErrForeignKeyViolation, constraint: "fk_orders_user", table: "orders", column: "user_id",
+		},
+		{
+			name: "MySQLNotNull",
+			err:  &mysqldriver.MySQLError{Number: 1048, Message: "Column 'name' cannot be null"},
+			kind: db.ErrNotNullViolation, column: "name",
+		},
+		{
+			name: "MySQLCheck",
+			err:  &mysqldriver.MySQLError{Number: 3819, Message: "Check constraint 'balance_positive' is violated."},
+			kind: db.ErrCheckViolation, constraint: "balance_positive",
+		},
+		{name: "MySQLLockWaitTimeout", err: &mysqldriver.MySQLError{Number: 1205}, kind: db.ErrTimeout},
+		{
+			name: "PureSQLiteUnique",
+			err: pureSQLiteError(t, filepath.Join(t.TempDir(), "unique.db"),
+				"CREATE TABLE users (email TEXT UNIQUE)",
+				"INSERT INTO users (email) VALUES ('ada@example.com')",
+				"INSERT INTO users (email) VALUES ('ada@example.com')"),
+			kind: db.ErrUniqueViolation, table: "users", column: "email",
+		},
+		{
+			name: "PureSQLiteCheck",
+			err: pureSQLiteError(t, filepath.Join(t.TempDir(), "check.db"),
+				"CREATE TABLE accounts (balance INTEGER CONSTRAINT balance_positive CHECK (balance >= 0))",
+				"INSERT INTO accounts (balance) VALUES (-1)"),
+			kind: db.ErrCheckViolation, constraint: "balance_positive",
+		},
+		{name: "PureSQLiteBusy", err: pureSQLiteBusyError(t), kind: db.ErrTimeout},
+		{name: "ContextDeadline", err: fmt.Errorf("query: %w", context.DeadlineExceeded), kind: db.ErrTimeout},
+		{name: "BadConnection", err: driver.ErrBadConn, kind: db.ErrConnectionLost},
+		{name: "MySQLInvalidConnection", err: mysqldriver.ErrInvalidConn, kind: db.ErrConnectionLost},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Translate(tt.err)
+			require.ErrorIs(t, err, tt.kind)
+			require.ErrorIs(t, err, tt.err, "the driver error should stay reachable")
+
+			var dbErr *db.DatabaseError
+			require.ErrorAs(t, err, &dbErr)
+			require.Equal(t, tt.constraint, dbErr.Constraint)
+			require.Equal(t, tt.table, dbErr.Table)
+			require.Equal(t, tt.column, dbErr.Column)
+			require.Same(t, err, Translate(err), "translating twice should be a no-op")
+		})
+	}
+
+	t.Run("Unclassified", func(t *testing.T) {
+		other := errors.New("boom")
+		require.Equal(t, other, Translate(other))
+		require.NoError(t, Translate(nil))
+		pgErr := &pgconn.PgError{Code: "42601"}
+		require.Equal(t, error(pgErr), Translate(pgErr))
+		coded := fmt.Errorf("call: %w", &otherCodedError{code: 2067})
+		require.Equal(t, coded, Translate(coded), "only SQLite errors are read as SQLite result codes")
+	})
+}
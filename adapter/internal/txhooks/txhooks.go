@@ -0,0 +1,70 @@
+// Package txhooks collects the OnCommit and OnRollback callbacks of a
+// transaction and its savepoints for the adapters of this module.
+package txhooks
+
+import (
+	"sync"
+)
+
+type (
+	// Hooks collects the callbacks registered within one transaction scope:
+	// the outermost transaction or one of its savepoints.
+	Hooks struct {
+		mu       sync.Mutex
+		commit   []func()
+		rollback []func()
+		// undone holds rollback hooks of savepoints that were already rolled back.
+		// They run when the outermost transaction finishes, whatever its outcome.
+		undone []func()
+	}
+)
+
+// OnCommit registers fn to run once the outermost transaction commits.
+func (h *Hooks) OnCommit(fn func()) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.commit = append(h.commit, fn)
+}
+
+// OnRollback registers fn to run once the work of this scope is rolled back.
+func (h *Hooks) OnRollback(fn func()) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.rollback = append(h.rollback, fn)
+}
+
+// Release hands the hooks of a released savepoint over to its parent scope.
+func (h *Hooks) Release(child *Hooks) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.commit = append(h.commit, child.commit...)
+	h.rollback = append(h.rollback, child.rollback...)
+	h.undone = append(h.undone, child.undone...)
+}
+
+// RollbackTo records that a savepoint was rolled back: its commit hooks are
+// dropped and its rollback hooks become due when the transaction finishes.
+func (h *Hooks) RollbackTo(child *Hooks) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.undone = append(h.undone, child.undone...)
+	h.undone = append(h.undone, child.rollback...)
+}
+
+// Finish runs the hooks that are due once the outermost transaction has
+// committed or rolled back.
+func (h *Hooks) Finish(committed bool) {
+	h.mu.Lock()
+	due := h.undone
+	if committed {
+		due = append(due, h.commit...)
+	} else {
+		due = append(due, h.rollback...)
+	}
+	h.commit, h.rollback, h.undone = nil, nil, nil
+	h.mu.Unlock()
+
+	for _, fn := range due {
+		fn()
+	}
+}
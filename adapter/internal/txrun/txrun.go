@@ -0,0 +1,71 @@
+// Package txrun holds the transaction handling the adapters of this module
+// share: running the attempts of a root transaction, finishing its hooks and
+// naming its savepoints.
+package txrun
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/next-trace/scg-database/adapter/internal/txhooks"
+	"github.com/next-trace/scg-database/contract"
+	"github.com/next-trace/scg-database/db"
+)
+
+type (
+	// Savepoints names the savepoints of a root transaction so that each nested
+	// call uses a unique name. It is shared by the connections of the
+	// transaction and its savepoints.
+	Savepoints struct {
+		n atomic.Int64
+	}
+)
+
+// Next returns the name of a new savepoint.
+func (s *Savepoints) Next() string {
+	return fmt.Sprintf("sp_%d", s.n.Add(1))
+}
+
+// Retry calls attempt once, or under the retry policy of txOpts when it has
+// one. A policy without its own classifier retries the errors retryable
+// accepts.
+func Retry(
+	ctx context.Context,
+	txOpts contract.TxOptions,
+	retryable func(error) bool,
+	attempt func(ctx context.Context) error,
+) error {
+	if txOpts.Retry == nil {
+		return attempt(ctx)
+	}
+
+	policy := *txOpts.Retry
+	if policy.Retryable == nil {
+		policy.Retryable = retryable
+	}
+	return db.Retry(ctx, "Transaction", policy, attempt)
+}
+
+// Run runs body, one attempt of a root transaction, with the hooks of the
+// transaction, and runs the hooks that are due once it returns: the commit
+// hooks when it returns nil, the rollback hooks otherwise. When body panics,
+// abort, if not nil, rolls the transaction back, and the rollback hooks run
+// before the panic propagates.
+func Run(body func(hooks *txhooks.Hooks) error, abort func()) error {
+	hooks := &txhooks.Hooks{}
+	finished := false
+	defer func() {
+		if !finished {
+			if abort != nil {
+				abort()
+			}
+			hooks.Finish(false)
+		}
+	}()
+
+	err := body(hooks)
+	finished = true
+	hooks.Finish(err == nil)
+	return err
+}
@@ -0,0 +1,103 @@
+package txrun
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/next-trace/scg-database/adapter/internal/txhooks"
+	"github.com/next-trace/scg-database/contract"
+	"github.com/next-trace/scg-database/db"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSavepoints_Next(t *testing.T) {
+	var sp Savepoints
+	require.Equal(t, "sp_1", sp.Next())
+	require.Equal(t, "sp_2", sp.Next())
+}
+
+func TestRetry(t *testing.T) {
+	errRetryable := errors.New("retryable")
+	retryable := func(err error) bool { return errors.Is(err, errRetryable) }
+	failingOnce := func(calls *int) func(context.Context) error {
+		return func(context.Context) error {
+			*calls++
+			if *calls == 1 {
+				return errRetryable
+			}
+			return nil
+		}
+	}
+
+	t.Run("WithoutPolicy", func(t *testing.T) {
+		calls := 0
+		err := Retry(t.Context(), contract.NewTxOptions(), retryable, failingOnce(&calls))
+		require.ErrorIs(t, err, errRetryable)
+		require.Equal(t, 1, calls)
+	})
+
+	t.Run("DefaultClassifier", func(t *testing.T) {
+		calls := 0
+		opts := contract.NewTxOptions(contract.WithRetry(contract.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}))
+		require.NoError(t, Retry(t.Context(), opts, retryable, failingOnce(&calls)))
+		require.Equal(t, 2, calls)
+	})
+
+	t.Run("PolicyClassifier", func(t *testing.T) {
+		calls := 0
+		opts := contract.NewTxOptions(contract.WithRetry(contract.RetryPolicy{
+			MaxAttempts: 3,
+			BaseDelay:   time.Millisecond,
+			Retryable:   func(error) bool { return false },
+		}))
+		err := Retry(t.Context(), opts, retryable, failingOnce(&calls))
+		var retryErr *db.RetryError
+		require.ErrorAs(t, err, &retryErr)
+		require.ErrorIs(t, err, errRetryable)
+		require.Equal(t, 1, calls, "the classifier of the policy wins")
+	})
+}
+
+func TestRun(t *testing.T) {
+	outcome := func(hooks *txhooks.Hooks) *string {
+		got := "none"
+		hooks.OnCommit(func() { got = "commit" })
+		hooks.OnRollback(func() { got = "rollback" })
+		return &got
+	}
+
+	t.Run("Commit", func(t *testing.T) {
+		var got *string
+		require.NoError(t, Run(func(hooks *txhooks.Hooks) error {
+			got = outcome(hooks)
+			return nil
+		}, nil))
+		require.Equal(t, "commit", *got)
+	})
+
+	t.Run("Rollback", func(t *testing.T) {
+		var got *string
+		errFailed := errors.New("failed")
+		err := Run(func(hooks *txhooks.Hooks) error {
+			got = outcome(hooks)
+			return errFailed
+		}, func() { t.Fatal("abort runs only on panics") })
+		require.ErrorIs(t, err, errFailed)
+		require.Equal(t, "rollback", *got)
+	})
+
+	t.Run("Panic", func(t *testing.T) {
+		var got *string
+		aborted := false
+		require.PanicsWithValue(t, "boom", func() {
+			_ = Run(func(hooks *txhooks.Hooks) error {
+				got = outcome(hooks)
+				panic("boom")
+			}, func() { aborted = true })
+		})
+		require.True(t, aborted)
+		require.Equal(t, "rollback", *got)
+	})
+}
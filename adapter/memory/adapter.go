@@ -0,0 +1,63 @@
+// Package memory provides an implementation of the SCG database toolkit
+// interfaces that keeps models in memory, for fast unit tests of code written
+// against contract.Connection and contract.Repository. It maps models to tables
+// like the GORM adapter and supports conditions on column comparisons,
+// ordering, pagination, preloading, soft deletes, timestamps and transactions
+// with rollback. Features that need a SQL database, such as raw SQL, joins and
+// grouping, fail with ErrNotSupported.
+package memory
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/next-trace/scg-database/adapter/internal/poolstats"
+	"github.com/next-trace/scg-database/config"
+	"github.com/next-trace/scg-database/contract"
+	"github.com/next-trace/scg-database/db"
+)
+
+// AdapterName is the name of the adapter and of its only driver.
+const AdapterName = "memory"
+
+//nolint:grouper // Only One Global Variable
+var registerOnce sync.Once
+
+type (
+	// Adapter is the in-memory implementation of the contract.DBAdapter interface.
+	Adapter struct{}
+)
+
+// Ensure Adapter satisfies the interfaces at compile time.
+var (
+	_ contract.DBAdapter    = (*Adapter)(nil)
+	_ contract.Capabilities = (*Adapter)(nil)
+)
+
+// Register registers the adapter and its query builder factory with the central
+// registry. This function is safe to call multiple times and will only register once.
+func Register() {
+	registerOnce.Do(func() {
+		db.RegisterAdapter(&Adapter{}, AdapterName)
+		db.RegisterQueryBuilderFactory(AdapterName, &QueryBuilderFactory{})
+	})
+}
+
+// Name returns the name of this database adapter
+func (a *Adapter) Name() string { return AdapterName }
+
+// Connect returns a connection to a new, empty database. The DSN is not used;
+// every call starts from scratch, so tests do not share data.
+func (a *Adapter) Connect(cfg *config.Config) (contract.Connection, error) {
+	if cfg.Driver != AdapterName {
+		return nil, fmt.Errorf("unsupported memory driver: %s (expected %s)", cfg.Driver, AdapterName)
+	}
+	return &connection{store: newStore(), health: &poolstats.Health{}}, nil
+}
+
+// Supports reports whether the memory driver supports capability, fulfilling
+// contract.Capabilities. Only savepoints, nested transactions that roll back on
+// their own, are supported.
+func (a *Adapter) Supports(driver string, capability contract.Capability) bool {
+	return driver == AdapterName && capability == contract.CapabilitySavepoints
+}
@@ -0,0 +1,58 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/next-trace/scg-database/config"
+	"github.com/next-trace/scg-database/contract"
+	"github.com/next-trace/scg-database/db"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegister(t *testing.T) {
+	Register()
+	Register() // registering twice is a no-op
+
+	adapter, err := db.GetAdapter(AdapterName)
+	require.NoError(t, err)
+	require.Equal(t, AdapterName, adapter.Name())
+
+	factory, err := db.GetQueryBuilderFactory(AdapterName)
+	require.NoError(t, err)
+	require.Equal(t, AdapterName, factory.Name())
+	require.True(t, db.Supports(AdapterName, contract.CapabilitySavepoints))
+}
+
+func TestAdapter_Connect(t *testing.T) {
+	adapter := &Adapter{}
+
+	_, err := adapter.Connect(&config.Config{Driver: "gorm:sqlite"})
+	require.EqualError(t, err, "unsupported memory driver: gorm:sqlite (expected memory)")
+
+	first, err := adapter.Connect(&config.Config{Driver: AdapterName})
+	require.NoError(t, err)
+	second, err := adapter.Connect(&config.Config{Driver: AdapterName})
+	require.NoError(t, err)
+
+	repo, err := first.NewRepository(&testUser{})
+	require.NoError(t, err)
+	require.NoError(t, repo.Create(t.Context(), newUser(1)))
+	require.Equal(t, 1, countUsers(t, first, t.Context()))
+	require.Zero(t, countUsers(t, second, t.Context()), "every connection has its own database")
+}
+
+func TestAdapter_Capabilities(t *testing.T) {
+	adapter := &Adapter{}
+
+	require.True(t, adapter.Supports(AdapterName, contract.CapabilitySavepoints))
+	for _, capability := range []contract.Capability{
+		contract.CapabilityReturning,
+		contract.CapabilityUpsert,
+		contract.CapabilityJSON,
+		contract.CapabilityRowLocking,
+		contract.CapabilityMigrations,
+	} {
+		require.False(t, adapter.Supports(AdapterName, capability), capability)
+	}
+	require.False(t, adapter.Supports("gorm:sqlite", contract.CapabilitySavepoints))
+}
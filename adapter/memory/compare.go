@@ -0,0 +1,135 @@
+package memory
+
+import (
+	"cmp"
+	"database/sql/driver"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/next-trace/scg-database/adapter/internal/schema"
+)
+
+// normalize converts a column or argument value to the form it is compared in:
+// nil, int64, uint64, float64, string, bool or time.Time. Pointers are
+// dereferenced and driver.Valuer types, such as sql.NullString, are asked for
+// their value, so a NULL one becomes nil.
+func normalize(value any) any {
+	value = schema.Indirect(value)
+	if valuer, ok := value.(driver.Valuer); ok {
+		v, err := valuer.Value()
+		if err != nil {
+			return value
+		}
+		value = schema.Indirect(v)
+	}
+
+	v := reflect.ValueOf(value)
+	switch {
+	case !v.IsValid():
+		return nil
+	case v.CanInt():
+		return v.Int()
+	case v.CanUint():
+		return v.Uint()
+	case v.CanFloat():
+		return v.Float()
+	case v.Kind() == reflect.String:
+		return v.String()
+	case v.Kind() == reflect.Bool:
+		return v.Bool()
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8:
+		return string(v.Bytes())
+	default:
+		return value
+	}
+}
+
+// compare compares two values after normalizing them. It reports false when
+// either is NULL or they cannot be compared, such as a string and a number.
+func compare(a, b any) (int, bool) {
+	a, b = normalize(a), normalize(b)
+	if a == nil || b == nil {
+		return 0, false
+	}
+	switch a := a.(type) {
+	case int64:
+		switch b := b.(type) {
+		case int64:
+			return cmp.Compare(a, b), true
+		case uint64:
+			if a < 0 {
+				return -1, true
+			}
+			return cmp.Compare(uint64(a), b), true
+		case float64:
+			return cmp.Compare(float64(a), b), true
+		}
+	case uint64:
+		switch b := b.(type) {
+		case uint64:
+			return cmp.Compare(a, b), true
+		case int64:
+			if b < 0 {
+				return 1, true
+			}
+			return cmp.Compare(a, uint64(b)), true
+		case float64:
+			return cmp.Compare(float64(a), b), true
+		}
+	case float64:
+		switch b := b.(type) {
+		case float64:
+			return cmp.Compare(a, b), true
+		case int64:
+			return cmp.Compare(a, float64(b)), true
+		case uint64:
+			return cmp.Compare(a, float64(b)), true
+		}
+	case string:
+		if b, ok := b.(string); ok {
+			return strings.Compare(a, b), true
+		}
+	case bool:
+		if b, ok := b.(bool); ok {
+			return cmp.Compare(boolRank(a), boolRank(b)), true
+		}
+	case time.Time:
+		if b, ok := b.(time.Time); ok {
+			return a.Compare(b), true
+		}
+	}
+	if reflect.TypeOf(a) == reflect.TypeOf(b) && reflect.TypeOf(a).Comparable() && a == b {
+		return 0, true
+	}
+	return 0, false
+}
+
+// equal reports whether two values compare equal.
+func equal(a, b any) bool {
+	result, ok := compare(a, b)
+	return ok && result == 0
+}
+
+// compareForSort compares two values for ORDER BY: NULL sorts before every
+// other value, and values that cannot be compared are kept in their order.
+func compareForSort(a, b any) int {
+	aNull, bNull := normalize(a) == nil, normalize(b) == nil
+	switch {
+	case aNull && bNull:
+		return 0
+	case aNull:
+		return -1
+	case bNull:
+		return 1
+	}
+	result, _ := compare(a, b)
+	return result
+}
+
+func boolRank(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
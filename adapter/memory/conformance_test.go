@@ -0,0 +1,14 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/next-trace/scg-database/contract"
+	"github.com/next-trace/scg-database/testing/conformance"
+)
+
+func TestConformance(t *testing.T) {
+	conformance.RunConformance(t, func(t *testing.T) contract.Connection {
+		return setupConnection(t)
+	})
+}
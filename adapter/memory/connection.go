@@ -0,0 +1,219 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/next-trace/scg-database/adapter/internal/poolstats"
+	"github.com/next-trace/scg-database/adapter/internal/schema"
+	"github.com/next-trace/scg-database/adapter/internal/txhooks"
+	"github.com/next-trace/scg-database/adapter/internal/txrun"
+	"github.com/next-trace/scg-database/contract"
+	"github.com/next-trace/scg-database/db"
+)
+
+type (
+	connection struct {
+		store *store
+		// journal records the writes of the transaction of the connection; it is
+		// nil outside Transaction.
+		journal *journal
+		// depth is the transaction nesting level: 0 for the root connection,
+		// 1 inside Transaction, and one more for every nested savepoint.
+		depth int
+		// hooks collects OnCommit and OnRollback callbacks of the current
+		// transaction scope; it is nil outside a transaction.
+		hooks *txhooks.Hooks
+		// health records the last Ping for Stats.
+		health *poolstats.Health
+	}
+)
+
+// Ensure the implementation satisfies the interface at compile time.
+var (
+	_ contract.Connection = (*connection)(nil)
+	_ contract.TxHooks    = (*connection)(nil)
+)
+
+func (c *connection) NewRepository(model contract.Model) (contract.Repository, error) {
+	if model == nil || reflect.ValueOf(model).IsNil() {
+		return nil, fmt.Errorf("model cannot be nil")
+	}
+	s, err := schema.Of(model)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // schema errors name the model
+	}
+	return &repository{conn: c, mdl: model, q: newQuery(s)}, nil
+}
+
+// GetConnection returns nil: there is no underlying database handle.
+func (c *connection) GetConnection() any {
+	return nil
+}
+
+// Ping fails once the connection is closed, and records the outcome for Stats.
+func (c *connection) Ping(ctx context.Context) error {
+	start := time.Now()
+	err := c.store.check(ctx)
+	c.health.Record(start, err)
+	return err
+}
+
+// Close discards every stored model. Later calls fail.
+func (c *connection) Close() error {
+	c.store.close()
+	return nil
+}
+
+// Stats reports a single pool without connection statistics, with the outcome
+// of the last Ping.
+func (c *connection) Stats() contract.Stats {
+	return contract.Stats{Pools: []contract.PoolStats{poolstats.Pool("primary", nil, c.health)}}
+}
+
+// Transaction runs fn in a transaction: if fn fails or panics, every write it
+// made through the transaction connection, or with a context carrying it (see
+// db.WithTx), is undone. Called on a connection that is already inside a
+// transaction, it behaves like a SAVEPOINT, so a failing inner function only
+// undoes its own work.
+//
+// Transactions are not isolated: other connections see their writes before
+// they commit. A rollback puts back the rows that still hold what the
+// transaction wrote; a row changed outside the transaction since keeps that
+// change. Isolation levels and read-only transactions are accepted but not
+// enforced. With a retry policy, fn is run again after db.ErrDeadlock and
+// db.ErrSerializationFailure, unless the policy has its own classifier.
+func (c *connection) Transaction(
+	ctx context.Context,
+	fn func(txConnection contract.Connection) error,
+	opts ...contract.TxOption,
+) error {
+	if c.depth > 0 {
+		return c.savepoint(ctx, fn)
+	}
+	if tx := c.ambientTx(ctx); tx != nil {
+		return tx.savepoint(ctx, fn)
+	}
+
+	txOpts := contract.NewTxOptions(opts...)
+	return txrun.Retry(ctx, txOpts, isRetryable, func(ctx context.Context) error {
+		return c.transaction(ctx, fn, txOpts)
+	})
+}
+
+// transaction runs one attempt of a root transaction.
+func (c *connection) transaction(
+	ctx context.Context,
+	fn func(txConnection contract.Connection) error,
+	txOpts contract.TxOptions,
+) error {
+	if txOpts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, txOpts.Timeout)
+		defer cancel()
+	}
+	if err := c.store.check(ctx); err != nil {
+		return err
+	}
+
+	j := &journal{}
+	return txrun.Run(func(hooks *txhooks.Hooks) error {
+		err := fn(c.child(j, 1, hooks))
+		if err == nil && ctx.Err() != nil {
+			err = fmt.Errorf("transaction timed out after %s: %w", txOpts.Timeout, ctx.Err())
+		}
+		if err != nil {
+			j.rollbackTo(c.store, 0)
+		}
+		return err
+	}, func() { j.rollbackTo(c.store, 0) })
+}
+
+// savepoint runs fn inside a savepoint of the current transaction. The writes
+// of fn are undone when it fails. A panic in fn propagates to the outermost
+// Transaction, which undoes everything.
+func (c *connection) savepoint(ctx context.Context, fn func(txConnection contract.Connection) error) error {
+	if err := c.store.check(ctx); err != nil {
+		return err
+	}
+	mark := len(c.journal.undo)
+	hooks := &txhooks.Hooks{}
+	if err := fn(c.child(c.journal, c.depth+1, hooks)); err != nil {
+		c.journal.rollbackTo(c.store, mark)
+		c.hooks.RollbackTo(hooks)
+		return err
+	}
+	c.hooks.Release(hooks)
+	return nil
+}
+
+// child returns the connection of a transaction scope.
+func (c *connection) child(j *journal, depth int, hooks *txhooks.Hooks) *connection {
+	return &connection{store: c.store, journal: j, depth: depth, hooks: hooks, health: c.health}
+}
+
+// OnCommit registers fn to run after the outermost transaction commits, fulfilling
+// contract.TxHooks. Outside a transaction there is nothing to wait for, so fn runs
+// immediately.
+func (c *connection) OnCommit(fn func()) {
+	if c.hooks == nil {
+		fn()
+		return
+	}
+	c.hooks.OnCommit(fn)
+}
+
+// OnRollback registers fn to run once the work of the current transaction scope
+// has been rolled back, fulfilling contract.TxHooks. Hooks of a rolled back
+// savepoint run when the outermost transaction finishes. Outside a transaction
+// fn never runs.
+func (c *connection) OnRollback(fn func()) {
+	if c.hooks != nil {
+		c.hooks.OnRollback(fn)
+	}
+}
+
+// Select fails with ErrNotSupported: the memory adapter does not run SQL.
+func (c *connection) Select(context.Context, string, ...any) ([]map[string]any, error) {
+	return nil, fmt.Errorf("%w: raw SQL", ErrNotSupported)
+}
+
+// Statement fails with ErrNotSupported: the memory adapter does not run SQL.
+func (c *connection) Statement(context.Context, string, ...any) (sql.Result, error) {
+	return nil, fmt.Errorf("%w: raw SQL", ErrNotSupported)
+}
+
+// journalFor returns the journal that records writes made with ctx: the one of
+// the connection, of the transaction of this database carried by ctx, or nil.
+func (c *connection) journalFor(ctx context.Context) *journal {
+	if c.journal != nil {
+		return c.journal
+	}
+	if tx := c.ambientTx(ctx); tx != nil {
+		return tx.journal
+	}
+	return nil
+}
+
+// ambientTx returns the transaction stored in ctx when it belongs to the same
+// database as c, and nil otherwise.
+func (c *connection) ambientTx(ctx context.Context) *connection {
+	ambient, ok := db.TxFromContext(ctx)
+	if !ok {
+		return nil
+	}
+	tx, ok := ambient.(*connection)
+	if !ok || tx.depth == 0 || tx.store != c.store {
+		return nil
+	}
+	return tx
+}
+
+// isRetryable reports whether err is a deadlock or a serialization failure.
+func isRetryable(err error) bool {
+	return errors.Is(err, db.ErrDeadlock) || errors.Is(err, db.ErrSerializationFailure)
+}
@@ -0,0 +1,330 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/next-trace/scg-database/contract"
+	"github.com/next-trace/scg-database/db"
+	"github.com/stretchr/testify/require"
+)
+
+// countUsers returns the number of stored users, soft deleted ones included.
+func countUsers(t *testing.T, conn contract.Connection, ctx context.Context) int {
+	t.Helper()
+	repo, err := conn.NewRepository(&testUser{})
+	require.NoError(t, err)
+	users, err := repo.Unscoped().Get(ctx)
+	require.NoError(t, err)
+	return len(users)
+}
+
+func TestConnection_Transaction(t *testing.T) {
+	conn := setupConnection(t)
+	ctx := t.Context()
+	repo, err := conn.NewRepository(&testUser{})
+	require.NoError(t, err)
+
+	t.Run("Commit", func(t *testing.T) {
+		err := conn.Transaction(ctx, func(tx contract.Connection) error {
+			txRepo, err := tx.NewRepository(&testUser{})
+			require.NoError(t, err)
+			return txRepo.Create(ctx, newUser(1))
+		})
+		require.NoError(t, err)
+		require.Equal(t, 1, countUsers(t, conn, ctx))
+	})
+
+	t.Run("Rollback", func(t *testing.T) {
+		txErr := errors.New("transaction error")
+		err := conn.Transaction(ctx, func(tx contract.Connection) error {
+			txRepo, err := tx.NewRepository(&testUser{})
+			require.NoError(t, err)
+			require.NoError(t, txRepo.Create(ctx, newUser(2)))
+
+			user, err := txRepo.FindOrFail(ctx, 1)
+			require.NoError(t, err)
+			user.(*testUser).Name = "Renamed"
+			require.NoError(t, txRepo.Update(ctx, user))
+			require.NoError(t, txRepo.Delete(ctx, user))
+			return txErr
+		})
+		require.ErrorIs(t, err, txErr)
+		require.Equal(t, 1, countUsers(t, conn, ctx))
+
+		user, err := repo.FindOrFail(ctx, 1)
+		require.NoError(t, err)
+		require.Equal(t, "User 1", user.(*testUser).Name, "updates are undone")
+		require.Nil(t, user.(*testUser).DeletedAt, "soft deletes are undone")
+	})
+
+	t.Run("NestedRollback", func(t *testing.T) {
+		err := conn.Transaction(ctx, func(tx contract.Connection) error {
+			txRepo, err := tx.NewRepository(&testUser{})
+			require.NoError(t, err)
+			require.NoError(t, txRepo.Create(ctx, newUser(3)))
+
+			innerErr := tx.Transaction(ctx, func(inner contract.Connection) error {
+				innerRepo, err := inner.NewRepository(&testUser{})
+				require.NoError(t, err)
+				require.NoError(t, innerRepo.Create(ctx, newUser(4)))
+				return errors.New("inner failure")
+			})
+			require.Error(t, innerErr)
+			require.Equal(t, 2, countUsers(t, tx, ctx), "only the savepoint is rolled back")
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, 2, countUsers(t, conn, ctx))
+	})
+
+	t.Run("Panic", func(t *testing.T) {
+		require.Panics(t, func() {
+			_ = conn.Transaction(ctx, func(tx contract.Connection) error {
+				txRepo, err := tx.NewRepository(&testUser{})
+				require.NoError(t, err)
+				require.NoError(t, txRepo.Create(ctx, newUser(5)))
+				panic("boom")
+			})
+		})
+		require.Equal(t, 2, countUsers(t, conn, ctx))
+	})
+}
+
+func TestConnection_SavepointJournal(t *testing.T) {
+	conn := setupConnection(t)
+	ctx := t.Context()
+	repo, err := conn.NewRepository(&testUser{})
+	require.NoError(t, err)
+	require.NoError(t, repo.Create(ctx, newUser(1)))
+
+	rename := func(tx contract.Connection, name string) {
+		txRepo, err := tx.NewRepository(&testUser{})
+		require.NoError(t, err)
+		require.NoError(t, txRepo.Where("id = ?", 1).QueryBuilder().Update(ctx, map[string]any{"name": name}))
+	}
+	name := func(tx contract.Connection) string {
+		txRepo, err := tx.NewRepository(&testUser{})
+		require.NoError(t, err)
+		user, err := txRepo.Unscoped().FindOrFail(ctx, 1)
+		require.NoError(t, err)
+		return user.(*testUser).Name
+	}
+
+	err = conn.Transaction(ctx, func(tx contract.Connection) error {
+		rename(tx, "Outer")
+		require.NoError(t, tx.Transaction(ctx, func(kept contract.Connection) error {
+			rename(kept, "Kept")
+			return nil
+		}))
+
+		innerErr := tx.Transaction(ctx, func(middle contract.Connection) error {
+			rename(middle, "Middle")
+			require.Error(t, middle.Transaction(ctx, func(inner contract.Connection) error {
+				rename(inner, "Inner")
+				txRepo, err := inner.NewRepository(&testUser{})
+				require.NoError(t, err)
+				require.NoError(t, txRepo.ForceDelete(ctx, &testUser{ID: 1}))
+				return errors.New("inner failure")
+			}))
+			require.Equal(t, "Middle", name(middle), "the inner savepoint is undone up to its start")
+			return errors.New("middle failure")
+		})
+		require.Error(t, innerErr)
+		require.Equal(t, "Kept", name(tx), "a released savepoint is kept when a later one is undone")
+		return errors.New("outer failure")
+	})
+	require.Error(t, err)
+	require.Equal(t, "User 1", name(conn), "the rollback undoes the released savepoints too")
+}
+
+func TestConnection_RollbackKeepsOutsideWrites(t *testing.T) {
+	conn := setupConnection(t)
+	ctx := t.Context()
+	repo, err := conn.NewRepository(&testUser{})
+	require.NoError(t, err)
+	require.NoError(t, repo.Create(ctx, newUser(1), newUser(2), newUser(3)))
+
+	err = conn.Transaction(ctx, func(tx contract.Connection) error {
+		txRepo, err := tx.NewRepository(&testUser{})
+		require.NoError(t, err)
+		require.NoError(t, txRepo.Where("id = ?", 1).QueryBuilder().Update(ctx, map[string]any{"name": "Inside"}))
+		require.NoError(t, txRepo.ForceDelete(ctx, &testUser{ID: 2}))
+		require.NoError(t, txRepo.Create(ctx, newUser(4)))
+		require.NoError(t, txRepo.Where("id = ?", 3).QueryBuilder().Update(ctx, map[string]any{"name": "Inside"}))
+
+		// Other connections see the writes and change the same rows.
+		require.NoError(t, repo.Where("id = ?", 1).QueryBuilder().Update(ctx, map[string]any{"name": "Outside"}))
+		outside := newUser(2)
+		outside.ID, outside.Name = 2, "Outside"
+		require.NoError(t, repo.Create(ctx, outside))
+		require.NoError(t, repo.Where("id = ?", 4).QueryBuilder().Update(ctx, map[string]any{"name": "Outside"}))
+		return errors.New("rollback")
+	})
+	require.Error(t, err)
+
+	name := func(id int) string {
+		user, err := repo.Unscoped().FindOrFail(ctx, id)
+		require.NoError(t, err)
+		return user.(*testUser).Name
+	}
+	require.Equal(t, "Outside", name(1), "an outside update is kept")
+	require.Equal(t, "Outside", name(2), "a row inserted outside with a deleted key is kept")
+	require.Equal(t, "Outside", name(4), "an outside update of an inserted row is kept")
+	require.Equal(t, "User 3", name(3), "rows left as written are undone")
+}
+
+func TestConnection_TransactionRetry(t *testing.T) {
+	conn := setupConnection(t)
+	policy := contract.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}
+
+	calls := 0
+	err := conn.Transaction(t.Context(), func(tx contract.Connection) error {
+		calls++
+		repo, err := tx.NewRepository(&testUser{})
+		require.NoError(t, err)
+		require.NoError(t, repo.Create(t.Context(), newUser(calls)))
+		if calls == 1 {
+			return db.NewDatabaseError(db.ErrSerializationFailure, errors.New("conflict"))
+		}
+		return nil
+	}, contract.WithRetry(policy))
+	require.NoError(t, err)
+	require.Equal(t, 2, calls)
+	require.Equal(t, 1, countUsers(t, conn, t.Context()), "the failed attempt is rolled back")
+}
+
+func TestConnection_TransactionTimeout(t *testing.T) {
+	conn := setupConnection(t)
+
+	err := conn.Transaction(t.Context(), func(tx contract.Connection) error {
+		repo, err := tx.NewRepository(&testUser{})
+		require.NoError(t, err)
+		time.Sleep(50 * time.Millisecond)
+		return repo.Create(context.Background(), newUser(1))
+	}, contract.WithTxTimeout(10*time.Millisecond))
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Zero(t, countUsers(t, conn, t.Context()), "the timed out transaction should be rolled back")
+}
+
+func TestConnection_TxHooks(t *testing.T) {
+	conn := setupConnection(t)
+	var events []string
+	record := func(event string) func() { return func() { events = append(events, event) } }
+
+	err := conn.Transaction(t.Context(), func(tx contract.Connection) error {
+		hooks := tx.(contract.TxHooks)
+		hooks.OnCommit(record("outer commit"))
+		_ = tx.Transaction(t.Context(), func(inner contract.Connection) error {
+			inner.(contract.TxHooks).OnCommit(record("inner commit"))
+			inner.(contract.TxHooks).OnRollback(record("inner rollback"))
+			return errors.New("inner failure")
+		})
+		require.Empty(t, events, "hooks wait for the outermost transaction")
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"inner rollback", "outer commit"}, events)
+
+	events = nil
+	conn.(contract.TxHooks).OnCommit(record("immediate"))
+	conn.(contract.TxHooks).OnRollback(record("never"))
+	require.Equal(t, []string{"immediate"}, events)
+}
+
+func TestConnection_AmbientTransaction(t *testing.T) {
+	conn := setupConnection(t)
+	repo, err := conn.NewRepository(&testUser{})
+	require.NoError(t, err)
+
+	err = db.InTransaction(t.Context(), conn, func(ctx context.Context) error {
+		require.NoError(t, repo.Create(ctx, newUser(1)))
+		require.NoError(t, repo.QueryBuilder().Create(ctx, newUser(2)))
+		require.NoError(t, repo.QueryBuilder().Where("name = ?", "User 1").Update(ctx, map[string]any{"age": 99}))
+
+		count, err := repo.QueryBuilder().Count(ctx)
+		require.NoError(t, err)
+		require.Equal(t, int64(2), count, "reads with the context should see the transaction's writes")
+		return errors.New("abort")
+	})
+	require.Error(t, err)
+	require.Zero(t, countUsers(t, conn, t.Context()), "the rollback should undo every write made with the context")
+
+	other := setupConnection(t)
+	err = db.InTransaction(t.Context(), other, func(ctx context.Context) error {
+		return errors.Join(repo.Create(ctx, newUser(3)), errors.New("abort"))
+	})
+	require.Error(t, err)
+	require.Equal(t, 1, countUsers(t, conn, t.Context()), "a transaction of another database is not joined")
+}
+
+func TestConnection_Concurrency(t *testing.T) {
+	conn := setupConnection(t)
+	repo, err := conn.NewRepository(&testUser{})
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := range 10 {
+		wg.Go(func() {
+			assertNoError(t, repo.Create(t.Context(), newUser(i)))
+			_, err := repo.Where("age > ?", 20).Get(t.Context())
+			assertNoError(t, err)
+		})
+	}
+	wg.Wait()
+	require.Equal(t, 10, countUsers(t, conn, t.Context()))
+}
+
+func TestConnection_Close(t *testing.T) {
+	conn := setupConnection(t)
+	repo, err := conn.NewRepository(&testUser{})
+	require.NoError(t, err)
+	require.NoError(t, repo.Create(t.Context(), newUser(1)))
+
+	require.NoError(t, conn.Close())
+	require.Error(t, conn.Ping(t.Context()))
+	_, err = repo.Get(t.Context())
+	require.EqualError(t, err, "memory: database is closed")
+	require.EqualError(t, repo.Create(t.Context(), newUser(2)), "memory: database is closed")
+	require.False(t, conn.Stats().Healthy())
+}
+
+func TestConnection_RawQueries(t *testing.T) {
+	conn := setupConnection(t)
+
+	_, err := conn.Select(t.Context(), "SELECT 1")
+	require.ErrorIs(t, err, ErrNotSupported)
+	_, err = conn.Statement(t.Context(), "DELETE FROM users")
+	require.ErrorIs(t, err, ErrNotSupported)
+}
+
+func TestConnection_Stats(t *testing.T) {
+	conn := setupConnection(t)
+
+	require.NoError(t, conn.Ping(t.Context()))
+	pool := conn.Stats().Pools[0]
+	require.Equal(t, "primary", pool.Name)
+	require.False(t, pool.LastPingAt.IsZero())
+	require.True(t, conn.Stats().Healthy())
+}
+
+func TestConnection_NewRepository(t *testing.T) {
+	conn := setupConnection(t)
+	_, err := conn.NewRepository(nil)
+	require.EqualError(t, err, "model cannot be nil")
+	var user *testUser
+	_, err = conn.NewRepository(user)
+	require.EqualError(t, err, "model cannot be nil")
+	require.Nil(t, conn.GetConnection())
+}
+
+// assertNoError reports err from a goroutine other than the test's.
+func assertNoError(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,223 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/next-trace/scg-database/adapter/internal/schema"
+	"github.com/next-trace/scg-database/contract"
+	"github.com/next-trace/scg-database/db"
+)
+
+//nolint:grouper // Only One Global Variable
+var mapType = reflect.TypeFor[map[string]any]()
+
+// fetch returns copies of the rows of q, pointers to model structs, with the
+// relationships of q preloaded.
+func (c *connection) fetch(ctx context.Context, q *query) ([]reflect.Value, error) {
+	if q.err != nil {
+		return nil, q.err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err //nolint:wrapcheck // context errors are returned as is
+	}
+	var rows []reflect.Value
+	err := c.store.read(func() error {
+		rows = q.filter(c.store.rows(q.schema))
+		return c.preload(q.schema, rows, q.preloads)
+	})
+	return rows, err
+}
+
+// fetchFirst returns the first row of q, in primary key order unless q is
+// ordered, or an invalid value when there is none.
+func (c *connection) fetchFirst(ctx context.Context, q *query) (reflect.Value, error) {
+	if len(q.orders) == 0 {
+		q = q.orderBy(q.schema.PrimaryKey.Name, OrderDirectionASC)
+	}
+	rows, err := c.fetch(ctx, q.withLimit(1))
+	if err != nil || len(rows) == 0 {
+		return reflect.Value{}, err
+	}
+	return rows[0], nil
+}
+
+// preload loads the named relationships of the owner rows, pointers to structs
+// of s, from the tables of the related models. Callers hold a lock of the store.
+func (c *connection) preload(s *schema.Schema, rows []reflect.Value, relations []string) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	for _, name := range relations {
+		rel, err := s.Relation(name)
+		if err != nil {
+			return err //nolint:wrapcheck // relation errors name the relationship
+		}
+		if rel.JoinTable != "" {
+			return fmt.Errorf("%w: many-to-many relationship %q", ErrNotSupported, name)
+		}
+
+		related := newQuery(rel.Related)
+		related = related.where(map[string]any{rel.RelatedKey: schema.DistinctKeys(rows, s.ByName[rel.OwnerKey])}, nil, false)
+		if related.err != nil {
+			return related.err
+		}
+		byKey := make(map[string][]reflect.Value)
+		relatedKey := rel.Related.ByName[rel.RelatedKey]
+		for _, row := range related.filter(c.store.rows(rel.Related)) {
+			key := schema.KeyOf(relatedKey.Value(row.Elem()))
+			byKey[key] = append(byKey[key], row)
+		}
+
+		ownerKey := s.ByName[rel.OwnerKey]
+		for _, row := range rows {
+			if err := rel.Assign(row, byKey[schema.KeyOf(ownerKey.Value(row.Elem()))]); err != nil {
+				return err //nolint:wrapcheck // assignment errors name the relationship
+			}
+		}
+	}
+	return nil
+}
+
+// load reads the rows of q into dest, which points to a model struct, a
+// map[string]any, a scalar of the single selected column, or a slice of them.
+// With first, only the first row is read and db.ErrRecordNotFound is returned
+// when there is none.
+func (c *connection) load(ctx context.Context, q *query, dest any, first bool) error {
+	dv := reflect.ValueOf(dest)
+	if dv.Kind() != reflect.Pointer || dv.IsNil() {
+		return fmt.Errorf("destination must be a non-nil pointer, got %T", dest)
+	}
+	target := dv.Elem()
+	elemType := target.Type()
+	isSlice := target.Kind() == reflect.Slice && elemType.Elem().Kind() != reflect.Uint8
+	if isSlice {
+		elemType = elemType.Elem()
+	}
+
+	var (
+		rows []reflect.Value
+		err  error
+	)
+	if first {
+		var row reflect.Value
+		if row, err = c.fetchFirst(ctx, q); row.IsValid() {
+			rows = []reflect.Value{row}
+		}
+	} else {
+		rows, err = c.fetch(ctx, q)
+	}
+	if err != nil {
+		return err
+	}
+
+	values := make([]reflect.Value, len(rows))
+	for i, row := range rows {
+		if values[i], err = q.convert(row, elemType); err != nil {
+			return err
+		}
+	}
+	switch {
+	case isSlice:
+		slice := reflect.MakeSlice(target.Type(), len(values), len(values))
+		for i, value := range values {
+			slice.Index(i).Set(value)
+		}
+		target.Set(slice)
+	case len(values) > 0:
+		target.Set(values[0])
+	case first:
+		return db.ErrRecordNotFound
+	}
+	return nil
+}
+
+// convert returns row, a pointer to a model struct, as a value of typ: the
+// struct or a pointer to it with only the selected columns set, a map of the
+// selected columns, or the value of the single selected column.
+func (q *query) convert(row reflect.Value, typ reflect.Type) (reflect.Value, error) {
+	selects := q.selects
+	if len(selects) == 0 {
+		selects = q.schema.Columns
+	}
+
+	switch {
+	case typ == q.schema.Type || (typ.Kind() == reflect.Pointer && typ.Elem() == q.schema.Type):
+		if len(q.selects) > 0 {
+			selected := reflect.New(q.schema.Type)
+			for _, col := range selects {
+				if err := col.Set(selected.Elem(), col.Value(row.Elem())); err != nil {
+					return reflect.Value{}, err //nolint:wrapcheck // column errors name the column
+				}
+			}
+			row = selected
+		}
+		if typ.Kind() == reflect.Pointer {
+			return row, nil
+		}
+		return row.Elem(), nil
+	case typ == mapType:
+		m := make(map[string]any, len(selects))
+		for _, col := range selects {
+			m[col.Name] = col.Value(row.Elem())
+		}
+		return reflect.ValueOf(m), nil
+	case len(q.selects) == 1:
+		return convertValue(q.selects[0].Value(row.Elem()), typ)
+	default:
+		return reflect.Value{}, fmt.Errorf("cannot read %s rows into %s: select a single column", q.schema.Type, typ)
+	}
+}
+
+// convertValue converts a column value to typ; nil becomes the zero value.
+func convertValue(value any, typ reflect.Type) (reflect.Value, error) {
+	v := reflect.ValueOf(value)
+	switch {
+	case !v.IsValid():
+		return reflect.Zero(typ), nil
+	case v.Type().AssignableTo(typ):
+		return v, nil
+	case v.Kind() == reflect.Pointer && !v.IsNil():
+		return convertValue(v.Elem().Interface(), typ)
+	case v.Kind() == reflect.Pointer:
+		return reflect.Zero(typ), nil
+	case v.Type().ConvertibleTo(typ) && (v.Kind() == typ.Kind() ||
+		(schema.IsNumeric(v.Kind()) && schema.IsNumeric(typ.Kind()))):
+		return v.Convert(typ), nil
+	default:
+		return reflect.Value{}, fmt.Errorf("cannot assign %s to %s", v.Type(), typ)
+	}
+}
+
+// modelsOf returns value as models: a model, a slice of models, or a pointer to
+// a slice of model structs.
+func modelsOf(value any) ([]contract.Model, error) {
+	switch value := value.(type) {
+	case contract.Model:
+		return []contract.Model{value}, nil
+	case []contract.Model:
+		return value, nil
+	}
+
+	v := reflect.ValueOf(value)
+	if v.Kind() == reflect.Pointer && v.Elem().Kind() == reflect.Slice {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("cannot create %T: expected a model or a slice of models", value)
+	}
+	models := make([]contract.Model, 0, v.Len())
+	for i := range v.Len() {
+		elem := v.Index(i)
+		if elem.Kind() != reflect.Pointer {
+			elem = elem.Addr()
+		}
+		model, ok := elem.Interface().(contract.Model)
+		if !ok {
+			return nil, fmt.Errorf("cannot create %T: %s is not a model", value, elem.Type())
+		}
+		models = append(models, model)
+	}
+	return models, nil
+}
@@ -0,0 +1,443 @@
+package memory
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/next-trace/scg-database/adapter/internal/schema"
+	"github.com/next-trace/scg-database/contract"
+)
+
+// Direction constants of OrderBy
+const (
+	OrderDirectionASC  = "ASC"
+	OrderDirectionDESC = "DESC"
+)
+
+// Errors of the memory adapter
+var (
+	// ErrNotSupported is returned for features that need a SQL database, such
+	// as raw SQL, joins and grouping.
+	ErrNotSupported = errors.New("not supported by the memory adapter")
+	// ErrMissingWhereClause is returned by query builder updates and deletes
+	// without conditions, which would otherwise change every row.
+	ErrMissingWhereClause = errors.New("WHERE conditions required")
+)
+
+// Comparison operators of a clause
+const (
+	opEqual     = "="
+	opNotEqual  = "!="
+	opLess      = "<"
+	opLessEq    = "<="
+	opGreater   = ">"
+	opGreaterEq = ">="
+	opIn        = "IN"
+	opNotIn     = "NOT IN"
+	opNull      = "IS NULL"
+	opNotNull   = "IS NOT NULL"
+	opBetween   = "BETWEEN"
+)
+
+type (
+	// clause compares one column with its values.
+	clause struct {
+		column *schema.Column
+		op     string
+		values []any
+	}
+
+	// condition is the conjunction of the clauses of one Where call.
+	condition struct {
+		clauses []clause
+		// or joins the condition to the previous ones with OR instead of AND.
+		or bool
+	}
+
+	// order is one OrderBy column.
+	order struct {
+		column *schema.Column
+		desc   bool
+	}
+
+	// query holds the conditions on the table of a model. Its methods return
+	// modified copies, so queries can be shared by chains.
+	query struct {
+		schema   *schema.Schema
+		selects  []*schema.Column
+		wheres   []condition
+		orders   []order
+		limit    int // -1 when unset
+		offset   int // -1 when unset
+		unscoped bool
+		preloads []string
+		// err is the first error of the chain, reported when the query runs.
+		err error
+	}
+)
+
+// clausePattern matches one clause of a SQL condition: a column followed by a
+// comparison with `?` placeholders, and the AND that joins it to the next one.
+//
+//nolint:grouper // Only One Global Variable
+var clausePattern = regexp.MustCompile(`(?i)^\s*([\w.]+)\s*(?:` +
+	`(is\s+not\s+null|is\s+null)|` +
+	`(between)\s+\?\s+and\s+\?|` +
+	`(=|!=|<>|<=|>=|<|>|not\s+in|in)\s*(?:\(\s*\?\s*\)|\?))` +
+	`\s*(?:and\s+|$)`)
+
+func newQuery(s *schema.Schema) *query {
+	return &query{schema: s, limit: -1, offset: -1}
+}
+
+// clone returns a copy of q whose slices are clipped, so appending to the copy
+// never writes to the backing arrays of q.
+func (q *query) clone() *query {
+	c := *q
+	c.selects = slices.Clip(q.selects)
+	c.wheres = slices.Clip(q.wheres)
+	c.orders = slices.Clip(q.orders)
+	c.preloads = slices.Clip(q.preloads)
+	return &c
+}
+
+// fail returns a copy of q that reports err when it runs.
+func (q *query) fail(err error) *query {
+	c := q.clone()
+	if c.err == nil {
+		c.err = err
+	}
+	return c
+}
+
+// where adds a condition. cond is a SQL condition made of column comparisons
+// joined by AND, such as "age > ? AND name = ?", a map of column values, a model
+// whose non-zero fields must match, or a primary key value (a slice of them
+// matches any).
+func (q *query) where(cond any, args []any, or bool) *query {
+	clauses, err := q.clauses(cond, args)
+	if err != nil {
+		return q.fail(err)
+	}
+	if len(clauses) == 0 {
+		return q
+	}
+	c := q.clone()
+	c.wheres = append(c.wheres, condition{clauses: clauses, or: or})
+	return c
+}
+
+func (q *query) clauses(cond any, args []any) ([]clause, error) {
+	switch cond := cond.(type) {
+	case string:
+		return q.parse(cond, args)
+	case map[string]any:
+		return q.mapClauses(cond)
+	case contract.Model:
+		return q.modelClauses(cond)
+	case nil:
+		return nil, errors.New("where condition cannot be nil")
+	default:
+		if isList(cond) {
+			return []clause{{column: q.schema.PrimaryKey, op: opIn, values: listValues(cond)}}, nil
+		}
+		return []clause{{column: q.schema.PrimaryKey, op: opEqual, values: []any{cond}}}, nil
+	}
+}
+
+// parse reads the clauses of a SQL condition.
+func (q *query) parse(sql string, args []any) ([]clause, error) {
+	var clauses []clause
+	for rest := strings.TrimSpace(sql); rest != ""; {
+		m := clausePattern.FindStringSubmatch(rest)
+		if m == nil {
+			return nil, fmt.Errorf("%w: condition %q; use column comparisons joined by AND", ErrNotSupported, sql)
+		}
+		rest = rest[len(m[0]):]
+
+		col, err := q.column(m[1])
+		if err != nil {
+			return nil, err
+		}
+		op, placeholders := normalizeOp(m[2]+m[3]+m[4]), 1
+		switch op {
+		case opNull, opNotNull:
+			placeholders = 0
+		case opBetween:
+			placeholders = 2
+		}
+		if len(args) < placeholders {
+			return nil, fmt.Errorf("not enough arguments for condition %q", sql)
+		}
+
+		values := args[:placeholders]
+		if op == opIn || op == opNotIn {
+			values = listValues(args[0])
+		}
+		clauses = append(clauses, clause{column: col, op: op, values: values})
+		args = args[placeholders:]
+	}
+	if len(args) > 0 {
+		return nil, fmt.Errorf("too many arguments for condition %q", sql)
+	}
+	return clauses, nil
+}
+
+// mapClauses matches the columns of m in key order; nil matches NULL and
+// slices match any of their values.
+func (q *query) mapClauses(m map[string]any) ([]clause, error) {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	clauses := make([]clause, 0, len(m))
+	for _, key := range keys {
+		col, err := q.column(key)
+		if err != nil {
+			return nil, err
+		}
+		switch value := m[key]; {
+		case value == nil:
+			clauses = append(clauses, clause{column: col, op: opNull})
+		case isList(value):
+			clauses = append(clauses, clause{column: col, op: opIn, values: listValues(value)})
+		default:
+			clauses = append(clauses, clause{column: col, op: opEqual, values: []any{value}})
+		}
+	}
+	return clauses, nil
+}
+
+// modelClauses matches the non-zero fields of model, as GORM does.
+func (q *query) modelClauses(model contract.Model) ([]clause, error) {
+	s, err := schema.Of(model)
+	if err != nil {
+		return nil, err
+	}
+	v := reflect.ValueOf(model).Elem()
+	var clauses []clause
+	for _, col := range s.Columns {
+		if col.IsZero(v) {
+			continue
+		}
+		own, err := q.column(col.Name)
+		if err != nil {
+			return nil, err
+		}
+		clauses = append(clauses, clause{column: own, op: opEqual, values: []any{col.Value(v)}})
+	}
+	return clauses, nil
+}
+
+// column returns the column of name, which may be qualified with the table.
+func (q *query) column(name string) (*schema.Column, error) {
+	if table, column, found := strings.Cut(name, "."); found && table == q.schema.Table {
+		name = column
+	}
+	col, ok := q.schema.ByName[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown column %q of %s", name, q.schema.Table)
+	}
+	return col, nil
+}
+
+// orderBy adds an ORDER BY column. Invalid directions fall back to ASC.
+func (q *query) orderBy(column, direction string) *query {
+	col, err := q.column(strings.TrimSpace(column))
+	if err != nil {
+		return q.fail(err)
+	}
+	c := q.clone()
+	c.orders = append(c.orders, order{
+		column: col,
+		desc:   strings.EqualFold(strings.TrimSpace(direction), OrderDirectionDESC),
+	})
+	return c
+}
+
+// withLimit sets the LIMIT; negative values leave the query unchanged.
+func (q *query) withLimit(limit int) *query {
+	if limit < 0 {
+		return q
+	}
+	c := q.clone()
+	c.limit = limit
+	return c
+}
+
+// withOffset sets the OFFSET; negative values leave the query unchanged.
+func (q *query) withOffset(offset int) *query {
+	if offset < 0 {
+		return q
+	}
+	c := q.clone()
+	c.offset = offset
+	return c
+}
+
+// withUnscoped disables the soft delete scope.
+func (q *query) withUnscoped() *query {
+	c := q.clone()
+	c.unscoped = true
+	return c
+}
+
+// withPreload loads the given relationships after the query runs.
+func (q *query) withPreload(relations ...string) *query {
+	c := q.clone()
+	c.preloads = append(c.preloads, relations...)
+	return c
+}
+
+// scoped reports whether soft deleted rows are excluded.
+func (q *query) scoped() bool {
+	return q.schema.SoftDelete && !q.unscoped
+}
+
+// filter returns the rows, pointers to structs, that match the conditions of
+// q, sorted and paginated.
+func (q *query) filter(rows []reflect.Value) []reflect.Value {
+	matched := rows[:0:0]
+	for _, row := range rows {
+		if q.matches(row.Elem()) {
+			matched = append(matched, row)
+		}
+	}
+	q.sort(matched)
+
+	if q.offset > 0 {
+		matched = matched[min(q.offset, len(matched)):]
+	}
+	if q.limit >= 0 {
+		matched = matched[:min(q.limit, len(matched))]
+	}
+	return matched
+}
+
+// matches reports whether the struct v satisfies the conditions. AND binds
+// tighter than OR, as in SQL; the soft delete scope applies to the whole.
+func (q *query) matches(v reflect.Value) bool {
+	if q.scoped() && !q.schema.ByName[schema.DeletedAtColumn].IsZero(v) {
+		return false
+	}
+	if len(q.wheres) == 0 {
+		return true
+	}
+	group := true
+	for i, cond := range q.wheres {
+		if i > 0 && cond.or {
+			if group {
+				return true
+			}
+			group = true
+		}
+		group = group && cond.matches(v)
+	}
+	return group
+}
+
+func (cond condition) matches(v reflect.Value) bool {
+	for _, c := range cond.clauses {
+		if !c.matches(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// matches compares the column of c in the struct v with the values of c.
+// As in SQL, comparisons with NULL are false.
+func (c clause) matches(v reflect.Value) bool {
+	value := normalize(c.column.Value(v))
+	switch c.op {
+	case opNull:
+		return value == nil
+	case opNotNull:
+		return value != nil
+	case opIn, opNotIn:
+		if value == nil {
+			return false
+		}
+		found := slices.ContainsFunc(c.values, func(other any) bool { return equal(value, other) })
+		return found == (c.op == opIn)
+	case opBetween:
+		low, okLow := compare(value, c.values[0])
+		high, okHigh := compare(value, c.values[1])
+		return okLow && okHigh && low >= 0 && high <= 0
+	}
+
+	result, ok := compare(value, c.values[0])
+	if !ok {
+		return false
+	}
+	switch c.op {
+	case opEqual:
+		return result == 0
+	case opNotEqual:
+		return result != 0
+	case opLess:
+		return result < 0
+	case opLessEq:
+		return result <= 0
+	case opGreater:
+		return result > 0
+	default: // opGreaterEq
+		return result >= 0
+	}
+}
+
+// sort orders rows by the OrderBy columns; rows that compare equal keep their
+// order. NULL sorts first in ascending order, as in MySQL and SQLite.
+func (q *query) sort(rows []reflect.Value) {
+	if len(q.orders) == 0 {
+		return
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		for _, o := range q.orders {
+			result := compareForSort(o.column.Value(rows[i].Elem()), o.column.Value(rows[j].Elem()))
+			if result == 0 {
+				continue
+			}
+			if o.desc {
+				return result > 0
+			}
+			return result < 0
+		}
+		return false
+	})
+}
+
+// normalizeOp returns the canonical form of a comparison operator.
+func normalizeOp(op string) string {
+	op = strings.ToUpper(strings.Join(strings.Fields(op), " "))
+	if op == "<>" {
+		return opNotEqual
+	}
+	return op
+}
+
+// isList reports whether value is a slice or array of values, but not []byte.
+func isList(value any) bool {
+	v := reflect.ValueOf(value)
+	return (v.Kind() == reflect.Slice || v.Kind() == reflect.Array) && v.Type().Elem().Kind() != reflect.Uint8
+}
+
+// listValues returns the elements of a slice, or value itself when it is not one.
+func listValues(value any) []any {
+	if !isList(value) {
+		return []any{value}
+	}
+	v := reflect.ValueOf(value)
+	values := make([]any, v.Len())
+	for i := range values {
+		values[i] = v.Index(i).Interface()
+	}
+	return values
+}
@@ -0,0 +1,287 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/next-trace/scg-database/adapter/internal/schema"
+	"github.com/next-trace/scg-database/contract"
+)
+
+type (
+	// queryBuilder implements the contract.QueryBuilder interface on the stored
+	// rows. Conditions are limited to column comparisons; joins, grouping and
+	// raw SQL fail with ErrNotSupported when the query runs.
+	queryBuilder struct {
+		conn  *connection
+		model contract.Model
+		q     *query
+	}
+)
+
+// Ensure queryBuilder implements contract.QueryBuilder
+var (
+	_ contract.QueryBuilder = (*queryBuilder)(nil)
+)
+
+// newQueryBuilder creates a query builder for model on a connection of this adapter.
+func newQueryBuilder(model contract.Model, conn any) *queryBuilder {
+	c, ok := conn.(*connection)
+	if !ok {
+		panic("connection must be a contract.Connection opened by the memory adapter")
+	}
+	s, err := schema.Of(model)
+	if err != nil {
+		panic(fmt.Sprintf("invalid query builder model: %v", err))
+	}
+	return &queryBuilder{conn: c, model: model, q: newQuery(s)}
+}
+
+// with returns a query builder of the same model using q.
+func (b *queryBuilder) with(q *query) contract.QueryBuilder {
+	return &queryBuilder{conn: b.conn, model: b.model, q: q}
+}
+
+// unsupported returns a builder that fails with ErrNotSupported for feature.
+func (b *queryBuilder) unsupported(feature string) contract.QueryBuilder {
+	return b.with(b.q.fail(fmt.Errorf("%w: %s", ErrNotSupported, feature)))
+}
+
+// Query building methods
+
+// Select restricts the columns read by Find, First and Get. Only column names
+// are supported, not expressions.
+func (b *queryBuilder) Select(columns ...string) contract.QueryBuilder {
+	selects := make([]*schema.Column, 0, len(columns))
+	for _, name := range columns {
+		col, err := b.q.column(name)
+		if err != nil {
+			return b.with(b.q.fail(err))
+		}
+		selects = append(selects, col)
+	}
+	q := b.q.clone()
+	q.selects = selects
+	return b.with(q)
+}
+
+func (b *queryBuilder) Where(condition string, args ...any) contract.QueryBuilder {
+	return b.with(b.q.where(condition, args, false))
+}
+
+func (b *queryBuilder) WhereIn(column string, values []any) contract.QueryBuilder {
+	return b.with(b.q.where(column+" IN ?", []any{values}, false))
+}
+
+func (b *queryBuilder) WhereNotIn(column string, values []any) contract.QueryBuilder {
+	return b.with(b.q.where(column+" NOT IN ?", []any{values}, false))
+}
+
+func (b *queryBuilder) WhereNull(column string) contract.QueryBuilder {
+	return b.with(b.q.where(column+" IS NULL", nil, false))
+}
+
+func (b *queryBuilder) WhereNotNull(column string) contract.QueryBuilder {
+	return b.with(b.q.where(column+" IS NOT NULL", nil, false))
+}
+
+func (b *queryBuilder) WhereBetween(column string, start, end any) contract.QueryBuilder {
+	return b.with(b.q.where(column+" BETWEEN ? AND ?", []any{start, end}, false))
+}
+
+func (b *queryBuilder) OrWhere(condition string, args ...any) contract.QueryBuilder {
+	return b.with(b.q.where(condition, args, true))
+}
+
+// Join methods
+
+func (b *queryBuilder) Join(string, string) contract.QueryBuilder {
+	return b.unsupported("joins")
+}
+
+func (b *queryBuilder) LeftJoin(string, string) contract.QueryBuilder {
+	return b.unsupported("joins")
+}
+
+func (b *queryBuilder) RightJoin(string, string) contract.QueryBuilder {
+	return b.unsupported("joins")
+}
+
+func (b *queryBuilder) InnerJoin(string, string) contract.QueryBuilder {
+	return b.unsupported("joins")
+}
+
+// Ordering and grouping
+
+func (b *queryBuilder) OrderBy(column, direction string) contract.QueryBuilder {
+	return b.with(b.q.orderBy(column, direction))
+}
+
+func (b *queryBuilder) GroupBy(...string) contract.QueryBuilder {
+	return b.unsupported("GROUP BY")
+}
+
+func (b *queryBuilder) Having(string, ...any) contract.QueryBuilder {
+	return b.unsupported("HAVING")
+}
+
+// Limiting and pagination
+
+func (b *queryBuilder) Limit(limit int) contract.QueryBuilder {
+	return b.with(b.q.withLimit(limit))
+}
+
+func (b *queryBuilder) Offset(offset int) contract.QueryBuilder {
+	return b.with(b.q.withOffset(offset))
+}
+
+// Relationships
+
+func (b *queryBuilder) With(relations ...string) contract.QueryBuilder {
+	return b.with(b.q.withPreload(relations...))
+}
+
+// WithCount is accepted for compatibility and, as in the GORM adapter, does not
+// change the query.
+func (b *queryBuilder) WithCount(...string) contract.QueryBuilder {
+	return b
+}
+
+// Scopes and advanced features
+
+// Scoped restores the soft delete scope removed by Unscoped.
+func (b *queryBuilder) Scoped() contract.QueryBuilder {
+	q := b.q.clone()
+	q.unscoped = false
+	return b.with(q)
+}
+
+func (b *queryBuilder) Unscoped() contract.QueryBuilder {
+	return b.with(b.q.withUnscoped())
+}
+
+// Execution methods
+
+// Find reads the matching rows into dest: a pointer to a slice of structs,
+// struct pointers, maps or scalars, or to a single one of them.
+func (b *queryBuilder) Find(ctx context.Context, dest any) error {
+	return b.conn.load(ctx, b.q, dest, false)
+}
+
+// First reads the first row in primary key order into dest, and returns
+// db.ErrRecordNotFound when there is none.
+func (b *queryBuilder) First(ctx context.Context, dest any) error {
+	return b.conn.load(ctx, b.q, dest, true)
+}
+
+func (b *queryBuilder) Get(ctx context.Context, dest any) error {
+	return b.conn.load(ctx, b.q, dest, false)
+}
+
+// Count counts the matching rows, ignoring Limit and Offset as GORM does.
+func (b *queryBuilder) Count(ctx context.Context) (int64, error) {
+	q := b.q.clone()
+	q.limit, q.offset, q.preloads = -1, -1, nil
+	rows, err := b.conn.fetch(ctx, q)
+	return int64(len(rows)), err
+}
+
+func (b *queryBuilder) Exists(ctx context.Context) (bool, error) {
+	count, err := b.Count(ctx)
+	return count > 0, err
+}
+
+// Mutation methods
+
+// Create inserts value, a model or a slice of models of the builder model type.
+func (b *queryBuilder) Create(ctx context.Context, value any) error {
+	models, err := modelsOf(value)
+	if err != nil {
+		return err
+	}
+	repo := &repository{conn: b.conn, mdl: b.model, q: b.q}
+	return repo.Create(ctx, models...)
+}
+
+// Update sets the columns of values, a map or a struct whose non-zero fields
+// are used, on the matching rows. UpdatedAt is refreshed for models with
+// timestamps.
+func (b *queryBuilder) Update(ctx context.Context, values any) error {
+	repo := &repository{conn: b.conn, mdl: b.model, q: b.q}
+	assignments, err := repo.assignments(values)
+	if err != nil {
+		return err
+	}
+	if len(assignments) == 0 {
+		return errors.New("no columns to update")
+	}
+	hasUpdatedAt := slices.ContainsFunc(assignments, func(a assignment) bool {
+		return a.column.Name == schema.UpdatedAtColumn
+	})
+	if b.q.schema.Timestamps && !hasUpdatedAt {
+		assignments = append(assignments, assignment{
+			column: b.q.schema.ByName[schema.UpdatedAtColumn],
+			value:  time.Now(),
+		})
+	}
+	return b.conn.update(ctx, b.q, assignments)
+}
+
+// Delete deletes the matching rows, or marks them deleted for models with
+// soft deletes.
+func (b *queryBuilder) Delete(ctx context.Context) error {
+	return b.conn.delete(ctx, b.q, time.Now())
+}
+
+// Raw query methods
+
+// Raw fails with ErrNotSupported when the query runs: the memory adapter does
+// not run SQL.
+func (b *queryBuilder) Raw(string, ...any) contract.QueryBuilder {
+	return b.unsupported("raw SQL")
+}
+
+// Exec fails with ErrNotSupported: the memory adapter does not run SQL.
+func (b *queryBuilder) Exec(context.Context, string, ...any) error {
+	return fmt.Errorf("%w: raw SQL", ErrNotSupported)
+}
+
+// Utility methods
+
+// ToSQL fails with ErrNotSupported: queries of the memory adapter have no SQL.
+func (b *queryBuilder) ToSQL() (sql string, args []any, err error) {
+	return "", nil, fmt.Errorf("%w: ToSQL", ErrNotSupported)
+}
+
+// Clone returns an independent copy of the builder.
+func (b *queryBuilder) Clone() contract.QueryBuilder {
+	return b.with(b.q.clone())
+}
+
+// Reset returns a builder of the same model without any clauses.
+func (b *queryBuilder) Reset() contract.QueryBuilder {
+	return b.with(newQuery(b.q.schema))
+}
+
+type (
+	// QueryBuilderFactory implements contract.QueryBuilderFactory for the memory
+	// adapter. The connection passed to NewQueryBuilder must be a
+	// contract.Connection opened by this adapter.
+	QueryBuilderFactory struct{}
+)
+
+// Ensure QueryBuilderFactory implements contract.QueryBuilderFactory
+var (
+	_ contract.QueryBuilderFactory = (*QueryBuilderFactory)(nil)
+)
+
+func (f *QueryBuilderFactory) NewQueryBuilder(model contract.Model, connection any) contract.QueryBuilder {
+	return newQueryBuilder(model, connection)
+}
+
+func (f *QueryBuilderFactory) Name() string {
+	return AdapterName
+}
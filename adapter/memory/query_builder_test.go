@@ -0,0 +1,69 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/next-trace/scg-database/contract"
+	"github.com/next-trace/scg-database/db"
+	"github.com/stretchr/testify/require"
+)
+
+// setupQueryBuilder returns a query builder of testUser with three users.
+func setupQueryBuilder(t *testing.T) contract.QueryBuilder {
+	t.Helper()
+	conn := setupConnection(t)
+	repo, err := conn.NewRepository(&testUser{})
+	require.NoError(t, err)
+	require.NoError(t, repo.Create(t.Context(), newUser(1), newUser(2), newUser(3)))
+
+	factory, err := db.GetQueryBuilderFactory(AdapterName)
+	require.NoError(t, err)
+	return factory.NewQueryBuilder(&testUser{}, conn)
+}
+
+func TestQueryBuilder_Unsupported(t *testing.T) {
+	qb := setupQueryBuilder(t)
+	ctx := t.Context()
+
+	var users []testUser
+	tests := map[string]contract.QueryBuilder{
+		"join":      qb.Join("posts", "posts.user_id = users.id"),
+		"left join": qb.LeftJoin("posts", "posts.user_id = users.id"),
+		"group by":  qb.GroupBy("age"),
+		"having":    qb.Having("COUNT(*) > ?", 1),
+		"raw":       qb.Raw("SELECT * FROM users"),
+	}
+	for name, unsupported := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.ErrorIs(t, unsupported.Find(ctx, &users), ErrNotSupported)
+			_, err := unsupported.Count(ctx)
+			require.ErrorIs(t, err, ErrNotSupported)
+		})
+	}
+
+	require.ErrorIs(t, qb.Exec(ctx, "DELETE FROM users"), ErrNotSupported)
+	_, _, err := qb.ToSQL()
+	require.ErrorIs(t, err, ErrNotSupported)
+}
+
+func TestQueryBuilder_MissingWhereClause(t *testing.T) {
+	qb := setupQueryBuilder(t)
+	ctx := t.Context()
+
+	require.ErrorIs(t, qb.Update(ctx, map[string]any{"name": "All"}), ErrMissingWhereClause)
+	require.ErrorIs(t, qb.Delete(ctx), ErrMissingWhereClause)
+	count, err := qb.Where("name = ?", "All").Count(ctx)
+	require.NoError(t, err)
+	require.Zero(t, count, "nothing is updated")
+	count, err = qb.Count(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), count, "nothing is deleted")
+}
+
+func TestQueryBuilderFactory(t *testing.T) {
+	factory := &QueryBuilderFactory{}
+	require.Equal(t, AdapterName, factory.Name())
+	require.PanicsWithValue(t, "connection must be a contract.Connection opened by the memory adapter", func() {
+		factory.NewQueryBuilder(&testUser{}, "not a connection")
+	})
+}
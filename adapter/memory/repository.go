@@ -0,0 +1,455 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/next-trace/scg-database/adapter/internal/schema"
+	"github.com/next-trace/scg-database/contract"
+	"github.com/next-trace/scg-database/db"
+)
+
+type (
+	repository struct {
+		conn *connection
+		mdl  contract.Model
+		q    *query
+	}
+
+	// assignment sets one column of an update.
+	assignment struct {
+		column *schema.Column
+		value  any
+	}
+)
+
+//nolint:grouper // Only One Global Variable
+var _ contract.Repository = (*repository)(nil)
+
+// with returns a repository of the same model using q.
+func (r *repository) with(q *query) contract.Repository {
+	return &repository{conn: r.conn, mdl: r.mdl, q: q}
+}
+
+// --- Query Building ---
+func (r *repository) With(relations ...string) contract.Repository {
+	return r.with(r.q.withPreload(relations...))
+}
+
+func (r *repository) Where(query any, args ...any) contract.Repository {
+	return r.with(r.q.where(query, args, false))
+}
+
+func (r *repository) Unscoped() contract.Repository {
+	return r.with(r.q.withUnscoped())
+}
+
+func (r *repository) Limit(limit int) contract.Repository {
+	return r.with(r.q.withLimit(limit))
+}
+
+func (r *repository) Offset(offset int) contract.Repository {
+	return r.with(r.q.withOffset(offset))
+}
+
+func (r *repository) OrderBy(column, direction string) contract.Repository {
+	return r.with(r.q.orderBy(column, direction))
+}
+
+// --- Read Operations ---
+func (r *repository) Find(ctx context.Context, id any) (contract.Model, error) {
+	row, err := r.conn.fetchFirst(ctx, r.q.where(id, nil, false))
+	return modelOf(row), err
+}
+
+func (r *repository) FindOrFail(ctx context.Context, id any) (contract.Model, error) {
+	return orFail(r.Find(ctx, id))
+}
+
+func (r *repository) First(ctx context.Context) (contract.Model, error) {
+	row, err := r.conn.fetchFirst(ctx, r.q)
+	return modelOf(row), err
+}
+
+func (r *repository) FirstOrFail(ctx context.Context) (contract.Model, error) {
+	return orFail(r.First(ctx))
+}
+
+func (r *repository) Get(ctx context.Context) ([]contract.Model, error) {
+	rows, err := r.conn.fetch(ctx, r.q)
+	if err != nil {
+		return nil, err
+	}
+	models := make([]contract.Model, len(rows))
+	for i, row := range rows {
+		models[i] = modelOf(row)
+	}
+	return models, nil
+}
+
+// Pluck reads the values of column into dest, a pointer to a slice.
+func (r *repository) Pluck(ctx context.Context, column string, dest any) error {
+	dv := reflect.ValueOf(dest)
+	if dv.Kind() != reflect.Pointer || dv.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("pluck destination must be a pointer to a slice, got %T", dest)
+	}
+	col, err := r.q.column(column)
+	if err != nil {
+		return err
+	}
+	q := r.q.clone()
+	q.selects = []*schema.Column{col}
+	return r.conn.load(ctx, q, dest, false)
+}
+
+// --- Write Operations ---
+
+// Create stores copies of models. Models without a primary key get the next
+// generated one, and the timestamps of models implementing contract.Timestamps
+// are set when zero.
+func (r *repository) Create(ctx context.Context, models ...contract.Model) error {
+	return r.CreateInBatches(ctx, models, max(len(models), 1))
+}
+
+func (r *repository) CreateInBatches(ctx context.Context, models []contract.Model, batchSize int) error {
+	if len(models) == 0 {
+		return nil
+	}
+	if batchSize <= 0 {
+		return errors.New("batch size must be positive")
+	}
+	for start := 0; start < len(models); start += batchSize {
+		if err := r.insert(ctx, models[start:min(start+batchSize, len(models))]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Update writes the non-zero fields of every model to its row, as GORM does,
+// and refreshes UpdatedAt of models implementing contract.Timestamps.
+func (r *repository) Update(ctx context.Context, models ...contract.Model) error {
+	s := r.q.schema
+	now := time.Now()
+	for _, model := range models {
+		v, err := r.structOf(model)
+		if err != nil {
+			return err
+		}
+		if s.Timestamps {
+			model.(contract.Timestamps).SetUpdatedAt(now) //nolint:forcetypeassert // checked by schema.Of
+		}
+
+		var assignments []assignment
+		for _, col := range s.Columns {
+			if col != s.PrimaryKey && !col.IsZero(v) {
+				assignments = append(assignments, assignment{column: col, value: col.Value(v)})
+			}
+		}
+		if err := r.conn.update(ctx, r.byKey(v), assignments); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Delete deletes models by primary key. Models implementing contract.SoftDelete
+// are marked deleted instead, and their DeletedAt is set.
+func (r *repository) Delete(ctx context.Context, models ...contract.Model) error {
+	return r.delete(ctx, r.q, models)
+}
+
+// ForceDelete deletes models by primary key, even models with soft deletes.
+func (r *repository) ForceDelete(ctx context.Context, models ...contract.Model) error {
+	return r.delete(ctx, r.q.withUnscoped(), models)
+}
+
+// --- Upsert Operations ---
+
+// FirstOrCreate reads the first row matching the non-zero fields of condition
+// into the model to create, which defaults to condition. Without a match, the
+// model is created with the fields of condition.
+func (r *repository) FirstOrCreate(
+	ctx context.Context,
+	condition contract.Model,
+	create ...contract.Model,
+) (contract.Model, error) {
+	toCreate := condition
+	if len(create) > 0 && create[0] != nil {
+		toCreate = create[0]
+	}
+	target, err := r.structOf(toCreate)
+	if err != nil {
+		return nil, err
+	}
+	cond, err := r.structOf(condition)
+	if err != nil {
+		return nil, err
+	}
+
+	found, err := r.conn.fetchFirst(ctx, r.q.where(condition, nil, false))
+	if err != nil {
+		return toCreate, err
+	}
+	if found.IsValid() {
+		target.Set(found.Elem())
+		return toCreate, nil
+	}
+
+	for _, col := range r.q.schema.Columns {
+		if !col.IsZero(cond) {
+			if err := col.Set(target, col.Value(cond)); err != nil {
+				return toCreate, err //nolint:wrapcheck // column errors name the column
+			}
+		}
+	}
+	return toCreate, r.insert(ctx, []contract.Model{toCreate})
+}
+
+// UpdateOrCreate reads the first row matching the non-zero fields of condition
+// into condition and updates it with values, a map of columns or a model whose
+// non-zero fields are used. Without a match, condition is created with values.
+func (r *repository) UpdateOrCreate(ctx context.Context, condition contract.Model, values any) (contract.Model, error) {
+	target, err := r.structOf(condition)
+	if err != nil {
+		return nil, err
+	}
+	assignments, err := r.assignments(values)
+	if err != nil {
+		return condition, err
+	}
+
+	found, err := r.conn.fetchFirst(ctx, r.q.where(condition, nil, false))
+	if err != nil {
+		return condition, err
+	}
+	if found.IsValid() {
+		target.Set(found.Elem())
+	}
+	for _, a := range assignments {
+		if err := a.column.Set(target, a.value); err != nil {
+			return condition, err //nolint:wrapcheck // column errors name the column
+		}
+	}
+	if !found.IsValid() {
+		return condition, r.insert(ctx, []contract.Model{condition})
+	}
+
+	if r.q.schema.Timestamps && len(assignments) > 0 {
+		now := time.Now()
+		condition.(contract.Timestamps).SetUpdatedAt(now) //nolint:forcetypeassert // checked by schema.Of
+		assignments = append(assignments, assignment{column: r.q.schema.ByName[schema.UpdatedAtColumn], value: now})
+	}
+	return condition, r.conn.update(ctx, r.byKey(target), assignments)
+}
+
+// QueryBuilder provides access to the fluent query builder interface. The
+// builder starts from the conditions of the repository.
+func (r *repository) QueryBuilder() contract.QueryBuilder {
+	return &queryBuilder{conn: r.conn, model: r.mdl, q: r.q}
+}
+
+// --- Helper Functions ---
+
+// insert stores models, which must be of the repository model type, all or
+// none of them. Generated primary keys are written back to the models.
+func (r *repository) insert(ctx context.Context, models []contract.Model) error {
+	if err := ctx.Err(); err != nil {
+		return err //nolint:wrapcheck // context errors are returned as is
+	}
+	s := r.q.schema
+	now := time.Now()
+	rows := make([]reflect.Value, len(models))
+	for i, model := range models {
+		v, err := r.structOf(model)
+		if err != nil {
+			return err
+		}
+		if s.Timestamps {
+			ts := model.(contract.Timestamps) //nolint:forcetypeassert // checked by schema.Of
+			if ts.GetCreatedAt().IsZero() {
+				ts.SetCreatedAt(now)
+			}
+			if ts.GetUpdatedAt().IsZero() {
+				ts.SetUpdatedAt(now)
+			}
+		}
+		rows[i] = v
+	}
+	return r.conn.store.write(func() error {
+		return r.conn.store.insert(r.conn.journalFor(ctx), s, rows)
+	})
+}
+
+// delete deletes the rows of models, matched by primary key within q.
+func (r *repository) delete(ctx context.Context, q *query, models []contract.Model) error {
+	if len(models) == 0 {
+		return nil
+	}
+	s := r.q.schema
+	var ids []any
+	for _, model := range models {
+		v, err := r.structOf(model)
+		if err != nil {
+			return err
+		}
+		if !s.PrimaryKey.IsZero(v) {
+			ids = append(ids, s.PrimaryKey.Value(v))
+		}
+	}
+	if len(ids) > 0 {
+		q = q.where(map[string]any{s.PrimaryKey.Name: ids}, nil, false)
+	}
+
+	now := time.Now()
+	if err := r.conn.delete(ctx, q, now); err != nil {
+		return err
+	}
+	if q.scoped() {
+		for _, model := range models {
+			model.(contract.SoftDelete).SetDeletedAt(&now) //nolint:forcetypeassert // checked by schema.Of
+		}
+	}
+	return nil
+}
+
+// byKey returns the query of the repository restricted to the row of v.
+func (r *repository) byKey(v reflect.Value) *query {
+	pk := r.q.schema.PrimaryKey
+	return r.q.where(map[string]any{pk.Name: pk.Value(v)}, nil, false)
+}
+
+// assignments returns the columns to update from values: a map of column or
+// field names, or a model or struct whose non-zero fields are used.
+func (r *repository) assignments(values any) ([]assignment, error) {
+	s := r.q.schema
+	if m, ok := values.(map[string]any); ok {
+		assignments := make([]assignment, 0, len(m))
+		for _, key := range slices.Sorted(maps.Keys(m)) {
+			col, ok := s.Lookup(key)
+			if !ok {
+				return nil, fmt.Errorf("unknown column %q of %s", key, s.Table)
+			}
+			assignments = append(assignments, assignment{column: col, value: m[key]})
+		}
+		return assignments, nil
+	}
+
+	v := reflect.ValueOf(values)
+	if v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("unsupported update values %T: expected a map or a struct", values)
+	}
+	vs, err := schema.OfStruct(v.Type())
+	if err != nil {
+		return nil, err //nolint:wrapcheck // schema errors name the type
+	}
+	var assignments []assignment
+	for _, col := range vs.Columns {
+		own := s.ByName[col.Name]
+		if col.IsZero(v) || (vs.PrimaryKey != nil && col == vs.PrimaryKey) || own == nil {
+			continue
+		}
+		assignments = append(assignments, assignment{column: own, value: col.Value(v)})
+	}
+	return assignments, nil
+}
+
+// structOf returns the struct model points to, which must be of the
+// repository model type.
+func (r *repository) structOf(model contract.Model) (reflect.Value, error) {
+	if model == nil {
+		return reflect.Value{}, errors.New("model cannot be nil")
+	}
+	v := reflect.ValueOf(model)
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return reflect.Value{}, errors.New("model cannot be nil")
+	}
+	if v.Elem().Type() != r.q.schema.Type {
+		return reflect.Value{}, fmt.Errorf("model %T does not belong to a repository of %s", model, r.q.schema.Type)
+	}
+	return v.Elem(), nil
+}
+
+// update sets the assigned columns of the rows matched by q.
+func (c *connection) update(ctx context.Context, q *query, assignments []assignment) error {
+	if len(assignments) == 0 {
+		return nil
+	}
+	return c.modify(ctx, q, func(j *journal, row reflect.Value) error {
+		for _, a := range assignments {
+			if err := a.column.Set(row.Elem(), a.value); err != nil {
+				return err //nolint:wrapcheck // column errors name the column
+			}
+		}
+		c.store.replace(j, q.schema, row.Elem())
+		return nil
+	})
+}
+
+// delete deletes the rows matched by q. With the soft delete scope, the rows
+// are marked deleted at deletedAt instead.
+func (c *connection) delete(ctx context.Context, q *query, deletedAt time.Time) error {
+	if q.scoped() {
+		return c.update(ctx, q, []assignment{{column: q.schema.ByName[schema.DeletedAtColumn], value: deletedAt}})
+	}
+	return c.modify(ctx, q, func(j *journal, row reflect.Value) error {
+		c.store.remove(j, q.schema, row.Elem())
+		return nil
+	})
+}
+
+// modify calls fn with copies of the rows matched by q while holding the write
+// lock, so the rows cannot change between matching and writing. When fn fails,
+// the writes made for the previous rows are undone, as a failing statement
+// changes no rows.
+func (c *connection) modify(ctx context.Context, q *query, fn func(j *journal, row reflect.Value) error) error {
+	if q.err != nil {
+		return q.err
+	}
+	if len(q.wheres) == 0 {
+		return ErrMissingWhereClause
+	}
+	if err := ctx.Err(); err != nil {
+		return err //nolint:wrapcheck // context errors are returned as is
+	}
+	return c.store.write(func() error {
+		statement := &journal{}
+		for _, row := range q.filter(c.store.rows(q.schema)) {
+			if err := fn(statement, row); err != nil {
+				statement.undoTo(0)
+				return err
+			}
+		}
+		if j := c.journalFor(ctx); j != nil {
+			j.undo = append(j.undo, statement.undo...)
+		}
+		return nil
+	})
+}
+
+// modelOf returns the model row points to, or nil for an invalid row.
+func modelOf(row reflect.Value) contract.Model {
+	if !row.IsValid() {
+		return nil
+	}
+	return row.Interface().(contract.Model) //nolint:forcetypeassert // rows are created from model types
+}
+
+// orFail turns a missing model into db.ErrRecordNotFound.
+func orFail(model contract.Model, err error) (contract.Model, error) {
+	if err != nil {
+		return nil, err
+	}
+	if model == nil {
+		return nil, db.ErrRecordNotFound
+	}
+	return model, nil
+}
@@ -0,0 +1,156 @@
+package memory
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/next-trace/scg-database/config"
+	"github.com/next-trace/scg-database/contract"
+	"github.com/next-trace/scg-database/db"
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	// Test Models
+	testUser struct {
+		ID        uint
+		Name      string
+		Email     string
+		Age       int
+		CreatedAt time.Time
+		UpdatedAt time.Time
+		DeletedAt *time.Time
+		Posts     []*testPost
+		Roles     []testRole
+	}
+
+	testPost struct {
+		ID     uint
+		UserID uint
+		Title  string
+		Author *testUser
+	}
+
+	testRole struct {
+		ID   uint
+		Name string
+	}
+)
+
+func (m *testUser) PrimaryKey() string        { return "id" }
+func (m *testUser) TableName() string         { return "users" }
+func (m *testUser) GetID() any                { return m.ID }
+func (m *testUser) SetID(id any)              { m.ID = id.(uint) }
+func (m *testUser) GetDeletedAt() *time.Time  { return m.DeletedAt }
+func (m *testUser) SetDeletedAt(t *time.Time) { m.DeletedAt = t }
+func (m *testUser) GetCreatedAt() time.Time   { return m.CreatedAt }
+func (m *testUser) GetUpdatedAt() time.Time   { return m.UpdatedAt }
+func (m *testUser) SetCreatedAt(t time.Time)  { m.CreatedAt = t }
+func (m *testUser) SetUpdatedAt(t time.Time)  { m.UpdatedAt = t }
+func (m *testUser) Relationships() map[string]contract.Relationship {
+	return map[string]contract.Relationship{
+		"Posts": contract.NewHasMany(&testPost{}, "user_id", "id"),
+		"Roles": contract.NewBelongsToMany(&testRole{}, "user_roles"),
+	}
+}
+
+func (m *testPost) PrimaryKey() string { return "id" }
+func (m *testPost) TableName() string  { return "posts" }
+func (m *testPost) GetID() any         { return m.ID }
+func (m *testPost) SetID(id any)       { m.ID = id.(uint) }
+func (m *testPost) Relationships() map[string]contract.Relationship {
+	return map[string]contract.Relationship{"Author": contract.NewBelongsTo(&testUser{}, "user_id", "id")}
+}
+
+func (m *testRole) PrimaryKey() string                              { return "id" }
+func (m *testRole) TableName() string                               { return "" } // derived: test_roles
+func (m *testRole) GetID() any                                      { return m.ID }
+func (m *testRole) SetID(id any)                                    { m.ID = id.(uint) }
+func (m *testRole) Relationships() map[string]contract.Relationship { return nil }
+
+// setupConnection connects to a new, empty in-memory database.
+func setupConnection(t *testing.T, opts ...config.Option) contract.Connection {
+	t.Helper()
+	Register()
+	conn, err := db.Connect(&config.Config{Driver: AdapterName, DSN: AdapterName}, opts...)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+// setupTest returns a repository of testUser on a new in-memory database.
+func setupTest(t *testing.T) contract.Repository {
+	t.Helper()
+	repo, err := setupConnection(t).NewRepository(&testUser{})
+	require.NoError(t, err)
+	return repo
+}
+
+func newUser(i int) *testUser {
+	return &testUser{Name: fmt.Sprintf("User %d", i), Email: fmt.Sprintf("user%d@example.com", i), Age: 20 + i}
+}
+
+// --- Test Cases ---
+
+// The behaviour shared with the other adapters is covered by TestConformance;
+// these tests cover what is specific to keeping models in memory.
+
+func TestRepository_CopyOnWrite(t *testing.T) {
+	repo := setupTest(t)
+	user := newUser(1)
+	user.Posts = []*testPost{{Title: "not stored"}}
+	require.NoError(t, repo.Create(t.Context(), user))
+
+	user.Name = "Changed"
+	found, err := repo.FindOrFail(t.Context(), user.ID)
+	require.NoError(t, err)
+	require.Equal(t, "User 1", found.(*testUser).Name, "the stored row is a copy of the created model")
+	require.Nil(t, found.(*testUser).Posts, "relationships are loaded with With rather than stored")
+
+	require.NoError(t, repo.Update(t.Context(), found))
+	found.(*testUser).Age = 99
+	again, err := repo.FindOrFail(t.Context(), user.ID)
+	require.NoError(t, err)
+	require.Equal(t, 21, again.(*testUser).Age, "the stored row is a copy of the updated model")
+}
+
+func TestRepository_CopyOnRead(t *testing.T) {
+	conn := setupConnection(t)
+	repo, err := conn.NewRepository(&testUser{})
+	require.NoError(t, err)
+	require.NoError(t, repo.Create(t.Context(), newUser(1)))
+	posts, err := conn.NewRepository(&testPost{})
+	require.NoError(t, err)
+	require.NoError(t, posts.Create(t.Context(), &testPost{UserID: 1, Title: "Hello"}))
+
+	found, err := repo.FindOrFail(t.Context(), 1)
+	require.NoError(t, err)
+	found.(*testUser).Name = "Changed"
+	all, err := repo.Get(t.Context())
+	require.NoError(t, err)
+	all[0].(*testUser).Age = 99
+	loaded, err := repo.With("Posts").FindOrFail(t.Context(), 1)
+	require.NoError(t, err)
+	loaded.(*testUser).Posts[0].Title = "Changed"
+
+	var rows []testUser
+	require.NoError(t, repo.QueryBuilder().Find(t.Context(), &rows))
+	rows[0].Name = "Changed"
+
+	stored, err := repo.With("Posts").FindOrFail(t.Context(), 1)
+	require.NoError(t, err)
+	require.Equal(t, "User 1", stored.(*testUser).Name, "models read are copies of the stored rows")
+	require.Equal(t, 21, stored.(*testUser).Age)
+	require.Equal(t, "Hello", stored.(*testUser).Posts[0].Title, "loaded relationships are copies too")
+}
+
+func TestRepository_Unsupported(t *testing.T) {
+	repo := setupTest(t)
+	require.NoError(t, repo.Create(t.Context(), newUser(1)))
+
+	_, err := repo.Where("LOWER(name) = ?", "user 1").Get(t.Context())
+	require.ErrorIs(t, err, ErrNotSupported, "only column comparisons are evaluated")
+	_, err = repo.With("Roles").Get(t.Context())
+	require.ErrorIs(t, err, ErrNotSupported, "many-to-many relationships need a join table")
+}
@@ -0,0 +1,255 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"sync"
+
+	"github.com/next-trace/scg-database/adapter/internal/schema"
+	"github.com/next-trace/scg-database/db"
+)
+
+type (
+	// store is the database of a connection: one table per model table name.
+	store struct {
+		mu     sync.RWMutex
+		tables map[string]*table
+		closed bool
+	}
+
+	// table holds the rows of one model type by primary key.
+	table struct {
+		rows map[string]*entry
+		// seq orders the rows by insertion; lastID is the last generated key.
+		seq    int64
+		lastID int64
+	}
+
+	// entry is a stored row: a pointer to a copy of the model struct.
+	entry struct {
+		seq int64
+		row reflect.Value
+	}
+
+	// journal records how to undo the writes of a transaction. Savepoints
+	// remember its length and undo back to it.
+	journal struct {
+		undo []func()
+	}
+)
+
+//nolint:grouper // Only One Global Variable
+var errClosed = errors.New("memory: database is closed")
+
+func newStore() *store {
+	return &store{tables: make(map[string]*table)}
+}
+
+// check fails when ctx is done or the store is closed.
+func (st *store) check(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err //nolint:wrapcheck // context errors are returned as is
+	}
+	return st.read(func() error { return nil })
+}
+
+// read runs fn with the tables locked for reading.
+func (st *store) read(fn func() error) error {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+	if st.closed {
+		return errClosed
+	}
+	return fn()
+}
+
+// write runs fn with the tables locked for writing.
+func (st *store) write(fn func() error) error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if st.closed {
+		return errClosed
+	}
+	return fn()
+}
+
+// table returns the table of s, creating it on first use. Callers hold the
+// write lock.
+func (st *store) table(s *schema.Schema) *table {
+	t, ok := st.tables[s.Table]
+	if !ok {
+		t = &table{rows: make(map[string]*entry)}
+		st.tables[s.Table] = t
+	}
+	return t
+}
+
+// rows returns copies of the rows of s in insertion order. Callers hold a lock.
+func (st *store) rows(s *schema.Schema) []reflect.Value {
+	t, ok := st.tables[s.Table]
+	if !ok {
+		return nil
+	}
+	entries := make([]*entry, 0, len(t.rows))
+	for _, e := range t.rows {
+		entries = append(entries, e)
+	}
+	slices.SortFunc(entries, func(a, b *entry) int { return cmp.Compare(a.seq, b.seq) })
+
+	rows := make([]reflect.Value, len(entries))
+	for i, e := range entries {
+		rows[i] = copyRow(s, e.row.Elem())
+	}
+	return rows
+}
+
+// insert stores copies of rows, structs of s. Rows without a primary key get
+// the next generated one, which is written back to them. Nothing is stored
+// when a key is taken. Callers hold the write lock.
+func (st *store) insert(j *journal, s *schema.Schema, rows []reflect.Value) error {
+	t := st.table(s)
+	keys := make(map[string]bool, len(rows))
+	for _, row := range rows {
+		if s.PrimaryKey.IsZero(row) {
+			continue
+		}
+		key := schema.KeyOf(s.PrimaryKey.Value(row))
+		if _, taken := t.rows[key]; taken || keys[key] {
+			return db.NewDatabaseError(db.ErrUniqueViolation,
+				fmt.Errorf("duplicate primary key %s of %s", key, s.Table))
+		}
+		keys[key] = true
+	}
+
+	// Explicit keys come first, as in a database that inserts them before the
+	// rows with generated keys.
+	for key := range keys {
+		if id, err := strconv.ParseInt(key, 10, 64); err == nil {
+			t.lastID = max(t.lastID, id)
+		}
+	}
+	for _, row := range rows {
+		if s.PrimaryKey.IsZero(row) {
+			if err := t.generateKey(s.PrimaryKey, row, keys); err != nil {
+				return err
+			}
+		}
+	}
+	for _, row := range rows {
+		key := schema.KeyOf(s.PrimaryKey.Value(row))
+		t.seq++
+		e := &entry{seq: t.seq, row: copyRow(s, row)}
+		t.rows[key] = e
+		j.record(func() { t.restore(key, e, nil) })
+	}
+	return nil
+}
+
+// generateKey sets the primary key of row to the next free integer. String
+// keys get its decimal form.
+func (t *table) generateKey(pk *schema.Column, row reflect.Value, taken map[string]bool) error {
+	for {
+		t.lastID++
+		key := strconv.FormatInt(t.lastID, 10)
+		if _, exists := t.rows[key]; exists || taken[key] {
+			continue
+		}
+		var value any = t.lastID
+		if pk.Type.Kind() == reflect.String {
+			value = key
+		}
+		return pk.Set(row, value)
+	}
+}
+
+// replace stores a copy of row, a struct of s, in place of the row with the
+// same primary key. Callers hold the write lock.
+func (st *store) replace(j *journal, s *schema.Schema, row reflect.Value) {
+	t := st.table(s)
+	key := schema.KeyOf(s.PrimaryKey.Value(row))
+	old, ok := t.rows[key]
+	if !ok {
+		return
+	}
+	e := &entry{seq: old.seq, row: copyRow(s, row)}
+	t.rows[key] = e
+	j.record(func() { t.restore(key, e, old) })
+}
+
+// remove deletes the row of s with the primary key of row. Callers hold the
+// write lock.
+func (st *store) remove(j *journal, s *schema.Schema, row reflect.Value) {
+	t := st.table(s)
+	key := schema.KeyOf(s.PrimaryKey.Value(row))
+	old, ok := t.rows[key]
+	if !ok {
+		return
+	}
+	delete(t.rows, key)
+	j.record(func() { t.restore(key, nil, old) })
+}
+
+// restore undoes a write that left the row at key as written, nil when it was
+// deleted, putting old back in its place, or deleting the row when old is nil.
+// A row that no longer holds what the write left was changed outside the
+// transaction since, and is kept. Callers hold the write lock.
+func (t *table) restore(key string, written, old *entry) {
+	if t.rows[key] != written {
+		return
+	}
+	if old == nil {
+		delete(t.rows, key)
+		return
+	}
+	t.rows[key] = old
+}
+
+// close empties the store; later calls fail.
+func (st *store) close() {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.closed = true
+	st.tables = nil
+}
+
+// record adds an undo step. Writes outside a transaction have a nil journal.
+func (j *journal) record(undo func()) {
+	if j != nil {
+		j.undo = append(j.undo, undo)
+	}
+}
+
+// rollbackTo undoes the writes recorded after the first mark steps, holding
+// the write lock of st.
+func (j *journal) rollbackTo(st *store, mark int) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	j.undoTo(mark)
+}
+
+// undoTo undoes the writes recorded after the first mark steps, in reverse
+// order. Callers hold the write lock.
+func (j *journal) undoTo(mark int) {
+	for i := len(j.undo) - 1; i >= mark; i-- {
+		j.undo[i]()
+	}
+	j.undo = j.undo[:mark]
+}
+
+// copyRow returns a pointer to a copy of the struct v of s. Relationship fields
+// are cleared, as they are loaded with With rather than stored.
+func copyRow(s *schema.Schema, v reflect.Value) reflect.Value {
+	row := reflect.New(s.Type)
+	row.Elem().Set(v)
+	for _, index := range s.Relations {
+		if f, err := row.Elem().FieldByIndexErr(index); err == nil {
+			f.SetZero()
+		}
+	}
+	return row
+}
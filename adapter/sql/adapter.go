@@ -0,0 +1,112 @@
+// Package sql provides an implementation of the SCG database toolkit interfaces
+// built directly on database/sql, without GORM. It generates the SQL of the
+// MySQL, Postgres and SQLite dialects itself and maps models to tables through
+// reflection (see schema).
+package sql
+
+import (
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/next-trace/scg-database/adapter/internal/poolstats"
+	"github.com/next-trace/scg-database/config"
+	"github.com/next-trace/scg-database/contract"
+	"github.com/next-trace/scg-database/db"
+)
+
+// AdapterName is the name of the adapter and the prefix of its drivers.
+const AdapterName = "sql"
+
+// Dialect and driver name constants
+const (
+	DialectMySQL      = "mysql"
+	DialectPostgres   = "postgres"
+	DialectSQLite     = "sqlite"
+	DialectSQLitePure = "sqlite-pure"
+
+	DriverMySQL      = "sql:mysql"
+	DriverPostgres   = "sql:postgres"
+	DriverSQLite     = "sql:sqlite"
+	DriverSQLitePure = "sql:sqlite-pure"
+)
+
+// defaultConnMaxLifetime matches the pool default of the GORM adapter.
+const defaultConnMaxLifetime = 10 * time.Second
+
+//nolint:grouper // Only One Global Variable
+var registerOnce sync.Once
+
+type (
+	// Adapter is the database/sql implementation of the contract.DBAdapter interface.
+	Adapter struct{}
+)
+
+// Ensure Adapter satisfies the interfaces at compile time.
+var (
+	_ contract.DBAdapter    = (*Adapter)(nil)
+	_ contract.Capabilities = (*Adapter)(nil)
+	_ contract.Dialects     = (*Adapter)(nil)
+)
+
+// Register registers the adapter and its query builder factory with the central
+// registry. This function is safe to call multiple times and will only register once.
+func Register() {
+	registerOnce.Do(func() {
+		db.RegisterAdapter(&Adapter{}, AdapterName, DriverMySQL, DriverPostgres, DriverSQLite, DriverSQLitePure)
+		db.RegisterQueryBuilderFactory(AdapterName, &QueryBuilderFactory{})
+	})
+}
+
+// Name returns the name of this database adapter
+func (a *Adapter) Name() string { return AdapterName }
+
+// Connect opens a database/sql pool for cfg and wraps it into the library's
+// contract.Connection. The database is not contacted: db.ConnectContext pings
+// the connection with its context. A DSN with secret references is opened
+// through db.OpenDB, which resolves them for every new pool connection.
+func (a *Adapter) Connect(cfg *config.Config) (contract.Connection, error) {
+	d, ok := lookupDialect(cfg.Driver)
+	if !ok {
+		return nil, fmt.Errorf("unsupported sql driver: %s (expected one of %s, %s, %s, %s)",
+			cfg.Driver, DriverMySQL, DriverPostgres, DriverSQLite, DriverSQLitePure)
+	}
+
+	var (
+		pool *sql.DB
+		err  error
+	)
+	if config.HasSecretRefs(cfg.DSN) {
+		pool, err = db.OpenDB(d.driver, cfg)
+	} else {
+		pool, err = sql.Open(d.driver, cfg.DSN)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	configurePool(pool, cfg)
+
+	return &connection{
+		db:       pool,
+		dialect:  d,
+		config:   *cfg,
+		health:   &poolstats.Health{},
+		observer: newQueryObserver(cfg),
+	}, nil
+}
+
+// configurePool applies the pool settings of cfg.
+func configurePool(pool *sql.DB, cfg *config.Config) {
+	lifetime := defaultConnMaxLifetime
+	if cfg.ConnMaxLifetime > 0 {
+		lifetime = cfg.ConnMaxLifetime
+	}
+	pool.SetConnMaxLifetime(lifetime)
+	if cfg.MaxIdleConns > 0 {
+		pool.SetMaxIdleConns(cfg.MaxIdleConns)
+	}
+	if cfg.MaxOpenConns > 0 {
+		pool.SetMaxOpenConns(cfg.MaxOpenConns)
+	}
+}
@@ -0,0 +1,147 @@
+package sql
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/next-trace/scg-database/config"
+	"github.com/next-trace/scg-database/contract"
+	"github.com/next-trace/scg-database/db"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegister(t *testing.T) {
+	Register()
+	Register() // registering twice is a no-op
+
+	adapter, err := db.GetAdapter(DriverPostgres)
+	require.NoError(t, err)
+	require.Equal(t, AdapterName, adapter.Name())
+
+	factory, err := db.GetQueryBuilderFactory(AdapterName)
+	require.NoError(t, err)
+	require.Equal(t, AdapterName, factory.Name())
+
+	var names []string
+	for _, info := range db.ListAdapters() {
+		if info.Adapter.Name() == AdapterName {
+			names = append(names, info.Name)
+			if info.Name != AdapterName {
+				require.Contains(t, info.Capabilities, contract.CapabilitySavepoints, info.Name)
+			}
+		}
+	}
+	require.Equal(t, []string{AdapterName, DriverMySQL, DriverPostgres, DriverSQLite, DriverSQLitePure}, names)
+}
+
+func TestAdapter_Connect(t *testing.T) {
+	adapter := &Adapter{}
+
+	_, err := adapter.Connect(&config.Config{Driver: "sql:oracle"})
+	require.ErrorContains(t, err, "unsupported sql driver: sql:oracle")
+	_, err = adapter.Connect(&config.Config{Driver: "mysql"})
+	require.ErrorContains(t, err, "unsupported sql driver: mysql")
+
+	cfg := &config.Config{
+		Driver:          testSQLiteDriver,
+		DSN:             filepath.Join(t.TempDir(), "pool.db"),
+		MaxOpenConns:    3,
+		MaxIdleConns:    2,
+		ConnMaxLifetime: time.Minute,
+	}
+	conn, err := adapter.Connect(cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	require.NoError(t, conn.Ping(t.Context()))
+
+	pool, ok := conn.GetConnection().(*sql.DB)
+	require.True(t, ok)
+	require.Equal(t, 3, pool.Stats().MaxOpenConnections)
+}
+
+func TestAdapter_ConnectWithMissingSecret(t *testing.T) {
+	cfg := config.Config{Driver: testSQLiteDriver, DSN: "secret://env/TEST_SQL_SURELY_UNSET"}
+
+	conn, err := (&Adapter{}).Connect(&cfg)
+	require.NoError(t, err, "secrets are resolved when the pool connects")
+	t.Cleanup(func() { _ = conn.Close() })
+	require.ErrorContains(t, conn.Ping(t.Context()), `"TEST_SQL_SURELY_UNSET" is not set`)
+}
+
+func TestAdapter_Capabilities(t *testing.T) {
+	adapter := &Adapter{}
+
+	require.True(t, adapter.Supports(DriverPostgres, contract.CapabilityReturning))
+	require.False(t, adapter.Supports(DriverMySQL, contract.CapabilityReturning))
+	require.True(t, adapter.Supports(DriverMySQL, contract.CapabilityRowLocking))
+	require.False(t, adapter.Supports(DriverSQLite, contract.CapabilityRowLocking))
+	require.True(t, adapter.Supports(DriverSQLitePure, contract.CapabilityMigrations))
+	require.False(t, adapter.Supports("sql:oracle", contract.CapabilitySavepoints))
+
+	tests := []struct {
+		driver, migrationDriver, truncate string
+	}{
+		{DriverMySQL, "mysql", "TRUNCATE TABLE users"},
+		{DriverPostgres, "postgres", "TRUNCATE TABLE users RESTART IDENTITY CASCADE"},
+		{DriverSQLite, "sqlite3", "DELETE FROM users"},
+		{DriverSQLitePure, "sqlite", "DELETE FROM users"},
+	}
+	for _, tt := range tests {
+		info, ok := adapter.DialectInfo(tt.driver)
+		require.True(t, ok, tt.driver)
+		require.Equal(t, tt.migrationDriver, info.MigrationDriver)
+		require.Equal(t, tt.truncate, info.TruncateStatement("users"))
+	}
+
+	_, ok := adapter.DialectInfo("sql:oracle")
+	require.False(t, ok)
+	_, ok = adapter.DialectInfo("gorm:postgres")
+	require.False(t, ok)
+}
+
+func TestSQLitePure(t *testing.T) {
+	Register()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "dsn"), []byte(filepath.Join(dir, "pure.db")), 0o600))
+
+	// The secret reference makes the adapter open the pool through db.OpenDB.
+	cfg := config.Config{Driver: DriverSQLitePure, DSN: "secret://file/dsn"}
+	config.WithSecretProvider(config.SecretProviderFile, config.FileSecretProvider{Dir: dir})(&cfg)
+	conn, err := db.Connect(&cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	ctx := t.Context()
+	for _, stmt := range testSchema {
+		_, err := conn.Statement(ctx, stmt)
+		require.NoError(t, err)
+	}
+	require.FileExists(t, filepath.Join(dir, "pure.db"))
+
+	repo, err := conn.NewRepository(&testUser{})
+	require.NoError(t, err)
+	require.NoError(t, repo.Create(ctx, newUser(1)))
+
+	// Nested transactions use savepoints.
+	err = conn.Transaction(ctx, func(tx contract.Connection) error {
+		return tx.Transaction(ctx, func(inner contract.Connection) error {
+			innerRepo, err := inner.NewRepository(&testUser{})
+			require.NoError(t, err)
+			return innerRepo.Create(ctx, newUser(2))
+		})
+	})
+	require.NoError(t, err)
+
+	found, err := repo.Where("email = ?", "user2@example.com").First(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "User 2", found.(*testUser).Name)
+
+	err = repo.Create(ctx, newUser(1))
+	require.ErrorIs(t, err, db.ErrUniqueViolation)
+
+	require.True(t, db.Supports(DriverSQLitePure, contract.CapabilitySavepoints))
+	require.Equal(t, config.DialectSQLite, config.Dialect(DriverSQLitePure))
+}
@@ -0,0 +1,18 @@
+package sql
+
+import (
+	"testing"
+
+	"github.com/next-trace/scg-database/contract"
+	"github.com/next-trace/scg-database/testing/conformance"
+)
+
+func TestConformance(t *testing.T) {
+	for _, driver := range testSQLiteDrivers {
+		t.Run(driver, func(t *testing.T) {
+			conformance.RunConformance(t, func(t *testing.T) contract.Connection {
+				return setupConnection(t, driver)
+			})
+		})
+	}
+}
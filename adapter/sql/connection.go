@@ -0,0 +1,355 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/next-trace/scg-database/adapter/internal/poolstats"
+	"github.com/next-trace/scg-database/adapter/internal/schema"
+	"github.com/next-trace/scg-database/adapter/internal/sqlerr"
+	"github.com/next-trace/scg-database/adapter/internal/txhooks"
+	"github.com/next-trace/scg-database/adapter/internal/txrun"
+	"github.com/next-trace/scg-database/config"
+	"github.com/next-trace/scg-database/contract"
+	"github.com/next-trace/scg-database/db"
+)
+
+type (
+	// executor runs statements on a pool or a transaction.
+	executor interface {
+		ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+		QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	}
+
+	connection struct {
+		db *sql.DB
+		// tx is the transaction of the connection; it is nil outside Transaction.
+		tx      *sql.Tx
+		dialect *dialect
+		config  config.Config
+		// depth is the transaction nesting level: 0 for the root connection,
+		// 1 inside Transaction, and one more for every nested savepoint.
+		depth int
+		// savepoints numbers the savepoints of the root transaction so that each
+		// gets a unique name, also when nesting goes through the ambient
+		// transaction; it is nil outside a transaction.
+		savepoints *txrun.Savepoints
+		// hooks collects OnCommit and OnRollback callbacks of the current
+		// transaction scope; it is nil outside a transaction.
+		hooks *txhooks.Hooks
+		// health records the last Ping for Stats.
+		health *poolstats.Health
+		// observer reports statements to query observers; nil without observers.
+		observer *queryObserver
+	}
+)
+
+// Ensure the implementation satisfies the interface at compile time.
+var (
+	_ contract.Connection = (*connection)(nil)
+	_ contract.TxHooks    = (*connection)(nil)
+)
+
+func (c *connection) NewRepository(model contract.Model) (contract.Repository, error) {
+	if model == nil || reflect.ValueOf(model).IsNil() {
+		return nil, fmt.Errorf("model cannot be nil")
+	}
+	s, err := schema.Of(model)
+	if err != nil {
+		return nil, err
+	}
+	return &repository{conn: c, mdl: model, q: newQuery(c.dialect, s)}, nil
+}
+
+// GetConnection returns the *sql.DB of the connection, or the *sql.Tx inside a
+// transaction.
+func (c *connection) GetConnection() any {
+	if c.tx != nil {
+		return c.tx
+	}
+	return c.db
+}
+
+// Ping checks the database and records the latency and outcome for Stats.
+func (c *connection) Ping(ctx context.Context) error {
+	start := time.Now()
+	err := c.db.PingContext(ctx)
+	if c.health != nil {
+		c.health.Record(start, err)
+	}
+	return err //nolint:wrapcheck // callers wrap ping errors
+}
+
+func (c *connection) Close() error {
+	return c.db.Close() //nolint:wrapcheck // closing errors are returned as is
+}
+
+// Stats returns the statistics of the connection pool.
+func (c *connection) Stats() contract.Stats {
+	return contract.Stats{Pools: []contract.PoolStats{poolstats.Pool("primary", c.db, c.health)}}
+}
+
+// Transaction runs fn in a database transaction configured by opts. Called on a
+// connection that is already inside a transaction, it runs fn inside a SAVEPOINT
+// instead, so a failing inner function only undoes its own work; the same happens
+// when ctx carries a transaction of this database (see db.WithTx). Savepoints share
+// the outer transaction, so opts only apply to the outermost call.
+//
+// With a retry policy, the whole transaction is run again after deadlocks and
+// serialization failures, unless the policy has its own classifier.
+func (c *connection) Transaction(
+	ctx context.Context,
+	fn func(txConnection contract.Connection) error,
+	opts ...contract.TxOption,
+) error {
+	if c.depth > 0 {
+		return c.savepoint(ctx, fn)
+	}
+	if tx := c.ambientTx(ctx); tx != nil {
+		return tx.savepoint(ctx, fn)
+	}
+
+	txOpts := contract.NewTxOptions(opts...)
+	return txrun.Retry(ctx, txOpts, sqlerr.IsRetryable, func(ctx context.Context) error {
+		return c.transaction(ctx, fn, txOpts)
+	})
+}
+
+// transaction runs one attempt of a root transaction.
+func (c *connection) transaction(
+	ctx context.Context,
+	fn func(txConnection contract.Connection) error,
+	txOpts contract.TxOptions,
+) (err error) {
+	if txOpts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, txOpts.Timeout)
+		defer cancel()
+	}
+
+	tx, err := c.db.BeginTx(ctx, txOpts.SQL())
+	if err != nil {
+		return sqlerr.Translate(err)
+	}
+
+	err = txrun.Run(func(hooks *txhooks.Hooks) error {
+		if err := fn(c.child(tx, 1, &txrun.Savepoints{}, hooks)); err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil && !errors.Is(rbErr, sql.ErrTxDone) {
+				return errors.Join(err, fmt.Errorf("failed to roll back transaction: %w", rbErr))
+			}
+			return err
+		}
+		return sqlerr.Translate(tx.Commit())
+	}, func() { _ = tx.Rollback() })
+
+	// database/sql rolls the transaction back when its deadline passes; make the
+	// timeout visible instead of the resulting "transaction has already been committed
+	// or rolled back" error.
+	timedOut := txOpts.Timeout > 0 && errors.Is(ctx.Err(), context.DeadlineExceeded)
+	if err != nil && timedOut && !errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("transaction timed out after %s: %w", txOpts.Timeout, errors.Join(ctx.Err(), err))
+	}
+	return err
+}
+
+// savepoint runs fn inside a savepoint of the current transaction. The savepoint
+// is rolled back to when fn fails and released when it succeeds. A panic in fn
+// propagates to the outermost Transaction, which rolls everything back.
+func (c *connection) savepoint(ctx context.Context, fn func(txConnection contract.Connection) error) error {
+	if !c.dialect.supports(contract.CapabilitySavepoints) {
+		return db.NewUnsupportedCapabilityError("Transaction", AdapterName+":"+c.dialect.name,
+			contract.CapabilitySavepoints)
+	}
+	name := c.savepoints.Next()
+
+	if _, err := c.tx.ExecContext(ctx, "SAVEPOINT "+name); err != nil {
+		return fmt.Errorf("failed to create savepoint %s: %w", name, sqlerr.Translate(err))
+	}
+
+	hooks := &txhooks.Hooks{}
+	rolledBack := false
+	defer func() {
+		if rolledBack {
+			c.hooks.RollbackTo(hooks)
+		} else {
+			c.hooks.Release(hooks)
+		}
+	}()
+
+	if err := fn(c.child(c.tx, c.depth+1, c.savepoints, hooks)); err != nil {
+		rolledBack = true
+		if _, rbErr := c.tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+name); rbErr != nil {
+			return errors.Join(err, fmt.Errorf("failed to roll back to savepoint %s: %w", name, rbErr))
+		}
+		return err
+	}
+
+	if _, err := c.tx.ExecContext(ctx, "RELEASE SAVEPOINT "+name); err != nil {
+		return fmt.Errorf("failed to release savepoint %s: %w", name, sqlerr.Translate(err))
+	}
+	return nil
+}
+
+// child returns the connection of a transaction scope.
+func (c *connection) child(tx *sql.Tx, depth int, savepoints *txrun.Savepoints, hooks *txhooks.Hooks) *connection {
+	return &connection{
+		db:         c.db,
+		tx:         tx,
+		dialect:    c.dialect,
+		config:     c.config,
+		depth:      depth,
+		savepoints: savepoints,
+		hooks:      hooks,
+		health:     c.health,
+		observer:   c.observer,
+	}
+}
+
+// OnCommit registers fn to run after the outermost transaction commits, fulfilling
+// contract.TxHooks. Outside a transaction there is nothing to wait for, so fn runs
+// immediately.
+func (c *connection) OnCommit(fn func()) {
+	if c.hooks == nil {
+		fn()
+		return
+	}
+	c.hooks.OnCommit(fn)
+}
+
+// OnRollback registers fn to run once the work of the current transaction scope
+// has been rolled back, fulfilling contract.TxHooks. Hooks of a rolled back
+// savepoint run when the outermost transaction finishes. Outside a transaction
+// fn never runs.
+func (c *connection) OnRollback(fn func()) {
+	if c.hooks != nil {
+		c.hooks.OnRollback(fn)
+	}
+}
+
+// Select executes a raw read query, fulfilling the contract. Like Statement, it
+// runs on the transaction when called on, or with a context carrying, one. As
+// in the GORM adapter, `?` placeholders are rebound for the dialect.
+func (c *connection) Select(ctx context.Context, query string, bindings ...any) ([]map[string]any, error) {
+	query, bindings = expandArgs(query, bindings)
+	var results []map[string]any
+	err := c.query(ctx, "row", c.dialect.rebind(query), bindings, func(rows *sql.Rows) (int64, error) {
+		var err error
+		results, err = scanMaps(rows)
+		return int64(len(results)), err
+	})
+	return results, err
+}
+
+// Statement executes a raw write query, fulfilling the contract. On a transaction
+// connection, or with a context carrying one, it runs on the transaction, so a
+// rollback undoes it.
+func (c *connection) Statement(ctx context.Context, query string, bindings ...any) (sql.Result, error) {
+	return c.exec(ctx, "exec", query, bindings)
+}
+
+// executor returns the transaction of the connection, the transaction of this
+// database carried by ctx (see db.WithTx), or the pool.
+func (c *connection) executor(ctx context.Context) executor {
+	if c.tx != nil {
+		return c.tx
+	}
+	if tx := c.ambientTx(ctx); tx != nil {
+		return tx.tx
+	}
+	return c.db
+}
+
+// ambientTx returns the transaction stored in ctx when it belongs to the same
+// database as c, and nil otherwise.
+func (c *connection) ambientTx(ctx context.Context) *connection {
+	ambient, ok := db.TxFromContext(ctx)
+	if !ok {
+		return nil
+	}
+	tx, ok := ambient.(*connection)
+	if !ok || tx.depth == 0 || tx.db != c.db {
+		return nil
+	}
+	return tx
+}
+
+// exec runs a write statement and reports it to the observers as operation.
+func (c *connection) exec(ctx context.Context, operation, query string, args []any) (sql.Result, error) {
+	start := time.Now()
+	result, err := c.executor(ctx).ExecContext(ctx, query, args...)
+	err = sqlerr.Translate(err)
+
+	if c.observer != nil {
+		event := contract.QueryEvent{Operation: operation, SQL: query, Args: args, Duration: time.Since(start), Err: err}
+		if result != nil {
+			event.RowsAffected, _ = result.RowsAffected()
+		}
+		c.observer.observe(ctx, event)
+	}
+	return result, err
+}
+
+// query runs a read statement, passes its rows to scan, and reports it to the
+// observers as operation with the number of rows scan returns.
+func (c *connection) query(
+	ctx context.Context,
+	operation, query string,
+	args []any,
+	scan func(rows *sql.Rows) (int64, error),
+) error {
+	start := time.Now()
+	var count int64
+	rows, err := c.executor(ctx).QueryContext(ctx, query, args...)
+	if err == nil {
+		count, err = scan(rows)
+		if closeErr := rows.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	err = sqlerr.Translate(err)
+
+	if c.observer != nil {
+		c.observer.observe(ctx, contract.QueryEvent{
+			Operation:    operation,
+			SQL:          query,
+			Args:         args,
+			Duration:     time.Since(start),
+			RowsAffected: count,
+			Err:          err,
+		})
+	}
+	return err
+}
+
+// scanMaps reads every row into a map of column names to values. Byte slices
+// are converted to strings, as drivers return text columns as []byte.
+func scanMaps(rows *sql.Rows) ([]map[string]any, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read result columns: %w", err)
+	}
+	var results []map[string]any
+	for rows.Next() {
+		values := make([]any, len(columns))
+		targets := make([]any, len(columns))
+		for i := range values {
+			targets[i] = &values[i]
+		}
+		if err := rows.Scan(targets...); err != nil {
+			return nil, err //nolint:wrapcheck // translated by the caller
+		}
+		row := make(map[string]any, len(columns))
+		for i, name := range columns {
+			if b, ok := values[i].([]byte); ok {
+				values[i] = string(b)
+			}
+			row[name] = values[i]
+		}
+		results = append(results, row)
+	}
+	return results, rows.Err() //nolint:wrapcheck // translated by the caller
+}
@@ -0,0 +1,315 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/next-trace/scg-database/adapter/internal/poolstats"
+	"github.com/next-trace/scg-database/contract"
+	"github.com/next-trace/scg-database/db"
+	"github.com/stretchr/testify/require"
+)
+
+// setupMockConnection returns a Postgres connection on a sqlmock database.
+func setupMockConnection(t *testing.T) (*connection, sqlmock.Sqlmock) {
+	t.Helper()
+	sqlDB, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+	return &connection{db: sqlDB, dialect: dialects[DialectPostgres], health: &poolstats.Health{}}, mock
+}
+
+// countUsers returns the number of rows of the users table, soft deleted ones included.
+func countUsers(t *testing.T, conn contract.Connection, ctx context.Context) int64 {
+	t.Helper()
+	rows, err := conn.Select(ctx, "SELECT COUNT(*) AS n FROM users")
+	require.NoError(t, err)
+	return rows[0]["n"].(int64)
+}
+
+func TestConnection_Transaction(t *testing.T) {
+	conn := setupConnection(t, testSQLiteDriver)
+	ctx := t.Context()
+
+	t.Run("Commit", func(t *testing.T) {
+		err := conn.Transaction(ctx, func(tx contract.Connection) error {
+			repo, err := tx.NewRepository(&testUser{})
+			require.NoError(t, err)
+			require.IsType(t, &sql.Tx{}, tx.GetConnection())
+			return repo.Create(ctx, newUser(1))
+		})
+		require.NoError(t, err)
+		require.Equal(t, int64(1), countUsers(t, conn, ctx))
+	})
+
+	t.Run("Rollback", func(t *testing.T) {
+		txErr := errors.New("transaction error")
+		err := conn.Transaction(ctx, func(tx contract.Connection) error {
+			_, err := tx.Statement(ctx, "INSERT INTO users (name, email) VALUES (?, ?)", "x", "x@example.com")
+			require.NoError(t, err)
+			return txErr
+		})
+		require.ErrorIs(t, err, txErr)
+		require.Equal(t, int64(1), countUsers(t, conn, ctx))
+	})
+
+	t.Run("NestedRollbackToSavepoint", func(t *testing.T) {
+		innerErr := errors.New("inner error")
+		err := conn.Transaction(ctx, func(tx contract.Connection) error {
+			_, err := tx.Statement(ctx, "INSERT INTO users (name, email) VALUES (?, ?)", "outer", "outer@example.com")
+			require.NoError(t, err)
+			err = tx.Transaction(ctx, func(inner contract.Connection) error {
+				_, err := inner.Statement(ctx, "INSERT INTO users (name, email) VALUES (?, ?)", "inner", "inner@example.com")
+				require.NoError(t, err)
+				return innerErr
+			})
+			require.ErrorIs(t, err, innerErr)
+			return nil // The outer transaction handles the failure and still commits.
+		})
+		require.NoError(t, err)
+		rows, err := conn.Select(ctx, "SELECT name FROM users WHERE name IN ?", []string{"outer", "inner"})
+		require.NoError(t, err)
+		require.Equal(t, []map[string]any{{"name": "outer"}}, rows)
+	})
+
+	t.Run("Panic", func(t *testing.T) {
+		require.Panics(t, func() {
+			_ = conn.Transaction(ctx, func(tx contract.Connection) error {
+				_, err := tx.Statement(ctx, "INSERT INTO users (name, email) VALUES (?, ?)", "p", "p@example.com")
+				require.NoError(t, err)
+				panic("boom")
+			})
+		})
+		require.Equal(t, int64(2), countUsers(t, conn, ctx), "a panic rolls the transaction back")
+	})
+}
+
+func TestConnection_Savepoints(t *testing.T) {
+	t.Run("NestedRelease", func(t *testing.T) {
+		conn, mock := setupMockConnection(t)
+		mock.ExpectBegin()
+		mock.ExpectExec("SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("SAVEPOINT sp_2").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("RELEASE SAVEPOINT sp_2").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("RELEASE SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		err := conn.Transaction(t.Context(), func(tx contract.Connection) error {
+			return tx.Transaction(t.Context(), func(inner contract.Connection) error {
+				return inner.Transaction(t.Context(), func(contract.Connection) error { return nil })
+			})
+		})
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("NestedThroughContext", func(t *testing.T) {
+		conn, mock := setupMockConnection(t)
+		mock.ExpectBegin()
+		mock.ExpectExec("SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("SAVEPOINT sp_2").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("ROLLBACK TO SAVEPOINT sp_2").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("ROLLBACK TO SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		// Both savepoints are started from the ambient transaction of ctx.
+		err := db.InTransaction(t.Context(), conn, func(ctx context.Context) error {
+			middleErr := conn.Transaction(ctx, func(contract.Connection) error {
+				innerErr := conn.Transaction(ctx, func(contract.Connection) error { return errors.New("inner") })
+				return errors.Join(innerErr, errors.New("middle"))
+			})
+			require.Error(t, middleErr)
+			return nil
+		})
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("SavepointError", func(t *testing.T) {
+		conn, mock := setupMockConnection(t)
+		spErr := errors.New("savepoint error")
+		mock.ExpectBegin()
+		mock.ExpectExec("SAVEPOINT sp_1").WillReturnError(spErr)
+		mock.ExpectRollback()
+
+		err := conn.Transaction(t.Context(), func(tx contract.Connection) error {
+			return tx.Transaction(t.Context(), func(contract.Connection) error {
+				t.Fatal("inner function must not run when the savepoint fails")
+				return nil
+			})
+		})
+		require.ErrorIs(t, err, spErr)
+		require.Contains(t, err.Error(), "failed to create savepoint sp_1")
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestConnection_TransactionRetry(t *testing.T) {
+	policy := contract.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}
+	conn, mock := setupMockConnection(t)
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE accounts").WillReturnError(&pgconn.PgError{Code: "40001"})
+	mock.ExpectRollback()
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE accounts").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	calls := 0
+	err := conn.Transaction(t.Context(), func(tx contract.Connection) error {
+		calls++
+		_, err := tx.Statement(t.Context(), "UPDATE accounts SET balance = balance - 1")
+		return err
+	}, contract.WithRetry(policy))
+	require.NoError(t, err)
+	require.Equal(t, 2, calls)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestConnection_TransactionTimeout(t *testing.T) {
+	conn := setupConnection(t, testSQLiteDriver)
+
+	err := conn.Transaction(t.Context(), func(tx contract.Connection) error {
+		time.Sleep(50 * time.Millisecond)
+		_, err := tx.Statement(context.Background(), "INSERT INTO users (name, email) VALUES ('late', 'late@example.com')")
+		return err
+	}, contract.WithTxTimeout(10*time.Millisecond))
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Zero(t, countUsers(t, conn, t.Context()), "the timed out transaction should be rolled back")
+}
+
+func TestConnection_TxHooks(t *testing.T) {
+	conn := setupConnection(t, testSQLiteDriver)
+	var events []string
+	record := func(event string) func() { return func() { events = append(events, event) } }
+
+	err := conn.Transaction(t.Context(), func(tx contract.Connection) error {
+		hooks := tx.(contract.TxHooks)
+		hooks.OnCommit(record("outer commit"))
+		_ = tx.Transaction(t.Context(), func(inner contract.Connection) error {
+			inner.(contract.TxHooks).OnCommit(record("inner commit"))
+			inner.(contract.TxHooks).OnRollback(record("inner rollback"))
+			return errors.New("inner failure")
+		})
+		require.Empty(t, events, "hooks wait for the outermost transaction")
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"inner rollback", "outer commit"}, events)
+
+	events = nil
+	conn.(contract.TxHooks).OnCommit(record("immediate"))
+	conn.(contract.TxHooks).OnRollback(record("never"))
+	require.Equal(t, []string{"immediate"}, events)
+}
+
+func TestConnection_AmbientTransaction(t *testing.T) {
+	conn := setupConnection(t, testSQLiteDriver)
+	repo, err := conn.NewRepository(&testUser{})
+	require.NoError(t, err)
+
+	err = db.InTransaction(t.Context(), conn, func(ctx context.Context) error {
+		require.NoError(t, repo.Create(ctx, newUser(1)))
+		require.NoError(t, repo.QueryBuilder().Create(ctx, newUser(2)))
+		_, err := conn.Statement(ctx, "INSERT INTO users (name, email) VALUES (?, ?)", "raw", "raw@example.com")
+		require.NoError(t, err)
+
+		count, err := repo.QueryBuilder().Count(ctx)
+		require.NoError(t, err)
+		require.Equal(t, int64(3), count, "reads with the context should see the transaction's writes")
+		return errors.New("abort")
+	})
+	require.Error(t, err)
+	require.Zero(t, countUsers(t, conn, t.Context()), "the rollback should undo every write made with the context")
+
+	other := setupConnection(t, testSQLiteDriver)
+	err = db.InTransaction(t.Context(), other, func(ctx context.Context) error {
+		return errors.Join(repo.Create(ctx, newUser(3)), errors.New("abort"))
+	})
+	require.Error(t, err)
+	require.Equal(t, int64(1), countUsers(t, conn, t.Context()), "a transaction of another database is not joined")
+}
+
+func TestConnection_RawQueries(t *testing.T) {
+	conn := setupConnection(t, testSQLiteDriver)
+	ctx := t.Context()
+
+	result, err := conn.Statement(ctx, "INSERT INTO users (name, email, age) VALUES (?, ?, ?)", "raw", "raw@example.com", 7)
+	require.NoError(t, err)
+	affected, err := result.RowsAffected()
+	require.NoError(t, err)
+	require.Equal(t, int64(1), affected)
+
+	rows, err := conn.Select(ctx, "SELECT name, age FROM users WHERE age = ?", 7)
+	require.NoError(t, err)
+	require.Equal(t, []map[string]any{{"name": "raw", "age": int64(7)}}, rows)
+
+	_, err = conn.Statement(ctx, "INSERT INTO users (name, email) VALUES (?, ?)", "dup", "raw@example.com")
+	require.ErrorIs(t, err, db.ErrUniqueViolation)
+	_, err = conn.Select(ctx, "SELECT * FROM missing")
+	require.Error(t, err)
+}
+
+func TestConnection_QueryObserver(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		events []contract.QueryEvent
+	)
+	observer := contract.QueryObserverFunc(func(_ context.Context, event contract.QueryEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, event)
+	})
+	conn := setupConnection(t, testSQLiteDriver, db.WithQueryObserver(observer))
+	repo, err := conn.NewRepository(&testUser{})
+	require.NoError(t, err)
+
+	mu.Lock()
+	events = nil
+	mu.Unlock()
+	require.NoError(t, repo.Create(t.Context(), newUser(1)))
+	_, err = repo.Where("name = ?", "User 1").Get(t.Context())
+	require.NoError(t, err)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, events, 2)
+	require.Equal(t, "create", events[0].Operation)
+	require.Contains(t, events[0].SQL, `INSERT INTO "users"`)
+	require.Equal(t, int64(1), events[0].RowsAffected)
+	require.Equal(t, "query", events[1].Operation)
+	require.Equal(t, []any{db.RedactedArg}, events[1].Args, "arguments are redacted by default")
+	require.Equal(t, int64(1), events[1].RowsAffected)
+}
+
+func TestConnection_Stats(t *testing.T) {
+	conn, mock := setupMockConnection(t)
+	pingErr := errors.New("ping failed")
+	mock.ExpectPing()
+	mock.ExpectPing().WillReturnError(pingErr)
+
+	require.NoError(t, conn.Ping(t.Context()))
+	pool := conn.Stats().Pools[0]
+	require.Equal(t, "primary", pool.Name)
+	require.False(t, pool.LastPingAt.IsZero())
+	require.True(t, conn.Stats().Healthy())
+
+	require.ErrorIs(t, conn.Ping(t.Context()), pingErr)
+	require.ErrorIs(t, conn.Stats().Pools[0].LastPingError, pingErr)
+	require.False(t, conn.Stats().Healthy())
+}
+
+func TestConnection_NewRepository(t *testing.T) {
+	conn, _ := setupMockConnection(t)
+	_, err := conn.NewRepository(nil)
+	require.EqualError(t, err, "model cannot be nil")
+	var user *testUser
+	_, err = conn.NewRepository(user)
+	require.EqualError(t, err, "model cannot be nil")
+	require.IsType(t, &sql.DB{}, conn.GetConnection())
+}
@@ -0,0 +1,177 @@
+package sql
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	_ "github.com/go-sql-driver/mysql" // database/sql driver of the mysql dialect
+	_ "github.com/jackc/pgx/v5/stdlib" // database/sql driver of the postgres dialect
+	_ "github.com/mattn/go-sqlite3"    // database/sql driver of the sqlite dialect
+	"github.com/next-trace/scg-database/contract"
+	_ "modernc.org/sqlite" // pure-Go database/sql driver of the sqlite-pure dialect
+)
+
+type (
+	// dialect holds the SQL differences between the supported databases.
+	dialect struct {
+		name string
+		// driver is the database/sql driver name.
+		driver string
+		// migrationDriver is the driver of the migration package.
+		migrationDriver string
+		// numbered placeholders ($1, $2, ...) replace the `?` of MySQL and SQLite.
+		numbered bool
+		// quote is the identifier quote character.
+		quote byte
+		// returning reports whether INSERT ... RETURNING reads generated keys.
+		returning bool
+		// noLimit is the LIMIT that lets OFFSET be used without a limit, when
+		// the dialect requires one.
+		noLimit      string
+		truncate     func(table string) string
+		capabilities []contract.Capability
+	}
+)
+
+//nolint:grouper // Only One Global Variable
+var dialects = map[string]*dialect{
+	DialectMySQL: {
+		name:            DialectMySQL,
+		driver:          "mysql",
+		migrationDriver: "mysql",
+		quote:           '`',
+		noLimit:         "18446744073709551615",
+		truncate:        func(table string) string { return "TRUNCATE TABLE " + table },
+		capabilities: []contract.Capability{
+			contract.CapabilitySavepoints,
+			contract.CapabilityUpsert,
+			contract.CapabilityJSON,
+			contract.CapabilityRowLocking,
+		},
+	},
+	DialectPostgres: {
+		name:            DialectPostgres,
+		driver:          "pgx",
+		migrationDriver: "postgres",
+		numbered:        true,
+		quote:           '"',
+		returning:       true,
+		truncate:        func(table string) string { return "TRUNCATE TABLE " + table + " RESTART IDENTITY CASCADE" },
+		capabilities: []contract.Capability{
+			contract.CapabilitySavepoints,
+			contract.CapabilityReturning,
+			contract.CapabilityUpsert,
+			contract.CapabilityJSON,
+			contract.CapabilityRowLocking,
+		},
+	},
+	DialectSQLite: {
+		name:            DialectSQLite,
+		driver:          "sqlite3",
+		migrationDriver: "sqlite3",
+		quote:           '"',
+		noLimit:         "-1",
+		returning:       true,
+		truncate:        func(table string) string { return "DELETE FROM " + table },
+		capabilities: []contract.Capability{
+			contract.CapabilitySavepoints,
+			contract.CapabilityReturning,
+			contract.CapabilityUpsert,
+			contract.CapabilityJSON,
+		},
+	},
+	DialectSQLitePure: {
+		name:            DialectSQLitePure,
+		driver:          "sqlite",
+		migrationDriver: "sqlite",
+		quote:           '"',
+		noLimit:         "-1",
+		returning:       true,
+		truncate:        func(table string) string { return "DELETE FROM " + table },
+		capabilities: []contract.Capability{
+			contract.CapabilitySavepoints,
+			contract.CapabilityReturning,
+			contract.CapabilityUpsert,
+			contract.CapabilityJSON,
+		},
+	},
+}
+
+// lookupDialect returns the dialect of a driver name such as "sql:postgres".
+func lookupDialect(driver string) (*dialect, bool) {
+	name, found := strings.CutPrefix(driver, AdapterName+":")
+	if !found {
+		return nil, false
+	}
+	d, ok := dialects[name]
+	return d, ok
+}
+
+// quoteIdent quotes a column or table name; qualified names are quoted per part.
+func (d *dialect) quoteIdent(name string) string {
+	parts := strings.Split(name, ".")
+	q := string(d.quote)
+	for i, part := range parts {
+		parts[i] = q + strings.ReplaceAll(part, q, q+q) + q
+	}
+	return strings.Join(parts, ".")
+}
+
+// rebind rewrites the `?` placeholders of query for the dialect. Question marks
+// inside quoted strings and identifiers are left alone.
+func (d *dialect) rebind(query string) string {
+	if !d.numbered || !strings.Contains(query, "?") {
+		return query
+	}
+
+	var (
+		b     strings.Builder
+		n     int
+		quote byte
+	)
+	b.Grow(len(query) + 8)
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '?':
+			n++
+			b.WriteByte('$')
+			b.WriteString(strconv.Itoa(n))
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+// supports reports whether the dialect supports capability.
+func (d *dialect) supports(capability contract.Capability) bool {
+	if capability == contract.CapabilityMigrations {
+		return d.migrationDriver != ""
+	}
+	return slices.Contains(d.capabilities, capability)
+}
+
+// Supports reports whether the dialect of driver supports capability,
+// fulfilling contract.Capabilities.
+func (a *Adapter) Supports(driver string, capability contract.Capability) bool {
+	d, ok := lookupDialect(driver)
+	return ok && d.supports(capability)
+}
+
+// DialectInfo returns the migration driver and truncate statement of the
+// dialect of driver, fulfilling contract.Dialects.
+func (a *Adapter) DialectInfo(driver string) (contract.DialectInfo, bool) {
+	d, ok := lookupDialect(driver)
+	if !ok {
+		return contract.DialectInfo{}, false
+	}
+	return contract.DialectInfo{MigrationDriver: d.migrationDriver, TruncateStatement: d.truncate}, true
+}
@@ -0,0 +1,225 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+
+	"github.com/next-trace/scg-database/adapter/internal/schema"
+	"github.com/next-trace/scg-database/contract"
+	"github.com/next-trace/scg-database/db"
+)
+
+//nolint:grouper // Only One Global Variable
+var mapType = reflect.TypeFor[map[string]any]()
+
+// fetch runs the SELECT of q and returns pointers to the model structs it
+// read, with the relationships of q preloaded.
+func (c *connection) fetch(ctx context.Context, q *query) ([]reflect.Value, error) {
+	query, args, err := q.selectSQL()
+	if err != nil {
+		return nil, err
+	}
+	var rows []reflect.Value
+	err = c.query(ctx, "query", query, args, func(result *sql.Rows) (int64, error) {
+		var err error
+		rows, err = scanRows(q.schema, result)
+		return int64(len(rows)), err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := c.preload(ctx, q.schema, rows, q.preloads); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// fetchFirst returns the first row of q in primary key order, or an invalid
+// value when there is none.
+func (c *connection) fetchFirst(ctx context.Context, q *query) (reflect.Value, error) {
+	if q.raw == nil {
+		q = q.orderBy(q.column(q.schema.PrimaryKey.Name), OrderDirectionASC).withLimit(1)
+	}
+	rows, err := c.fetch(ctx, q)
+	if err != nil || len(rows) == 0 {
+		return reflect.Value{}, err
+	}
+	return rows[0], nil
+}
+
+// load runs the SELECT of q and stores the result in dest, which points to a
+// struct, a map[string]any, a scalar, or a slice of them. With first, only the
+// first row is read and db.ErrRecordNotFound is returned when there is none.
+func (c *connection) load(ctx context.Context, q *query, dest any, first bool) error {
+	dv := reflect.ValueOf(dest)
+	if dv.Kind() != reflect.Pointer || dv.IsNil() {
+		return fmt.Errorf("destination must be a non-nil pointer, got %T", dest)
+	}
+	target := dv.Elem()
+	elemType := target.Type()
+	isSlice := target.Kind() == reflect.Slice && elemType.Elem().Kind() != reflect.Uint8
+	if isSlice {
+		elemType = elemType.Elem()
+	}
+	if first {
+		if q.raw == nil && q.schema.PrimaryKey != nil && len(q.orders) == 0 {
+			q = q.orderBy(q.column(q.schema.PrimaryKey.Name), OrderDirectionASC)
+		}
+		q = q.withLimit(1)
+	}
+
+	values, err := c.loadValues(ctx, q, elemType)
+	if err != nil {
+		return err
+	}
+	switch {
+	case isSlice:
+		slice := reflect.MakeSlice(target.Type(), len(values), len(values))
+		for i, value := range values {
+			slice.Index(i).Set(value)
+		}
+		target.Set(slice)
+	case len(values) > 0:
+		target.Set(values[0])
+	case first:
+		return db.ErrRecordNotFound
+	}
+	return nil
+}
+
+// loadValues runs the SELECT of q and converts every row to a value of typ: a
+// struct or pointer to one, a map[string]any, or the scalar of a single column.
+func (c *connection) loadValues(ctx context.Context, q *query, typ reflect.Type) ([]reflect.Value, error) {
+	base := typ
+	if base.Kind() == reflect.Pointer {
+		base = base.Elem()
+	}
+
+	switch {
+	case typ == mapType:
+		return c.loadMaps(ctx, q)
+	case base.Kind() == reflect.Struct && schema.IsStructOfColumns(base):
+		s, err := schema.OfStruct(base)
+		if err != nil {
+			return nil, err
+		}
+		rows, err := c.fetchInto(ctx, q, s)
+		if err != nil {
+			return nil, err
+		}
+		if typ.Kind() != reflect.Pointer {
+			for i := range rows {
+				rows[i] = rows[i].Elem()
+			}
+		}
+		return rows, nil
+	default:
+		return c.loadScalars(ctx, q, typ)
+	}
+}
+
+// fetchInto runs the SELECT of q and reads its rows into structs of s.
+// Relationships are preloaded when s is a model.
+func (c *connection) fetchInto(ctx context.Context, q *query, s *schema.Schema) ([]reflect.Value, error) {
+	if s == q.schema {
+		return c.fetch(ctx, q)
+	}
+	query, args, err := q.selectSQL()
+	if err != nil {
+		return nil, err
+	}
+	var rows []reflect.Value
+	err = c.query(ctx, "query", query, args, func(result *sql.Rows) (int64, error) {
+		var err error
+		rows, err = scanRows(s, result)
+		return int64(len(rows)), err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if s.Table != "" {
+		err = c.preload(ctx, s, rows, q.preloads)
+	}
+	return rows, err
+}
+
+// loadMaps runs the SELECT of q and returns every row as a map[string]any.
+func (c *connection) loadMaps(ctx context.Context, q *query) ([]reflect.Value, error) {
+	query, args, err := q.selectSQL()
+	if err != nil {
+		return nil, err
+	}
+	var values []reflect.Value
+	err = c.query(ctx, "query", query, args, func(rows *sql.Rows) (int64, error) {
+		maps, err := scanMaps(rows)
+		for _, m := range maps {
+			values = append(values, reflect.ValueOf(m))
+		}
+		return int64(len(maps)), err
+	})
+	return values, err
+}
+
+// loadScalars runs the SELECT of q and returns the first column of every row
+// as a value of typ.
+func (c *connection) loadScalars(ctx context.Context, q *query, typ reflect.Type) ([]reflect.Value, error) {
+	query, args, err := q.selectSQL()
+	if err != nil {
+		return nil, err
+	}
+	var values []reflect.Value
+	err = c.query(ctx, "query", query, args, func(rows *sql.Rows) (int64, error) {
+		columns, err := rows.Columns()
+		if err != nil {
+			return 0, fmt.Errorf("failed to read result columns: %w", err)
+		}
+		for rows.Next() {
+			value := reflect.New(typ)
+			targets := make([]any, len(columns))
+			targets[0] = value.Interface()
+			for i := 1; i < len(targets); i++ {
+				targets[i] = new(any)
+			}
+			if err := rows.Scan(targets...); err != nil {
+				return 0, err //nolint:wrapcheck // translated by the caller
+			}
+			values = append(values, value.Elem())
+		}
+		return int64(len(values)), rows.Err() //nolint:wrapcheck // translated by the caller
+	})
+	return values, err
+}
+
+// modelsOf returns value as models: a model, a slice of models, or a pointer to
+// a slice of model structs.
+func modelsOf(value any) ([]contract.Model, error) {
+	switch value := value.(type) {
+	case contract.Model:
+		return []contract.Model{value}, nil
+	case []contract.Model:
+		return value, nil
+	}
+
+	v := reflect.ValueOf(value)
+	if v.Kind() == reflect.Pointer && v.Elem().Kind() == reflect.Slice {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("cannot create %T: expected a model or a slice of models", value)
+	}
+	models := make([]contract.Model, 0, v.Len())
+	for i := range v.Len() {
+		elem := v.Index(i)
+		if elem.Kind() != reflect.Pointer {
+			elem = elem.Addr()
+		}
+		model, ok := elem.Interface().(contract.Model)
+		if !ok {
+			return nil, fmt.Errorf("cannot create %T: %s is not a model", value, elem.Type())
+		}
+		models = append(models, model)
+	}
+	return models, nil
+}
@@ -0,0 +1,40 @@
+package sql
+
+import (
+	"context"
+
+	"github.com/next-trace/scg-database/config"
+	"github.com/next-trace/scg-database/contract"
+	"github.com/next-trace/scg-database/db"
+)
+
+type (
+	// queryObserver reports every statement to the observers registered with
+	// db.WithQueryObserver.
+	queryObserver struct {
+		observers []contract.QueryObserver
+		redact    func(arg any) any
+	}
+)
+
+// newQueryObserver returns the observer of cfg, or nil when no observer is registered.
+func newQueryObserver(cfg *config.Config) *queryObserver {
+	observers := db.QueryObservers(cfg)
+	if len(observers) == 0 {
+		return nil
+	}
+	return &queryObserver{observers: observers, redact: db.QueryArgRedactor(cfg)}
+}
+
+// observe redacts the event arguments and notifies every observer.
+func (o *queryObserver) observe(ctx context.Context, event contract.QueryEvent) {
+	args := make([]any, len(event.Args))
+	for i, arg := range event.Args {
+		args[i] = o.redact(arg)
+	}
+	event.Args = args
+
+	for _, observer := range o.observers {
+		observer.ObserveQuery(ctx, event)
+	}
+}
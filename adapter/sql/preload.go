@@ -0,0 +1,129 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+
+	"github.com/next-trace/scg-database/adapter/internal/schema"
+)
+
+// preload loads the named relationships of the owner rows, pointers to structs
+// of s, with one query per relationship.
+func (c *connection) preload(ctx context.Context, s *schema.Schema, rows []reflect.Value, relations []string) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	for _, name := range relations {
+		rel, err := s.Relation(name)
+		if err != nil {
+			return err
+		}
+		if rel.JoinTable != "" {
+			err = c.preloadManyToMany(ctx, rel, rows)
+		} else {
+			err = c.preloadByKey(ctx, rel, rows)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to preload %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// preloadByKey loads has-one, has-many and belongs-to relationships, whose
+// related rows hold the owner key in relatedKey.
+func (c *connection) preloadByKey(ctx context.Context, rel *schema.Relation, rows []reflect.Value) error {
+	ownerKey := rel.Owner.ByName[rel.OwnerKey]
+	keys := schema.DistinctKeys(rows, ownerKey)
+	if len(keys) == 0 {
+		return nil
+	}
+	related, err := c.fetchRelated(ctx, rel.Related, rel.RelatedKey, keys)
+	if err != nil {
+		return err
+	}
+
+	relatedKey := rel.Related.ByName[rel.RelatedKey]
+	byKey := make(map[string][]reflect.Value)
+	for _, row := range related {
+		key := schema.KeyOf(relatedKey.Value(row.Elem()))
+		byKey[key] = append(byKey[key], row)
+	}
+	for _, row := range rows {
+		key := schema.KeyOf(ownerKey.Value(row.Elem()))
+		if err := rel.Assign(row, byKey[key]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// preloadManyToMany loads a many-to-many relationship through its join table.
+func (c *connection) preloadManyToMany(ctx context.Context, rel *schema.Relation, rows []reflect.Value) error {
+	ownerKey := rel.Owner.ByName[rel.OwnerKey]
+	keys := schema.DistinctKeys(rows, ownerKey)
+	if len(keys) == 0 {
+		return nil
+	}
+
+	// Read the pairs of the join table first, then the related rows.
+	d := c.dialect
+	query, args := expandArgs(
+		"SELECT "+d.quoteIdent(rel.OwnerJoinKey)+", "+d.quoteIdent(rel.RelatedJoinKey)+
+			" FROM "+d.quoteIdent(rel.JoinTable)+" WHERE "+d.quoteIdent(rel.OwnerJoinKey)+" IN ?",
+		[]any{keys},
+	)
+	pairs := make(map[string][]string)
+	var relatedKeys []any
+	seen := make(map[string]bool)
+	err := c.query(ctx, "query", d.rebind(query), args, func(result *sql.Rows) (int64, error) {
+		var n int64
+		for result.Next() {
+			var owner, related any
+			if err := result.Scan(&owner, &related); err != nil {
+				return n, err //nolint:wrapcheck // translated by the caller
+			}
+			n++
+			pairs[schema.KeyOf(owner)] = append(pairs[schema.KeyOf(owner)], schema.KeyOf(related))
+			if !seen[schema.KeyOf(related)] {
+				seen[schema.KeyOf(related)] = true
+				relatedKeys = append(relatedKeys, related)
+			}
+		}
+		return n, result.Err() //nolint:wrapcheck // translated by the caller
+	})
+	if err != nil || len(relatedKeys) == 0 {
+		return err
+	}
+
+	related, err := c.fetchRelated(ctx, rel.Related, rel.RelatedKey, relatedKeys)
+	if err != nil {
+		return err
+	}
+	relatedKey := rel.Related.ByName[rel.RelatedKey]
+	byKey := make(map[string]reflect.Value, len(related))
+	for _, row := range related {
+		byKey[schema.KeyOf(relatedKey.Value(row.Elem()))] = row
+	}
+	for _, row := range rows {
+		var items []reflect.Value
+		for _, key := range pairs[schema.KeyOf(ownerKey.Value(row.Elem()))] {
+			if item, ok := byKey[key]; ok {
+				items = append(items, item)
+			}
+		}
+		if err := rel.Assign(row, items); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fetchRelated returns the rows of s whose column holds one of keys.
+func (c *connection) fetchRelated(ctx context.Context, s *schema.Schema, column string, keys []any) ([]reflect.Value, error) {
+	q := newQuery(c.dialect, s)
+	q = q.where(q.column(column)+" IN ?", []any{keys}, false)
+	return c.fetch(ctx, q)
+}
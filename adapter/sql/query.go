@@ -0,0 +1,502 @@
+package sql
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/next-trace/scg-database/adapter/internal/schema"
+	"github.com/next-trace/scg-database/contract"
+)
+
+// SQL direction constants
+const (
+	OrderDirectionASC  = "ASC"
+	OrderDirectionDESC = "DESC"
+)
+
+// ErrMissingWhereClause is returned by updates and deletes without conditions,
+// which would otherwise change every row of the table.
+//
+//nolint:grouper // Only One Global Variable
+var ErrMissingWhereClause = errors.New("WHERE conditions required")
+
+type (
+	// condition is one WHERE or HAVING condition with its arguments.
+	condition struct {
+		sql  string
+		args []any
+		// or joins the condition to the previous ones with OR instead of AND.
+		or bool
+	}
+
+	// assignment is one column = value pair of an UPDATE.
+	assignment struct {
+		column string
+		value  any
+	}
+
+	// query holds the clauses of a statement on the table of a model. Its
+	// methods return modified copies, so queries can be shared by chains.
+	query struct {
+		dialect  *dialect
+		schema   *schema.Schema
+		selects  []string
+		joins    []string
+		wheres   []condition
+		groups   []string
+		havings  []condition
+		orders   []string
+		limit    int // -1 when unset
+		offset   int // -1 when unset
+		unscoped bool
+		preloads []string
+		// raw replaces the generated SELECT when set by QueryBuilder.Raw.
+		raw *condition
+		// err is the first error of the chain, reported when the query runs.
+		err error
+	}
+)
+
+func newQuery(d *dialect, s *schema.Schema) *query {
+	return &query{dialect: d, schema: s, limit: -1, offset: -1}
+}
+
+// clone returns a copy of q whose clause slices are clipped, so appending to
+// the copy never writes to the backing arrays of q.
+func (q *query) clone() *query {
+	c := *q
+	c.selects = slices.Clip(q.selects)
+	c.joins = slices.Clip(q.joins)
+	c.wheres = slices.Clip(q.wheres)
+	c.groups = slices.Clip(q.groups)
+	c.havings = slices.Clip(q.havings)
+	c.orders = slices.Clip(q.orders)
+	c.preloads = slices.Clip(q.preloads)
+	return &c
+}
+
+// fail returns a copy of q that reports err when it runs.
+func (q *query) fail(err error) *query {
+	c := q.clone()
+	if c.err == nil {
+		c.err = err
+	}
+	return c
+}
+
+// where adds a condition. cond is a SQL string with `?` placeholders, a map of
+// column values, a model whose non-zero fields must match, or a primary key
+// value (a slice of them matches any). Slice arguments of a SQL string expand
+// to a parenthesized list, so "id IN ?" works with a slice.
+func (q *query) where(cond any, args []any, or bool) *query {
+	sql, condArgs, err := q.condition(cond, args)
+	if err != nil {
+		return q.fail(err)
+	}
+	if sql == "" {
+		return q
+	}
+	c := q.clone()
+	c.wheres = append(c.wheres, condition{sql: sql, args: condArgs, or: or})
+	return c
+}
+
+func (q *query) condition(cond any, args []any) (string, []any, error) {
+	switch cond := cond.(type) {
+	case string:
+		return cond, args, nil
+	case map[string]any:
+		return q.mapCondition(cond), mapValues(cond), nil
+	case contract.Model:
+		return q.modelCondition(cond)
+	case nil:
+		return "", nil, errors.New("where condition cannot be nil")
+	default:
+		if q.schema.PrimaryKey == nil {
+			return "", nil, fmt.Errorf("unsupported where condition %T", cond)
+		}
+		pk := q.column(q.schema.PrimaryKey.Name)
+		if isList(cond) {
+			return pk + " IN ?", []any{cond}, nil
+		}
+		return pk + " = ?", []any{cond}, nil
+	}
+}
+
+// mapCondition matches the columns of m in key order; nil matches NULL.
+func (q *query) mapCondition(m map[string]any) string {
+	parts := make([]string, 0, len(m))
+	for _, key := range sortedKeys(m) {
+		switch {
+		case m[key] == nil:
+			parts = append(parts, q.column(key)+" IS NULL")
+		case isList(m[key]):
+			parts = append(parts, q.column(key)+" IN ?")
+		default:
+			parts = append(parts, q.column(key)+" = ?")
+		}
+	}
+	return strings.Join(parts, " AND ")
+}
+
+// mapValues returns the non-nil values of m in key order.
+func mapValues(m map[string]any) []any {
+	values := make([]any, 0, len(m))
+	for _, key := range sortedKeys(m) {
+		if m[key] != nil {
+			values = append(values, m[key])
+		}
+	}
+	return values
+}
+
+// modelCondition matches the non-zero fields of model, as GORM does.
+func (q *query) modelCondition(model contract.Model) (string, []any, error) {
+	s, err := schema.Of(model)
+	if err != nil {
+		return "", nil, err
+	}
+	v := reflect.ValueOf(model).Elem()
+	var (
+		parts []string
+		args  []any
+	)
+	for _, col := range s.Columns {
+		if col.IsZero(v) {
+			continue
+		}
+		parts = append(parts, q.column(col.Name)+" = ?")
+		args = append(args, col.Value(v))
+	}
+	return strings.Join(parts, " AND "), args, nil
+}
+
+// column quotes a column name. Names of the model table are qualified with it,
+// so they stay unambiguous in joins.
+func (q *query) column(name string) string {
+	if !strings.Contains(name, ".") && q.schema.Table != "" && q.schema.ByName[name] != nil {
+		return q.dialect.quoteIdent(q.schema.Table) + "." + q.dialect.quoteIdent(name)
+	}
+	return q.dialect.quoteIdent(name)
+}
+
+// orderBy adds an ORDER BY column. Invalid directions fall back to ASC.
+func (q *query) orderBy(column, direction string) *query {
+	direction = strings.ToUpper(strings.TrimSpace(direction))
+	if direction != OrderDirectionASC && direction != OrderDirectionDESC {
+		direction = OrderDirectionASC
+	}
+	c := q.clone()
+	c.orders = append(c.orders, column+" "+direction)
+	return c
+}
+
+// withLimit sets the LIMIT; negative values leave the query unchanged.
+func (q *query) withLimit(limit int) *query {
+	if limit < 0 {
+		return q
+	}
+	c := q.clone()
+	c.limit = limit
+	return c
+}
+
+// withOffset sets the OFFSET; negative values leave the query unchanged.
+func (q *query) withOffset(offset int) *query {
+	if offset < 0 {
+		return q
+	}
+	c := q.clone()
+	c.offset = offset
+	return c
+}
+
+// withUnscoped disables the soft delete scope.
+func (q *query) withUnscoped() *query {
+	c := q.clone()
+	c.unscoped = true
+	return c
+}
+
+// withPreload loads the given relationships after the query runs.
+func (q *query) withPreload(relations ...string) *query {
+	c := q.clone()
+	c.preloads = append(c.preloads, relations...)
+	return c
+}
+
+// scoped reports whether soft deleted rows are excluded.
+func (q *query) scoped() bool {
+	return q.schema.SoftDelete && !q.unscoped
+}
+
+// table returns the quoted table name.
+func (q *query) table() string {
+	return q.dialect.quoteIdent(q.schema.Table)
+}
+
+// selectSQL builds the SELECT statement of the query.
+func (q *query) selectSQL() (string, []any, error) {
+	if q.err != nil {
+		return "", nil, q.err
+	}
+	if q.raw != nil {
+		sql, args := expandArgs(q.raw.sql, q.raw.args)
+		return q.dialect.rebind(sql), args, nil
+	}
+
+	var b sqlBuilder
+	b.write("SELECT ")
+	switch {
+	case len(q.selects) > 0:
+		b.write(strings.Join(q.selects, ", "))
+	case len(q.joins) > 0:
+		b.write(q.table() + ".*")
+	default:
+		b.write("*")
+	}
+	q.writeFrom(&b)
+	if len(q.groups) > 0 {
+		b.write(" GROUP BY " + strings.Join(q.groups, ", "))
+	}
+	if len(q.havings) > 0 {
+		b.write(" HAVING ")
+		b.conditions(q.havings)
+	}
+	if len(q.orders) > 0 {
+		b.write(" ORDER BY " + strings.Join(q.orders, ", "))
+	}
+	q.writeLimit(&b)
+	return q.dialect.rebind(b.String()), b.args, nil
+}
+
+// countSQL builds a statement counting the rows of the query. Grouped and raw
+// queries are counted as a subquery.
+func (q *query) countSQL() (string, []any, error) {
+	if q.raw != nil || len(q.groups) > 0 {
+		inner := q.clone()
+		inner.orders, inner.limit, inner.offset = nil, -1, -1
+		sql, args, err := inner.selectSQL()
+		if err != nil {
+			return "", nil, err
+		}
+		return "SELECT COUNT(*) FROM (" + sql + ") counted", args, nil
+	}
+	if q.err != nil {
+		return "", nil, q.err
+	}
+
+	var b sqlBuilder
+	b.write("SELECT COUNT(*)")
+	q.writeFrom(&b)
+	return q.dialect.rebind(b.String()), b.args, nil
+}
+
+// updateSQL builds an UPDATE of the rows of the query.
+func (q *query) updateSQL(assignments []assignment) (string, []any, error) {
+	if q.err != nil {
+		return "", nil, q.err
+	}
+	if len(assignments) == 0 {
+		return "", nil, errors.New("no columns to update")
+	}
+	if len(q.wheres) == 0 {
+		return "", nil, ErrMissingWhereClause
+	}
+
+	var b sqlBuilder
+	b.write("UPDATE " + q.table() + " SET ")
+	for i, a := range assignments {
+		if i > 0 {
+			b.write(", ")
+		}
+		b.write(q.dialect.quoteIdent(a.column) + " = ?")
+		b.args = append(b.args, a.value)
+	}
+	q.writeWhere(&b)
+	return q.dialect.rebind(b.String()), b.args, nil
+}
+
+// deleteSQL builds a DELETE of the rows of the query. With the soft delete
+// scope, the rows are marked deleted at deletedAt instead.
+func (q *query) deleteSQL(deletedAt any) (string, []any, error) {
+	if q.scoped() {
+		return q.updateSQL([]assignment{{column: schema.DeletedAtColumn, value: deletedAt}})
+	}
+	if q.err != nil {
+		return "", nil, q.err
+	}
+	if len(q.wheres) == 0 {
+		return "", nil, ErrMissingWhereClause
+	}
+
+	var b sqlBuilder
+	b.write("DELETE FROM " + q.table())
+	q.writeWhere(&b)
+	return q.dialect.rebind(b.String()), b.args, nil
+}
+
+// insertSQL builds an INSERT of rows with the given columns. The generated
+// primary key is returned by dialects with RETURNING when returning is set.
+func (q *query) insertSQL(columns []string, rows [][]any, returning bool) (string, []any) {
+	var b sqlBuilder
+	quoted := make([]string, len(columns))
+	for i, name := range columns {
+		quoted[i] = q.dialect.quoteIdent(name)
+	}
+	placeholders := "(" + strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ") + ")"
+
+	b.write("INSERT INTO " + q.table() + " (" + strings.Join(quoted, ", ") + ") VALUES ")
+	for i, row := range rows {
+		if i > 0 {
+			b.write(", ")
+		}
+		b.write(placeholders)
+		b.args = append(b.args, row...)
+	}
+	if returning && q.dialect.returning {
+		b.write(" RETURNING " + q.dialect.quoteIdent(q.schema.PrimaryKey.Name))
+	}
+	return q.dialect.rebind(b.String()), b.args
+}
+
+// writeFrom writes the FROM, JOIN and WHERE clauses.
+func (q *query) writeFrom(b *sqlBuilder) {
+	b.write(" FROM " + q.table())
+	for _, join := range q.joins {
+		b.write(" " + join)
+	}
+	q.writeWhere(b)
+}
+
+// writeWhere writes the WHERE clause, including the soft delete scope.
+func (q *query) writeWhere(b *sqlBuilder) {
+	if len(q.wheres) == 0 && !q.scoped() {
+		return
+	}
+	b.write(" WHERE ")
+	hasOr := slices.ContainsFunc(q.wheres, func(c condition) bool { return c.or })
+	if hasOr && q.scoped() {
+		b.write("(")
+	}
+	b.conditions(q.wheres)
+	if hasOr && q.scoped() {
+		b.write(")")
+	}
+	if q.scoped() {
+		if len(q.wheres) > 0 {
+			b.write(" AND ")
+		}
+		b.write(q.column(schema.DeletedAtColumn) + " IS NULL")
+	}
+}
+
+// writeLimit writes the LIMIT and OFFSET clauses.
+func (q *query) writeLimit(b *sqlBuilder) {
+	switch {
+	case q.limit >= 0:
+		b.write(" LIMIT " + strconv.Itoa(q.limit))
+	case q.offset >= 0 && q.dialect.noLimit != "":
+		b.write(" LIMIT " + q.dialect.noLimit)
+	}
+	if q.offset >= 0 {
+		b.write(" OFFSET " + strconv.Itoa(q.offset))
+	}
+}
+
+type (
+	// sqlBuilder accumulates the text and arguments of a statement.
+	sqlBuilder struct {
+		strings.Builder
+		args []any
+	}
+)
+
+func (b *sqlBuilder) write(s string) {
+	b.WriteString(s)
+}
+
+// conditions writes each condition in parentheses, joined by AND or OR.
+func (b *sqlBuilder) conditions(conditions []condition) {
+	for i, c := range conditions {
+		if i > 0 {
+			if c.or {
+				b.write(" OR ")
+			} else {
+				b.write(" AND ")
+			}
+		}
+		sql, args := expandArgs(c.sql, c.args)
+		b.write("(" + sql + ")")
+		b.args = append(b.args, args...)
+	}
+}
+
+// expandArgs replaces the placeholder of every slice argument with a
+// parenthesized list of placeholders, one per element. An empty slice becomes
+// (NULL), which matches nothing.
+func expandArgs(sql string, args []any) (string, []any) {
+	if !slices.ContainsFunc(args, isList) {
+		return sql, args
+	}
+
+	var (
+		b        strings.Builder
+		expanded = make([]any, 0, len(args))
+		n        int
+		quote    byte
+	)
+	for i := 0; i < len(sql); i++ {
+		c := sql[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '?' && n < len(args):
+			arg := args[n]
+			n++
+			if !isList(arg) {
+				expanded = append(expanded, arg)
+				break
+			}
+			list := reflect.ValueOf(arg)
+			if list.Len() == 0 {
+				b.WriteString("(NULL)")
+				continue
+			}
+			b.WriteString("(" + strings.TrimSuffix(strings.Repeat("?, ", list.Len()), ", ") + ")")
+			for j := range list.Len() {
+				expanded = append(expanded, list.Index(j).Interface())
+			}
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return b.String(), append(expanded, args[n:]...)
+}
+
+// isList reports whether arg is a slice or array to expand into a list,
+// rather than a value such as []byte or a driver.Valuer.
+func isList(arg any) bool {
+	if _, ok := arg.(driver.Valuer); ok {
+		return false
+	}
+	v := reflect.ValueOf(arg)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return false
+	}
+	return v.Type().Elem().Kind() != reflect.Uint8
+}
+
+func sortedKeys(m map[string]any) []string {
+	return slices.Sorted(maps.Keys(m))
+}
@@ -0,0 +1,302 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/next-trace/scg-database/adapter/internal/schema"
+	"github.com/next-trace/scg-database/contract"
+)
+
+type (
+	// queryBuilder implements the contract.QueryBuilder interface on database/sql.
+	// Column names and conditions are used as given, as in the GORM adapter;
+	// values are always bound as arguments.
+	queryBuilder struct {
+		conn  *connection
+		model contract.Model
+		q     *query
+	}
+)
+
+// Ensure queryBuilder implements contract.QueryBuilder
+var (
+	_ contract.QueryBuilder = (*queryBuilder)(nil)
+)
+
+// newQueryBuilder creates a query builder for model on a connection of this adapter.
+func newQueryBuilder(model contract.Model, conn any) *queryBuilder {
+	c, ok := conn.(*connection)
+	if !ok {
+		panic("connection must be a contract.Connection opened by the sql adapter")
+	}
+	s, err := schema.Of(model)
+	if err != nil {
+		panic(fmt.Sprintf("invalid query builder model: %v", err))
+	}
+	return &queryBuilder{conn: c, model: model, q: newQuery(c.dialect, s)}
+}
+
+// with returns a query builder of the same model using q.
+func (b *queryBuilder) with(q *query) contract.QueryBuilder {
+	return &queryBuilder{conn: b.conn, model: b.model, q: q}
+}
+
+// Query building methods
+
+func (b *queryBuilder) Select(columns ...string) contract.QueryBuilder {
+	q := b.q.clone()
+	q.selects = columns
+	return b.with(q)
+}
+
+func (b *queryBuilder) Where(condition string, args ...any) contract.QueryBuilder {
+	return b.with(b.q.where(condition, args, false))
+}
+
+func (b *queryBuilder) WhereIn(column string, values []any) contract.QueryBuilder {
+	return b.with(b.q.where(column+" IN ?", []any{values}, false))
+}
+
+func (b *queryBuilder) WhereNotIn(column string, values []any) contract.QueryBuilder {
+	return b.with(b.q.where(column+" NOT IN ?", []any{values}, false))
+}
+
+func (b *queryBuilder) WhereNull(column string) contract.QueryBuilder {
+	return b.with(b.q.where(column+" IS NULL", nil, false))
+}
+
+func (b *queryBuilder) WhereNotNull(column string) contract.QueryBuilder {
+	return b.with(b.q.where(column+" IS NOT NULL", nil, false))
+}
+
+func (b *queryBuilder) WhereBetween(column string, start, end any) contract.QueryBuilder {
+	return b.with(b.q.where(column+" BETWEEN ? AND ?", []any{start, end}, false))
+}
+
+func (b *queryBuilder) OrWhere(condition string, args ...any) contract.QueryBuilder {
+	return b.with(b.q.where(condition, args, true))
+}
+
+// Join methods
+
+func (b *queryBuilder) Join(table, condition string) contract.QueryBuilder {
+	return b.join("JOIN", table, condition)
+}
+
+func (b *queryBuilder) LeftJoin(table, condition string) contract.QueryBuilder {
+	return b.join("LEFT JOIN", table, condition)
+}
+
+func (b *queryBuilder) RightJoin(table, condition string) contract.QueryBuilder {
+	return b.join("RIGHT JOIN", table, condition)
+}
+
+func (b *queryBuilder) InnerJoin(table, condition string) contract.QueryBuilder {
+	return b.join("INNER JOIN", table, condition)
+}
+
+func (b *queryBuilder) join(kind, table, condition string) contract.QueryBuilder {
+	q := b.q.clone()
+	q.joins = append(q.joins, fmt.Sprintf("%s %s ON %s", kind, table, condition))
+	return b.with(q)
+}
+
+// Ordering and grouping
+
+func (b *queryBuilder) OrderBy(column, direction string) contract.QueryBuilder {
+	return b.with(b.q.orderBy(column, direction))
+}
+
+func (b *queryBuilder) GroupBy(columns ...string) contract.QueryBuilder {
+	q := b.q.clone()
+	q.groups = append(q.groups, columns...)
+	return b.with(q)
+}
+
+func (b *queryBuilder) Having(cond string, args ...any) contract.QueryBuilder {
+	q := b.q.clone()
+	q.havings = append(q.havings, condition{sql: cond, args: args})
+	return b.with(q)
+}
+
+// Limiting and pagination
+
+func (b *queryBuilder) Limit(limit int) contract.QueryBuilder {
+	return b.with(b.q.withLimit(limit))
+}
+
+func (b *queryBuilder) Offset(offset int) contract.QueryBuilder {
+	return b.with(b.q.withOffset(offset))
+}
+
+// Relationships
+
+func (b *queryBuilder) With(relations ...string) contract.QueryBuilder {
+	return b.with(b.q.withPreload(relations...))
+}
+
+// WithCount is accepted for compatibility and, as in the GORM adapter, does not
+// change the query.
+func (b *queryBuilder) WithCount(...string) contract.QueryBuilder {
+	return b
+}
+
+// Scopes and advanced features
+
+// Scoped restores the soft delete scope removed by Unscoped.
+func (b *queryBuilder) Scoped() contract.QueryBuilder {
+	q := b.q.clone()
+	q.unscoped = false
+	return b.with(q)
+}
+
+func (b *queryBuilder) Unscoped() contract.QueryBuilder {
+	return b.with(b.q.withUnscoped())
+}
+
+// Execution methods
+
+// Find reads the matching rows into dest: a pointer to a slice of structs,
+// struct pointers, maps or scalars, or to a single one of them.
+func (b *queryBuilder) Find(ctx context.Context, dest any) error {
+	return b.conn.load(ctx, b.q, dest, false)
+}
+
+// First reads the first row in primary key order into dest, and returns
+// db.ErrRecordNotFound when there is none.
+func (b *queryBuilder) First(ctx context.Context, dest any) error {
+	return b.conn.load(ctx, b.q, dest, true)
+}
+
+func (b *queryBuilder) Get(ctx context.Context, dest any) error {
+	return b.conn.load(ctx, b.q, dest, false)
+}
+
+func (b *queryBuilder) Count(ctx context.Context) (int64, error) {
+	query, args, err := b.q.countSQL()
+	if err != nil {
+		return 0, err
+	}
+	var count int64
+	err = b.conn.query(ctx, "query", query, args, func(rows *sql.Rows) (int64, error) {
+		if rows.Next() {
+			if err := rows.Scan(&count); err != nil {
+				return 0, err //nolint:wrapcheck // translated by the caller
+			}
+		}
+		return 1, rows.Err() //nolint:wrapcheck // translated by the caller
+	})
+	return count, err
+}
+
+func (b *queryBuilder) Exists(ctx context.Context) (bool, error) {
+	count, err := b.Count(ctx)
+	return count > 0, err
+}
+
+// Mutation methods
+
+// Create inserts value, a model or a slice of models of the builder model type.
+func (b *queryBuilder) Create(ctx context.Context, value any) error {
+	models, err := modelsOf(value)
+	if err != nil {
+		return err
+	}
+	repo := &repository{conn: b.conn, mdl: b.model, q: b.q}
+	return repo.Create(ctx, models...)
+}
+
+// Update sets the columns of values, a map or a struct whose non-zero fields
+// are used, on the matching rows. UpdatedAt is refreshed for models with
+// timestamps.
+func (b *queryBuilder) Update(ctx context.Context, values any) error {
+	repo := &repository{conn: b.conn, mdl: b.model, q: b.q}
+	assignments, err := repo.assignments(values)
+	if err != nil {
+		return err
+	}
+	if len(assignments) == 0 {
+		return errors.New("no columns to update")
+	}
+	hasUpdatedAt := slices.ContainsFunc(assignments, func(a assignment) bool { return a.column == schema.UpdatedAtColumn })
+	if b.q.schema.Timestamps && !hasUpdatedAt {
+		assignments = append(assignments, assignment{column: schema.UpdatedAtColumn, value: time.Now()})
+	}
+	query, args, err := b.q.updateSQL(assignments)
+	if err != nil {
+		return err
+	}
+	_, err = b.conn.exec(ctx, "update", query, args)
+	return err
+}
+
+// Delete deletes the matching rows, or marks them deleted for models with
+// soft deletes.
+func (b *queryBuilder) Delete(ctx context.Context) error {
+	query, args, err := b.q.deleteSQL(time.Now())
+	if err != nil {
+		return err
+	}
+	_, err = b.conn.exec(ctx, "delete", query, args)
+	return err
+}
+
+// Raw query methods
+
+// Raw replaces the generated SELECT with sql. Its `?` placeholders are rebound
+// for the dialect and slice arguments expand to lists.
+func (b *queryBuilder) Raw(sql string, args ...any) contract.QueryBuilder {
+	q := b.q.clone()
+	q.raw = &condition{sql: sql, args: args}
+	return b.with(q)
+}
+
+// Exec runs a write statement; like Raw, it rebinds `?` placeholders.
+func (b *queryBuilder) Exec(ctx context.Context, sql string, args ...any) error {
+	query, args := expandArgs(sql, args)
+	_, err := b.conn.exec(ctx, "raw", b.q.dialect.rebind(query), args)
+	return err
+}
+
+// Utility methods
+
+// ToSQL returns the SELECT statement of the builder with its arguments, without
+// running it.
+func (b *queryBuilder) ToSQL() (sql string, args []any, err error) {
+	return b.q.selectSQL()
+}
+
+// Clone returns an independent copy of the builder.
+func (b *queryBuilder) Clone() contract.QueryBuilder {
+	return b.with(b.q.clone())
+}
+
+// Reset returns a builder of the same model without any clauses.
+func (b *queryBuilder) Reset() contract.QueryBuilder {
+	return b.with(newQuery(b.q.dialect, b.q.schema))
+}
+
+type (
+	// QueryBuilderFactory implements contract.QueryBuilderFactory for the sql
+	// adapter. The connection passed to NewQueryBuilder must be a
+	// contract.Connection opened by this adapter.
+	QueryBuilderFactory struct{}
+)
+
+// Ensure QueryBuilderFactory implements contract.QueryBuilderFactory
+var (
+	_ contract.QueryBuilderFactory = (*QueryBuilderFactory)(nil)
+)
+
+func (f *QueryBuilderFactory) NewQueryBuilder(model contract.Model, connection any) contract.QueryBuilder {
+	return newQueryBuilder(model, connection)
+}
+
+func (f *QueryBuilderFactory) Name() string {
+	return AdapterName
+}
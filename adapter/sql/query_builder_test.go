@@ -0,0 +1,202 @@
+package sql
+
+import (
+	"testing"
+
+	"github.com/next-trace/scg-database/contract"
+	"github.com/next-trace/scg-database/db"
+	"github.com/stretchr/testify/require"
+)
+
+// setupQueryBuilder returns a query builder of testUser with three users.
+func setupQueryBuilder(t *testing.T) (contract.Connection, contract.QueryBuilder) {
+	t.Helper()
+	conn := setupConnection(t, testSQLiteDriver)
+	repo, err := conn.NewRepository(&testUser{})
+	require.NoError(t, err)
+	require.NoError(t, repo.Create(t.Context(), newUser(1), newUser(2), newUser(3)))
+
+	factory, err := db.GetQueryBuilderFactory(AdapterName)
+	require.NoError(t, err)
+	return conn, factory.NewQueryBuilder(&testUser{}, conn)
+}
+
+func TestQueryBuilder_Filters(t *testing.T) {
+	_, qb := setupQueryBuilder(t)
+	ctx := t.Context()
+
+	tests := []struct {
+		name  string
+		qb    contract.QueryBuilder
+		count int64
+	}{
+		{"where", qb.Where("age > ?", 21), 2},
+		{"or where", qb.Where("age = ?", 21).OrWhere("age = ?", 23), 2},
+		{"where in", qb.WhereIn("name", []any{"User 1", "User 2"}), 2},
+		{"where not in", qb.WhereNotIn("name", []any{"User 1"}), 2},
+		{"where null", qb.WhereNull("deleted_at"), 3},
+		{"where not null", qb.WhereNotNull("deleted_at"), 0},
+		{"where between", qb.WhereBetween("age", 21, 22), 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			count, err := tt.qb.Count(ctx)
+			require.NoError(t, err)
+			require.Equal(t, tt.count, count)
+		})
+	}
+
+	exists, err := qb.Where("name = ?", "nobody").Exists(ctx)
+	require.NoError(t, err)
+	require.False(t, exists)
+}
+
+func TestQueryBuilder_Find(t *testing.T) {
+	_, qb := setupQueryBuilder(t)
+	ctx := t.Context()
+
+	var users []testUser
+	require.NoError(t, qb.OrderBy("age", "desc").Limit(2).Offset(1).Find(ctx, &users))
+	require.Len(t, users, 2)
+	require.Equal(t, "User 2", users[0].Name)
+
+	var pointers []*testUser
+	require.NoError(t, qb.Get(ctx, &pointers))
+	require.Len(t, pointers, 3)
+
+	var user testUser
+	require.NoError(t, qb.Where("age > ?", 21).First(ctx, &user))
+	require.Equal(t, "User 2", user.Name)
+	require.ErrorIs(t, qb.Where("age > ?", 100).First(ctx, &user), db.ErrRecordNotFound)
+
+	var rows []map[string]any
+	require.NoError(t, qb.Select("name", "age").OrderBy("id", "asc").Find(ctx, &rows))
+	require.Equal(t, map[string]any{"name": "User 1", "age": int64(21)}, rows[0])
+
+	var names []string
+	require.NoError(t, qb.Select("name").Where("age < ?", 23).Find(ctx, &names))
+	require.Equal(t, []string{"User 1", "User 2"}, names)
+
+	var total int
+	require.NoError(t, qb.Select("SUM(age)").First(ctx, &total))
+	require.Equal(t, 66, total)
+
+	type summary struct {
+		Age   int
+		Total int `db:"total"`
+	}
+	var summaries []summary
+	require.NoError(t, qb.Select("age", "COUNT(*) AS total").GroupBy("age").Having("COUNT(*) > ?", 0).
+		OrderBy("age", "asc").Find(ctx, &summaries))
+	require.Equal(t, summary{Age: 21, Total: 1}, summaries[0])
+
+	require.ErrorContains(t, qb.Find(ctx, users), "destination must be a non-nil pointer")
+}
+
+func TestQueryBuilder_Joins(t *testing.T) {
+	conn, qb := setupQueryBuilder(t)
+	ctx := t.Context()
+	_, err := conn.Statement(ctx, "INSERT INTO posts (user_id, title) VALUES (1, 'a'), (1, 'b'), (2, 'c')")
+	require.NoError(t, err)
+
+	var users []testUser
+	require.NoError(t, qb.Join("posts", "posts.user_id = users.id").Where("posts.title = ?", "c").Find(ctx, &users))
+	require.Len(t, users, 1)
+	require.Equal(t, "User 2", users[0].Name)
+
+	count, err := qb.LeftJoin("posts", "posts.user_id = users.id").WhereNull("posts.id").Count(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count)
+
+	count, err = qb.InnerJoin("posts", "posts.user_id = users.id").Count(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), count)
+
+	var withPosts []testUser
+	require.NoError(t, qb.With("Posts").Where("id = ?", 1).Find(ctx, &withPosts))
+	require.Len(t, withPosts[0].Posts, 2)
+}
+
+func TestQueryBuilder_Mutations(t *testing.T) {
+	_, qb := setupQueryBuilder(t)
+	ctx := t.Context()
+
+	require.NoError(t, qb.Create(ctx, newUser(4)))
+	require.NoError(t, qb.Create(ctx, &[]testUser{*newUser(5), *newUser(6)}))
+	count, err := qb.Count(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(6), count)
+	require.ErrorContains(t, qb.Create(ctx, 1), "expected a model or a slice of models")
+
+	require.NoError(t, qb.Where("age > ?", 24).Update(ctx, map[string]any{"name": "Old"}))
+	count, err = qb.Where("name = ?", "Old").Count(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), count)
+	require.ErrorIs(t, qb.Update(ctx, map[string]any{"name": "All"}), ErrMissingWhereClause)
+
+	require.NoError(t, qb.Where("name = ?", "Old").Delete(ctx))
+	count, err = qb.Count(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(4), count)
+	count, err = qb.Unscoped().Count(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(6), count, "soft deleted rows remain")
+	count, err = qb.Unscoped().Scoped().Count(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(4), count)
+
+	require.NoError(t, qb.Unscoped().Where("name = ?", "Old").Delete(ctx))
+	count, err = qb.Unscoped().Count(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(4), count)
+	require.ErrorIs(t, qb.Delete(ctx), ErrMissingWhereClause)
+}
+
+func TestQueryBuilder_Raw(t *testing.T) {
+	_, qb := setupQueryBuilder(t)
+	ctx := t.Context()
+
+	var users []testUser
+	require.NoError(t, qb.Raw("SELECT * FROM users WHERE age IN ?", []int{21, 23}).Find(ctx, &users))
+	require.Len(t, users, 2)
+
+	count, err := qb.Raw("SELECT * FROM users WHERE age > ?", 21).Count(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), count)
+
+	require.NoError(t, qb.Exec(ctx, "UPDATE users SET age = ? WHERE name = ?", 50, "User 1"))
+	count, err = qb.Where("age = ?", 50).Count(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count)
+}
+
+func TestQueryBuilder_ToSQL(t *testing.T) {
+	_, qb := setupQueryBuilder(t)
+
+	sql, args, err := qb.Select("name").Where("age > ?", 21).OrderBy("name", "asc").Limit(5).ToSQL()
+	require.NoError(t, err)
+	require.Equal(t, `SELECT name FROM "users" WHERE (age > ?) AND "users"."deleted_at" IS NULL ORDER BY name ASC LIMIT 5`,
+		sql)
+	require.Equal(t, []any{21}, args)
+
+	base := qb.Where("age > ?", 21)
+	clone := base.Clone().Where("name = ?", "x")
+	baseSQL, _, err := base.ToSQL()
+	require.NoError(t, err)
+	cloneSQL, _, err := clone.ToSQL()
+	require.NoError(t, err)
+	require.NotEqual(t, baseSQL, cloneSQL, "clones are independent")
+
+	resetSQL, _, err := clone.Reset().ToSQL()
+	require.NoError(t, err)
+	require.Equal(t, `SELECT * FROM "users" WHERE "users"."deleted_at" IS NULL`, resetSQL)
+	require.Same(t, qb, qb.WithCount("Posts"))
+}
+
+func TestQueryBuilderFactory(t *testing.T) {
+	factory := &QueryBuilderFactory{}
+	require.Equal(t, AdapterName, factory.Name())
+	require.PanicsWithValue(t, "connection must be a contract.Connection opened by the sql adapter", func() {
+		factory.NewQueryBuilder(&testUser{}, "not a connection")
+	})
+}
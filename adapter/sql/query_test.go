@@ -0,0 +1,153 @@
+package sql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/next-trace/scg-database/adapter/internal/schema"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestQuery returns a query on the users table in dialect.
+func newTestQuery(t *testing.T, dialect string) *query {
+	t.Helper()
+	s, err := schema.Of(&testUser{})
+	require.NoError(t, err)
+	return newQuery(dialects[dialect], s)
+}
+
+func TestQuery_SelectSQL(t *testing.T) {
+	tests := []struct {
+		dialect string
+		sql     string
+	}{
+		{
+			DialectPostgres,
+			`SELECT * FROM "users" WHERE (age > $1) AND ("users"."id" IN ($2, $3)) AND ` +
+				`"users"."deleted_at" IS NULL ORDER BY name DESC LIMIT 10 OFFSET 5`,
+		},
+		{
+			DialectMySQL,
+			"SELECT * FROM `users` WHERE (age > ?) AND (`users`.`id` IN (?, ?)) AND " +
+				"`users`.`deleted_at` IS NULL ORDER BY name DESC LIMIT 10 OFFSET 5",
+		},
+		{
+			DialectSQLite,
+			`SELECT * FROM "users" WHERE (age > ?) AND ("users"."id" IN (?, ?)) AND ` +
+				`"users"."deleted_at" IS NULL ORDER BY name DESC LIMIT 10 OFFSET 5`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.dialect, func(t *testing.T) {
+			q := newTestQuery(t, tt.dialect).
+				where("age > ?", []any{18}, false).
+				where([]int{1, 2}, nil, false).
+				orderBy("name", "desc").
+				withLimit(10).
+				withOffset(5)
+			sql, args, err := q.selectSQL()
+			require.NoError(t, err)
+			require.Equal(t, tt.sql, sql)
+			require.Equal(t, []any{18, 1, 2}, args)
+		})
+	}
+}
+
+func TestQuery_Conditions(t *testing.T) {
+	q := newTestQuery(t, DialectPostgres)
+
+	sql, args, err := q.where("name = ?", []any{"a"}, false).where("name = ?", []any{"b"}, true).selectSQL()
+	require.NoError(t, err)
+	require.Equal(t, `SELECT * FROM "users" WHERE ((name = $1) OR (name = $2)) AND "users"."deleted_at" IS NULL`, sql,
+		"OR conditions are grouped before the soft delete scope")
+	require.Equal(t, []any{"a", "b"}, args)
+
+	sql, args, err = q.withUnscoped().where(map[string]any{"name": "a", "deleted_at": nil, "age": []int{}}, nil, false).
+		selectSQL()
+	require.NoError(t, err)
+	require.Equal(t, `SELECT * FROM "users" WHERE ("users"."age" IN (NULL) AND "users"."deleted_at" IS NULL AND `+
+		`"users"."name" = $1)`, sql)
+	require.Equal(t, []any{"a"}, args)
+
+	sql, args, err = q.where(&testUser{Name: "a", Age: 3}, nil, false).selectSQL()
+	require.NoError(t, err)
+	require.Equal(t, `SELECT * FROM "users" WHERE ("users"."name" = $1 AND "users"."age" = $2) AND `+
+		`"users"."deleted_at" IS NULL`, sql)
+	require.Equal(t, []any{"a", 3}, args)
+
+	sql, _, err = q.where(&testUser{}, nil, false).withUnscoped().selectSQL()
+	require.NoError(t, err)
+	require.Equal(t, `SELECT * FROM "users"`, sql, "a zero model adds no condition")
+
+	sql, args, err = q.where("note = '?' AND age IN ?", []any{[]int{1, 2}}, false).withUnscoped().selectSQL()
+	require.NoError(t, err)
+	require.Equal(t, `SELECT * FROM "users" WHERE (note = '?' AND age IN ($1, $2))`, sql,
+		"quoted question marks are not placeholders")
+	require.Equal(t, []any{1, 2}, args)
+}
+
+func TestQuery_Limit(t *testing.T) {
+	for dialect, want := range map[string]string{
+		DialectPostgres: `SELECT * FROM "users" OFFSET 5`,
+		DialectMySQL:    "SELECT * FROM `users` LIMIT 18446744073709551615 OFFSET 5",
+		DialectSQLite:   `SELECT * FROM "users" LIMIT -1 OFFSET 5`,
+	} {
+		sql, _, err := newTestQuery(t, dialect).withUnscoped().withOffset(5).selectSQL()
+		require.NoError(t, err)
+		require.Equal(t, want, sql, dialect)
+	}
+}
+
+func TestQuery_CountSQL(t *testing.T) {
+	q := newTestQuery(t, DialectPostgres).withUnscoped().where("age > ?", []any{1}, false)
+
+	sql, args, err := q.orderBy("name", "asc").withLimit(1).countSQL()
+	require.NoError(t, err)
+	require.Equal(t, `SELECT COUNT(*) FROM "users" WHERE (age > $1)`, sql)
+	require.Equal(t, []any{1}, args)
+
+	grouped := q.clone()
+	grouped.selects = []string{"age"}
+	grouped.groups = []string{"age"}
+	sql, _, err = grouped.countSQL()
+	require.NoError(t, err)
+	require.Equal(t, `SELECT COUNT(*) FROM (SELECT age FROM "users" WHERE (age > $1) GROUP BY age) counted`, sql)
+}
+
+func TestQuery_WriteSQL(t *testing.T) {
+	q := newTestQuery(t, DialectPostgres)
+	now := time.Now()
+
+	sql, args, err := q.where("id = ?", []any{1}, false).updateSQL([]assignment{{"name", "a"}, {"age", 2}})
+	require.NoError(t, err)
+	require.Equal(t, `UPDATE "users" SET "name" = $1, "age" = $2 WHERE (id = $3) AND "users"."deleted_at" IS NULL`, sql)
+	require.Equal(t, []any{"a", 2, 1}, args)
+
+	sql, args, err = q.where("id = ?", []any{1}, false).deleteSQL(now)
+	require.NoError(t, err)
+	require.Equal(t, `UPDATE "users" SET "deleted_at" = $1 WHERE (id = $2) AND "users"."deleted_at" IS NULL`, sql,
+		"soft deletes update deleted_at")
+	require.Equal(t, []any{now, 1}, args)
+
+	sql, _, err = q.withUnscoped().where("id = ?", []any{1}, false).deleteSQL(now)
+	require.NoError(t, err)
+	require.Equal(t, `DELETE FROM "users" WHERE (id = $1)`, sql)
+
+	_, _, err = q.updateSQL([]assignment{{"name", "a"}})
+	require.ErrorIs(t, err, ErrMissingWhereClause)
+	_, _, err = q.withUnscoped().deleteSQL(now)
+	require.ErrorIs(t, err, ErrMissingWhereClause)
+
+	sql, args = q.insertSQL([]string{"name", "age"}, [][]any{{"a", 1}, {"b", 2}}, true)
+	require.Equal(t, `INSERT INTO "users" ("name", "age") VALUES ($1, $2), ($3, $4) RETURNING "id"`, sql)
+	require.Equal(t, []any{"a", 1, "b", 2}, args)
+
+	sql, _ = newTestQuery(t, DialectMySQL).insertSQL([]string{"name"}, [][]any{{"a"}}, true)
+	require.Equal(t, "INSERT INTO `users` (`name`) VALUES (?)", sql, "MySQL reads keys from LastInsertId")
+}
+
+func TestDialect_QuoteIdent(t *testing.T) {
+	require.Equal(t, `"public"."users"`, dialects[DialectPostgres].quoteIdent("public.users"))
+	require.Equal(t, `"we""ird"`, dialects[DialectPostgres].quoteIdent(`we"ird`))
+	require.Equal(t, "`users`", dialects[DialectMySQL].quoteIdent("users"))
+}
@@ -0,0 +1,466 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/next-trace/scg-database/adapter/internal/schema"
+	"github.com/next-trace/scg-database/contract"
+	"github.com/next-trace/scg-database/db"
+)
+
+type (
+	repository struct {
+		conn *connection
+		mdl  contract.Model
+		q    *query
+	}
+)
+
+//nolint:grouper // Only One Global Variable
+var _ contract.Repository = (*repository)(nil)
+
+// with returns a repository of the same model using q.
+func (r *repository) with(q *query) contract.Repository {
+	return &repository{conn: r.conn, mdl: r.mdl, q: q}
+}
+
+// --- Query Building ---
+func (r *repository) With(relations ...string) contract.Repository {
+	return r.with(r.q.withPreload(relations...))
+}
+
+func (r *repository) Where(query any, args ...any) contract.Repository {
+	return r.with(r.q.where(query, args, false))
+}
+
+func (r *repository) Unscoped() contract.Repository {
+	return r.with(r.q.withUnscoped())
+}
+
+func (r *repository) Limit(limit int) contract.Repository {
+	return r.with(r.q.withLimit(limit))
+}
+
+func (r *repository) Offset(offset int) contract.Repository {
+	return r.with(r.q.withOffset(offset))
+}
+
+func (r *repository) OrderBy(column, direction string) contract.Repository {
+	return r.with(r.q.orderBy(column, direction))
+}
+
+// --- Read Operations ---
+func (r *repository) Find(ctx context.Context, id any) (contract.Model, error) {
+	row, err := r.conn.fetchFirst(ctx, r.q.where(id, nil, false))
+	return modelOf(row), err
+}
+
+func (r *repository) FindOrFail(ctx context.Context, id any) (contract.Model, error) {
+	return orFail(r.Find(ctx, id))
+}
+
+func (r *repository) First(ctx context.Context) (contract.Model, error) {
+	row, err := r.conn.fetchFirst(ctx, r.q)
+	return modelOf(row), err
+}
+
+func (r *repository) FirstOrFail(ctx context.Context) (contract.Model, error) {
+	return orFail(r.First(ctx))
+}
+
+func (r *repository) Get(ctx context.Context) ([]contract.Model, error) {
+	rows, err := r.conn.fetch(ctx, r.q)
+	if err != nil {
+		return nil, err
+	}
+	models := make([]contract.Model, len(rows))
+	for i, row := range rows {
+		models[i] = modelOf(row)
+	}
+	return models, nil
+}
+
+// Pluck reads the values of column into dest, a pointer to a slice.
+func (r *repository) Pluck(ctx context.Context, column string, dest any) error {
+	dv := reflect.ValueOf(dest)
+	if dv.Kind() != reflect.Pointer || dv.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("pluck destination must be a pointer to a slice, got %T", dest)
+	}
+	q := r.q.clone()
+	q.selects = []string{column}
+	return r.conn.load(ctx, q, dest, false)
+}
+
+// --- Write Operations ---
+
+// Create inserts models in one statement. Generated primary keys are read back
+// into the models, and the timestamps of models implementing contract.Timestamps
+// are set when zero.
+func (r *repository) Create(ctx context.Context, models ...contract.Model) error {
+	return r.CreateInBatches(ctx, models, max(len(models), 1))
+}
+
+func (r *repository) CreateInBatches(ctx context.Context, models []contract.Model, batchSize int) error {
+	if len(models) == 0 {
+		return nil
+	}
+	if batchSize <= 0 {
+		return errors.New("batch size must be positive")
+	}
+	for start := 0; start < len(models); start += batchSize {
+		if err := r.insert(ctx, models[start:min(start+batchSize, len(models))]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Update writes the non-zero fields of every model to its row, as GORM does,
+// and refreshes UpdatedAt of models implementing contract.Timestamps.
+func (r *repository) Update(ctx context.Context, models ...contract.Model) error {
+	s := r.q.schema
+	now := time.Now()
+	for _, model := range models {
+		v, err := r.structOf(model)
+		if err != nil {
+			return err
+		}
+		if s.Timestamps {
+			model.(contract.Timestamps).SetUpdatedAt(now) //nolint:forcetypeassert // checked by schemaOf
+		}
+
+		var assignments []assignment
+		for _, col := range s.Columns {
+			if col != s.PrimaryKey && !col.IsZero(v) {
+				assignments = append(assignments, assignment{column: col.Name, value: col.Value(v)})
+			}
+		}
+		if err := r.update(ctx, r.byKey(v), assignments); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Delete deletes models by primary key. Models implementing contract.SoftDelete
+// are marked deleted instead, and their DeletedAt is set.
+func (r *repository) Delete(ctx context.Context, models ...contract.Model) error {
+	return r.delete(ctx, r.q, models)
+}
+
+// ForceDelete deletes models by primary key, even models with soft deletes.
+func (r *repository) ForceDelete(ctx context.Context, models ...contract.Model) error {
+	return r.delete(ctx, r.q.withUnscoped(), models)
+}
+
+// --- Upsert Operations ---
+
+// FirstOrCreate reads the first row matching the non-zero fields of condition
+// into the model to create, which defaults to condition. Without a match, the
+// model is created with the fields of condition.
+func (r *repository) FirstOrCreate(
+	ctx context.Context,
+	condition contract.Model,
+	create ...contract.Model,
+) (contract.Model, error) {
+	toCreate := condition
+	if len(create) > 0 && create[0] != nil {
+		toCreate = create[0]
+	}
+	target, err := r.structOf(toCreate)
+	if err != nil {
+		return nil, err
+	}
+	cond, err := r.structOf(condition)
+	if err != nil {
+		return nil, err
+	}
+
+	found, err := r.conn.fetchFirst(ctx, r.q.where(condition, nil, false))
+	if err != nil {
+		return toCreate, err
+	}
+	if found.IsValid() {
+		target.Set(found.Elem())
+		return toCreate, nil
+	}
+
+	for _, col := range r.q.schema.Columns {
+		if !col.IsZero(cond) {
+			if err := col.Set(target, col.Value(cond)); err != nil {
+				return toCreate, err
+			}
+		}
+	}
+	return toCreate, r.insert(ctx, []contract.Model{toCreate})
+}
+
+// UpdateOrCreate reads the first row matching the non-zero fields of condition
+// into condition and updates it with values, a map of columns or a model whose
+// non-zero fields are used. Without a match, condition is created with values.
+func (r *repository) UpdateOrCreate(ctx context.Context, condition contract.Model, values any) (contract.Model, error) {
+	target, err := r.structOf(condition)
+	if err != nil {
+		return nil, err
+	}
+	assignments, err := r.assignments(values)
+	if err != nil {
+		return condition, err
+	}
+
+	found, err := r.conn.fetchFirst(ctx, r.q.where(condition, nil, false))
+	if err != nil {
+		return condition, err
+	}
+	if found.IsValid() {
+		target.Set(found.Elem())
+	}
+	for _, a := range assignments {
+		if err := r.q.schema.ByName[a.column].Set(target, a.value); err != nil {
+			return condition, err
+		}
+	}
+	if !found.IsValid() {
+		return condition, r.insert(ctx, []contract.Model{condition})
+	}
+
+	if r.q.schema.Timestamps && len(assignments) > 0 {
+		now := time.Now()
+		condition.(contract.Timestamps).SetUpdatedAt(now) //nolint:forcetypeassert // checked by schemaOf
+		assignments = append(assignments, assignment{column: schema.UpdatedAtColumn, value: now})
+	}
+	return condition, r.update(ctx, r.byKey(target), assignments)
+}
+
+// QueryBuilder provides access to the fluent query builder interface. The
+// builder starts from the conditions of the repository.
+func (r *repository) QueryBuilder() contract.QueryBuilder {
+	return &queryBuilder{conn: r.conn, model: r.mdl, q: r.q}
+}
+
+// --- Helper Functions ---
+
+// insert inserts models, which must be of the repository model type. Models
+// with a primary key and models whose key is generated by the database are
+// inserted by separate statements.
+func (r *repository) insert(ctx context.Context, models []contract.Model) error {
+	s := r.q.schema
+	now := time.Now()
+	var keyed, generated []reflect.Value
+	for _, model := range models {
+		v, err := r.structOf(model)
+		if err != nil {
+			return err
+		}
+		if s.Timestamps {
+			ts := model.(contract.Timestamps) //nolint:forcetypeassert // checked by schemaOf
+			if ts.GetCreatedAt().IsZero() {
+				ts.SetCreatedAt(now)
+			}
+			if ts.GetUpdatedAt().IsZero() {
+				ts.SetUpdatedAt(now)
+			}
+		}
+		if s.PrimaryKey.IsZero(v) {
+			generated = append(generated, v)
+		} else {
+			keyed = append(keyed, v)
+		}
+	}
+
+	if len(keyed) > 0 {
+		query, args := r.q.insertSQL(s.ColumnNames(true), rowValues(s, keyed, true), false)
+		if _, err := r.conn.exec(ctx, "create", query, args); err != nil {
+			return err
+		}
+	}
+	if len(generated) > 0 {
+		return r.insertGenerated(ctx, generated)
+	}
+	return nil
+}
+
+// insertGenerated inserts rows without a primary key and reads the generated
+// keys back, with RETURNING or, on MySQL, from the first inserted id.
+func (r *repository) insertGenerated(ctx context.Context, rows []reflect.Value) error {
+	s := r.q.schema
+	query, args := r.q.insertSQL(s.ColumnNames(false), rowValues(s, rows, false), true)
+
+	if r.q.dialect.returning {
+		return r.conn.query(ctx, "create", query, args, func(result *sql.Rows) (int64, error) {
+			var n int64
+			for ; result.Next(); n++ {
+				var id any
+				if err := result.Scan(&id); err != nil {
+					return n, err //nolint:wrapcheck // translated by the caller
+				}
+				if int(n) < len(rows) {
+					if err := s.PrimaryKey.Set(rows[n], id); err != nil {
+						return n, err
+					}
+				}
+			}
+			return n, result.Err() //nolint:wrapcheck // translated by the caller
+		})
+	}
+
+	result, err := r.conn.exec(ctx, "create", query, args)
+	if err != nil {
+		return err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil //nolint:nilerr // keys that are not auto-increment cannot be read back
+	}
+	for i, row := range rows {
+		if err := s.PrimaryKey.Set(row, id+int64(i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// update sets the assigned columns of the rows matched by q.
+func (r *repository) update(ctx context.Context, q *query, assignments []assignment) error {
+	if len(assignments) == 0 {
+		return nil
+	}
+	query, args, err := q.updateSQL(assignments)
+	if err != nil {
+		return err
+	}
+	_, err = r.conn.exec(ctx, "update", query, args)
+	return err
+}
+
+// delete deletes the rows of models, matched by primary key within q.
+func (r *repository) delete(ctx context.Context, q *query, models []contract.Model) error {
+	if len(models) == 0 {
+		return nil
+	}
+	s := r.q.schema
+	var ids []any
+	for _, model := range models {
+		v, err := r.structOf(model)
+		if err != nil {
+			return err
+		}
+		if !s.PrimaryKey.IsZero(v) {
+			ids = append(ids, s.PrimaryKey.Value(v))
+		}
+	}
+	if len(ids) > 0 {
+		q = q.where(q.column(s.PrimaryKey.Name)+" IN ?", []any{ids}, false)
+	}
+
+	now := time.Now()
+	query, args, err := q.deleteSQL(now)
+	if err != nil {
+		return err
+	}
+	if _, err := r.conn.exec(ctx, "delete", query, args); err != nil {
+		return err
+	}
+	if q.scoped() {
+		for _, model := range models {
+			model.(contract.SoftDelete).SetDeletedAt(&now) //nolint:forcetypeassert // checked by schemaOf
+		}
+	}
+	return nil
+}
+
+// byKey returns the query of the repository restricted to the row of v.
+func (r *repository) byKey(v reflect.Value) *query {
+	pk := r.q.schema.PrimaryKey
+	return r.q.where(r.q.column(pk.Name)+" = ?", []any{pk.Value(v)}, false)
+}
+
+// assignments returns the columns to update from values: a map of column or
+// field names, or a model or struct whose non-zero fields are used.
+func (r *repository) assignments(values any) ([]assignment, error) {
+	s := r.q.schema
+	if m, ok := values.(map[string]any); ok {
+		assignments := make([]assignment, 0, len(m))
+		for _, key := range sortedKeys(m) {
+			col, ok := s.Lookup(key)
+			if !ok {
+				return nil, fmt.Errorf("unknown column %q of %s", key, s.Table)
+			}
+			assignments = append(assignments, assignment{column: col.Name, value: m[key]})
+		}
+		return assignments, nil
+	}
+
+	v := reflect.ValueOf(values)
+	if v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("unsupported update values %T: expected a map or a struct", values)
+	}
+	vs, err := schema.OfStruct(v.Type())
+	if err != nil {
+		return nil, err
+	}
+	var assignments []assignment
+	for _, col := range vs.Columns {
+		if col.IsZero(v) || (vs.PrimaryKey != nil && col == vs.PrimaryKey) || s.ByName[col.Name] == nil {
+			continue
+		}
+		assignments = append(assignments, assignment{column: col.Name, value: col.Value(v)})
+	}
+	return assignments, nil
+}
+
+// structOf returns the struct model points to, which must be of the
+// repository model type.
+func (r *repository) structOf(model contract.Model) (reflect.Value, error) {
+	if model == nil {
+		return reflect.Value{}, errors.New("model cannot be nil")
+	}
+	v := reflect.ValueOf(model)
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return reflect.Value{}, errors.New("model cannot be nil")
+	}
+	if v.Elem().Type() != r.q.schema.Type {
+		return reflect.Value{}, fmt.Errorf("model %T does not belong to a repository of %s", model, r.q.schema.Type)
+	}
+	return v.Elem(), nil
+}
+
+// rowValues returns the column values of rows for an INSERT.
+func rowValues(s *schema.Schema, rows []reflect.Value, withPrimaryKey bool) [][]any {
+	values := make([][]any, len(rows))
+	for i, row := range rows {
+		for _, col := range s.Columns {
+			if col != s.PrimaryKey || withPrimaryKey {
+				values[i] = append(values[i], col.Value(row))
+			}
+		}
+	}
+	return values
+}
+
+// modelOf returns the model row points to, or nil for an invalid row.
+func modelOf(row reflect.Value) contract.Model {
+	if !row.IsValid() {
+		return nil
+	}
+	return row.Interface().(contract.Model) //nolint:forcetypeassert // rows are created from model types
+}
+
+// orFail turns a missing model into db.ErrRecordNotFound.
+func orFail(model contract.Model, err error) (contract.Model, error) {
+	if err != nil {
+		return nil, err
+	}
+	if model == nil {
+		return nil, db.ErrRecordNotFound
+	}
+	return model, nil
+}
@@ -0,0 +1,442 @@
+package sql
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/next-trace/scg-database/config"
+	"github.com/next-trace/scg-database/contract"
+	"github.com/next-trace/scg-database/db"
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	// Test Models
+	testUser struct {
+		ID        uint
+		Name      string
+		Email     string
+		Age       int
+		CreatedAt time.Time
+		UpdatedAt time.Time
+		DeletedAt *time.Time
+		Posts     []*testPost
+		Profile   *testProfile
+		Roles     []testRole
+	}
+
+	testPost struct {
+		ID     uint
+		UserID uint
+		Title  string
+		Author *testUser
+	}
+
+	testProfile struct {
+		ID     uint
+		UserID uint
+		Bio    string
+	}
+
+	testRole struct {
+		ID   uint
+		Name string
+	}
+)
+
+func (m *testUser) PrimaryKey() string        { return "id" }
+func (m *testUser) TableName() string         { return "users" }
+func (m *testUser) GetID() any                { return m.ID }
+func (m *testUser) SetID(id any)              { m.ID = id.(uint) }
+func (m *testUser) GetDeletedAt() *time.Time  { return m.DeletedAt }
+func (m *testUser) SetDeletedAt(t *time.Time) { m.DeletedAt = t }
+func (m *testUser) GetCreatedAt() time.Time   { return m.CreatedAt }
+func (m *testUser) GetUpdatedAt() time.Time   { return m.UpdatedAt }
+func (m *testUser) SetCreatedAt(t time.Time)  { m.CreatedAt = t }
+func (m *testUser) SetUpdatedAt(t time.Time)  { m.UpdatedAt = t }
+func (m *testUser) Relationships() map[string]contract.Relationship {
+	return map[string]contract.Relationship{
+		"Posts":   contract.NewHasMany(&testPost{}, "user_id", "id"),
+		"Profile": contract.NewHasOne(&testProfile{}, "user_id", "id"),
+		"Roles":   contract.NewBelongsToMany(&testRole{}, "user_roles"),
+	}
+}
+
+func (m *testPost) PrimaryKey() string { return "id" }
+func (m *testPost) TableName() string  { return "posts" }
+func (m *testPost) GetID() any         { return m.ID }
+func (m *testPost) SetID(id any)       { m.ID = id.(uint) }
+func (m *testPost) Relationships() map[string]contract.Relationship {
+	return map[string]contract.Relationship{"Author": contract.NewBelongsTo(&testUser{}, "user_id", "id")}
+}
+
+func (m *testProfile) PrimaryKey() string                              { return "id" }
+func (m *testProfile) TableName() string                               { return "profiles" }
+func (m *testProfile) GetID() any                                      { return m.ID }
+func (m *testProfile) SetID(id any)                                    { m.ID = id.(uint) }
+func (m *testProfile) Relationships() map[string]contract.Relationship { return nil }
+
+func (m *testRole) PrimaryKey() string                              { return "id" }
+func (m *testRole) TableName() string                               { return "" } // derived: test_roles
+func (m *testRole) GetID() any                                      { return m.ID }
+func (m *testRole) SetID(id any)                                    { m.ID = id.(uint) }
+func (m *testRole) Relationships() map[string]contract.Relationship { return nil }
+
+//nolint:grouper // Only One Global Variable
+var testSchema = []string{
+	`CREATE TABLE users (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL DEFAULT '',
+		email TEXT NOT NULL UNIQUE,
+		age INTEGER NOT NULL DEFAULT 0,
+		created_at DATETIME,
+		updated_at DATETIME,
+		deleted_at DATETIME
+	)`,
+	`CREATE TABLE posts (id INTEGER PRIMARY KEY AUTOINCREMENT, user_id INTEGER NOT NULL, title TEXT NOT NULL)`,
+	`CREATE TABLE profiles (id INTEGER PRIMARY KEY AUTOINCREMENT, user_id INTEGER NOT NULL, bio TEXT NOT NULL)`,
+	`CREATE TABLE test_roles (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT NOT NULL)`,
+	`CREATE TABLE user_roles (test_user_id INTEGER NOT NULL, test_role_id INTEGER NOT NULL)`,
+}
+
+// setupConnection connects to a new SQLite database of driver with the test schema.
+func setupConnection(t *testing.T, driver string, opts ...config.Option) contract.Connection {
+	t.Helper()
+	Register()
+	cfg := &config.Config{Driver: driver, DSN: filepath.Join(t.TempDir(), "test.db")}
+	conn, err := db.Connect(cfg, opts...)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	for _, stmt := range testSchema {
+		_, err := conn.Statement(t.Context(), stmt)
+		require.NoError(t, err)
+	}
+	return conn
+}
+
+// setupTest returns a repository of testUser on a new SQLite database.
+func setupTest(t *testing.T) contract.Repository {
+	t.Helper()
+	repo, err := setupConnection(t, testSQLiteDriver).NewRepository(&testUser{})
+	require.NoError(t, err)
+	return repo
+}
+
+func newUser(i int) *testUser {
+	return &testUser{Name: fmt.Sprintf("User %d", i), Email: fmt.Sprintf("user%d@example.com", i), Age: 20 + i}
+}
+
+// --- Test Cases ---
+func TestRepository_Create(t *testing.T) {
+	repo := setupTest(t)
+	user := newUser(1)
+	require.NoError(t, repo.Create(t.Context(), user))
+	require.NotZero(t, user.ID, "the generated key is read back")
+	require.False(t, user.CreatedAt.IsZero())
+	require.Equal(t, user.CreatedAt, user.UpdatedAt)
+
+	// Explicit keys are kept.
+	require.NoError(t, repo.Create(t.Context(), &testUser{ID: 42, Name: "Keyed", Email: "keyed@example.com"}))
+	found, err := repo.FindOrFail(t.Context(), 42)
+	require.NoError(t, err)
+	require.Equal(t, "Keyed", found.(*testUser).Name)
+}
+
+func TestRepository_CreateMultiple(t *testing.T) {
+	repo := setupTest(t)
+	users := []contract.Model{newUser(1), newUser(2), &testUser{ID: 10, Name: "Ten", Email: "ten@example.com"}}
+	require.NoError(t, repo.Create(t.Context(), users...))
+	// Models with a key are inserted first, then the others read their generated keys.
+	require.Equal(t, uint(11), users[0].(*testUser).ID)
+	require.Equal(t, uint(12), users[1].(*testUser).ID)
+
+	all, err := repo.Get(t.Context())
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+}
+
+func TestRepository_CreateInBatches(t *testing.T) {
+	repo := setupTest(t)
+	models := make([]contract.Model, 0, 5)
+	for i := range 5 {
+		models = append(models, newUser(i))
+	}
+	require.NoError(t, repo.CreateInBatches(t.Context(), models, 2))
+
+	all, err := repo.Get(t.Context())
+	require.NoError(t, err)
+	require.Len(t, all, 5)
+	for i, model := range models {
+		require.Equal(t, uint(i+1), model.(*testUser).ID)
+	}
+
+	require.EqualError(t, repo.CreateInBatches(t.Context(), models, 0), "batch size must be positive")
+	require.NoError(t, repo.CreateInBatches(t.Context(), nil, 2))
+}
+
+func TestRepository_Create_Errors(t *testing.T) {
+	repo := setupTest(t)
+	require.EqualError(t, repo.Create(t.Context(), nil), "model cannot be nil")
+	require.NoError(t, repo.Create(t.Context()))
+	require.ErrorContains(t, repo.Create(t.Context(), &testPost{}), "does not belong to a repository of")
+
+	require.NoError(t, repo.Create(t.Context(), newUser(1)))
+	err := repo.Create(t.Context(), newUser(1))
+	require.ErrorIs(t, err, db.ErrUniqueViolation)
+}
+
+func TestRepository_Find(t *testing.T) {
+	repo := setupTest(t)
+	user := newUser(1)
+	require.NoError(t, repo.Create(t.Context(), user))
+
+	found, err := repo.Find(t.Context(), user.ID)
+	require.NoError(t, err)
+	require.Equal(t, user.Name, found.(*testUser).Name)
+	require.WithinDuration(t, user.CreatedAt, found.(*testUser).CreatedAt, time.Second)
+
+	missing, err := repo.Find(t.Context(), 999)
+	require.NoError(t, err)
+	require.Nil(t, missing)
+
+	_, err = repo.FindOrFail(t.Context(), 999)
+	require.ErrorIs(t, err, db.ErrRecordNotFound)
+}
+
+func TestRepository_First(t *testing.T) {
+	repo := setupTest(t)
+	first, err := repo.First(t.Context())
+	require.NoError(t, err)
+	require.Nil(t, first)
+	_, err = repo.FirstOrFail(t.Context())
+	require.ErrorIs(t, err, db.ErrRecordNotFound)
+
+	require.NoError(t, repo.Create(t.Context(), newUser(2), newUser(1)))
+	first, err = repo.FirstOrFail(t.Context())
+	require.NoError(t, err)
+	require.Equal(t, "User 2", first.(*testUser).Name, "First orders by primary key")
+}
+
+func TestRepository_Where(t *testing.T) {
+	repo := setupTest(t)
+	require.NoError(t, repo.Create(t.Context(), newUser(1), newUser(2), newUser(3)))
+
+	tests := []struct {
+		name  string
+		repo  contract.Repository
+		names []string
+	}{
+		{"sql", repo.Where("age > ?", 21), []string{"User 2", "User 3"}},
+		{"slice", repo.Where("name IN ?", []string{"User 1", "User 3"}), []string{"User 1", "User 3"}},
+		{"map", repo.Where(map[string]any{"name": "User 2", "age": 22}), []string{"User 2"}},
+		{"model", repo.Where(&testUser{Age: 23}), []string{"User 3"}},
+		{"primary key", repo.Where(uint(1)), []string{"User 1"}},
+		{"primary keys", repo.Where([]uint{1, 2}), []string{"User 1", "User 2"}},
+		{"chained", repo.Where("age > ?", 21).Where("name = ?", "User 3"), []string{"User 3"}},
+		{"null", repo.Where(map[string]any{"deleted_at": nil}), []string{"User 1", "User 2", "User 3"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := tt.repo.Get(t.Context())
+			require.NoError(t, err)
+			names := make([]string, 0, len(results))
+			for _, result := range results {
+				names = append(names, result.(*testUser).Name)
+			}
+			require.Equal(t, tt.names, names)
+		})
+	}
+
+	_, err := repo.Where(nil).Get(t.Context())
+	require.EqualError(t, err, "where condition cannot be nil")
+}
+
+func TestRepository_OrderByLimitOffset(t *testing.T) {
+	repo := setupTest(t)
+	for i := range 5 {
+		require.NoError(t, repo.Create(t.Context(), newUser(i)))
+	}
+
+	results, err := repo.OrderBy("age", "desc").Limit(2).Offset(1).Get(t.Context())
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	require.Equal(t, "User 3", results[0].(*testUser).Name)
+	require.Equal(t, "User 2", results[1].(*testUser).Name)
+
+	// OFFSET without LIMIT, and an invalid direction falling back to ASC.
+	results, err = repo.OrderBy("age", "sideways").Offset(3).Get(t.Context())
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	require.Equal(t, "User 3", results[0].(*testUser).Name)
+
+	// Negative values are ignored.
+	results, err = repo.Limit(-1).Offset(-1).Get(t.Context())
+	require.NoError(t, err)
+	require.Len(t, results, 5)
+}
+
+func TestRepository_Pluck(t *testing.T) {
+	repo := setupTest(t)
+	require.NoError(t, repo.Create(t.Context(), newUser(1), newUser(2)))
+
+	var names []string
+	require.NoError(t, repo.OrderBy("id", "desc").Pluck(t.Context(), "name", &names))
+	require.Equal(t, []string{"User 2", "User 1"}, names)
+
+	var names2 string
+	require.ErrorContains(t, repo.Pluck(t.Context(), "name", &names2), "pointer to a slice")
+}
+
+func TestRepository_Update(t *testing.T) {
+	repo := setupTest(t)
+	user := newUser(1)
+	require.NoError(t, repo.Create(t.Context(), user))
+	created := user.UpdatedAt
+
+	time.Sleep(10 * time.Millisecond)
+	user.Name = "Updated"
+	user.Age = 0 // zero fields are not written, as in GORM
+	require.NoError(t, repo.Update(t.Context(), user))
+	require.True(t, user.UpdatedAt.After(created))
+
+	found, err := repo.FindOrFail(t.Context(), user.ID)
+	require.NoError(t, err)
+	require.Equal(t, "Updated", found.(*testUser).Name)
+	require.Equal(t, 21, found.(*testUser).Age)
+
+	require.EqualError(t, repo.Update(t.Context(), nil), "model cannot be nil")
+	require.NoError(t, repo.Update(t.Context()))
+}
+
+func TestRepository_Delete(t *testing.T) {
+	repo := setupTest(t)
+	first, second := newUser(1), newUser(2)
+	require.NoError(t, repo.Create(t.Context(), first, second))
+
+	require.NoError(t, repo.Delete(t.Context(), first))
+	require.NotNil(t, first.DeletedAt, "soft deletes set DeletedAt")
+
+	found, err := repo.Find(t.Context(), first.ID)
+	require.NoError(t, err)
+	require.Nil(t, found, "soft deleted rows are hidden")
+
+	trashed, err := repo.Unscoped().Find(t.Context(), first.ID)
+	require.NoError(t, err)
+	require.NotNil(t, trashed.(*testUser).DeletedAt)
+
+	require.NoError(t, repo.ForceDelete(t.Context(), first, second))
+	all, err := repo.Unscoped().Get(t.Context())
+	require.NoError(t, err)
+	require.Empty(t, all)
+
+	require.EqualError(t, repo.Delete(t.Context(), nil), "model cannot be nil")
+	require.ErrorIs(t, repo.Delete(t.Context(), &testUser{}), ErrMissingWhereClause)
+	require.NoError(t, repo.Delete(t.Context()))
+}
+
+func TestRepository_Delete_HardDelete(t *testing.T) {
+	conn := setupConnection(t, testSQLiteDriver)
+	repo, err := conn.NewRepository(&testPost{})
+	require.NoError(t, err)
+	post := &testPost{UserID: 1, Title: "Hello"}
+	require.NoError(t, repo.Create(t.Context(), post))
+
+	require.NoError(t, repo.Delete(t.Context(), post))
+	all, err := repo.Unscoped().Get(t.Context())
+	require.NoError(t, err)
+	require.Empty(t, all, "models without soft deletes are deleted")
+}
+
+func TestRepository_FirstOrCreate(t *testing.T) {
+	repo := setupTest(t)
+
+	created, err := repo.FirstOrCreate(t.Context(), &testUser{Email: "new@example.com"},
+		&testUser{Name: "New", Age: 30})
+	require.NoError(t, err)
+	require.NotZero(t, created.(*testUser).ID)
+	require.Equal(t, "new@example.com", created.(*testUser).Email, "condition fields are created")
+	require.Equal(t, "New", created.(*testUser).Name)
+
+	found, err := repo.FirstOrCreate(t.Context(), &testUser{Email: "new@example.com"})
+	require.NoError(t, err)
+	require.Equal(t, created.(*testUser).ID, found.(*testUser).ID)
+	require.Equal(t, 30, found.(*testUser).Age)
+}
+
+func TestRepository_UpdateOrCreate(t *testing.T) {
+	repo := setupTest(t)
+
+	created, err := repo.UpdateOrCreate(t.Context(), &testUser{Email: "a@example.com"}, map[string]any{"name": "A"})
+	require.NoError(t, err)
+	require.NotZero(t, created.(*testUser).ID)
+	require.Equal(t, "A", created.(*testUser).Name)
+
+	updated, err := repo.UpdateOrCreate(t.Context(), &testUser{Email: "a@example.com"}, &testUser{Name: "B", Age: 40})
+	require.NoError(t, err)
+	require.Equal(t, created.(*testUser).ID, updated.(*testUser).ID)
+
+	found, err := repo.FindOrFail(t.Context(), created.(*testUser).ID)
+	require.NoError(t, err)
+	require.Equal(t, "B", found.(*testUser).Name)
+	require.Equal(t, 40, found.(*testUser).Age)
+
+	_, err = repo.UpdateOrCreate(t.Context(), &testUser{Email: "a@example.com"}, map[string]any{"nope": 1})
+	require.EqualError(t, err, `unknown column "nope" of users`)
+	_, err = repo.UpdateOrCreate(t.Context(), &testUser{Email: "a@example.com"}, 1)
+	require.ErrorContains(t, err, "expected a map or a struct")
+}
+
+func TestRepository_With(t *testing.T) {
+	conn := setupConnection(t, testSQLiteDriver)
+	ctx := t.Context()
+	users, err := conn.NewRepository(&testUser{})
+	require.NoError(t, err)
+	alice, bob := newUser(1), newUser(2)
+	require.NoError(t, users.Create(ctx, alice, bob))
+
+	posts, err := conn.NewRepository(&testPost{})
+	require.NoError(t, err)
+	require.NoError(t, posts.Create(ctx,
+		&testPost{UserID: alice.ID, Title: "A1"},
+		&testPost{UserID: alice.ID, Title: "A2"},
+		&testPost{UserID: bob.ID, Title: "B1"},
+	))
+	_, err = conn.Statement(ctx, "INSERT INTO profiles (user_id, bio) VALUES (?, ?)", bob.ID, "Bio")
+	require.NoError(t, err)
+	_, err = conn.Statement(ctx, "INSERT INTO test_roles (name) VALUES ('admin'), ('editor')")
+	require.NoError(t, err)
+	_, err = conn.Statement(ctx, "INSERT INTO user_roles (test_user_id, test_role_id) VALUES (1, 1), (1, 2), (2, 2)")
+	require.NoError(t, err)
+
+	results, err := users.With("Posts", "Profile", "Roles").OrderBy("id", "asc").Get(ctx)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	a, b := results[0].(*testUser), results[1].(*testUser)
+	require.Len(t, a.Posts, 2)
+	require.Equal(t, "A1", a.Posts[0].Title)
+	require.Nil(t, a.Profile)
+	require.Equal(t, []testRole{{ID: 1, Name: "admin"}, {ID: 2, Name: "editor"}}, a.Roles)
+	require.Len(t, b.Posts, 1)
+	require.Equal(t, "Bio", b.Profile.Bio)
+	require.Equal(t, []testRole{{ID: 2, Name: "editor"}}, b.Roles)
+
+	post, err := posts.With("Author").FindOrFail(ctx, 3)
+	require.NoError(t, err)
+	require.Equal(t, "User 2", post.(*testPost).Author.Name)
+
+	_, err = users.With("Unknown").Get(ctx)
+	require.ErrorContains(t, err, `unknown relationship "Unknown"`)
+}
+
+func TestRepository_QueryBuilder(t *testing.T) {
+	repo := setupTest(t)
+	require.NoError(t, repo.Create(t.Context(), newUser(1), newUser(2)))
+
+	count, err := repo.Where("age > ?", 21).QueryBuilder().Count(t.Context())
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count, "the builder keeps the repository conditions")
+}
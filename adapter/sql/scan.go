@@ -0,0 +1,69 @@
+package sql
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+
+	"github.com/next-trace/scg-database/adapter/internal/schema"
+)
+
+// scanRows reads every row into a new struct and returns the pointers to them.
+func scanRows(s *schema.Schema, rows *sql.Rows) ([]reflect.Value, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read result columns: %w", err)
+	}
+	var result []reflect.Value
+	for rows.Next() {
+		row := reflect.New(s.Type)
+		targets, assign := scanTargets(s, row.Elem(), columns)
+		if err := rows.Scan(targets...); err != nil {
+			return nil, err //nolint:wrapcheck // translated by the caller
+		}
+		assign()
+		result = append(result, row)
+	}
+	return result, rows.Err() //nolint:wrapcheck // translated by the caller
+}
+
+// scanTargets returns the destinations of a row with the given columns into
+// the struct v, and a function that copies nullable values into their fields
+// once the row is scanned. Unknown columns are discarded.
+func scanTargets(s *schema.Schema, v reflect.Value, columns []string) ([]any, func()) {
+	targets := make([]any, len(columns))
+	var fixups []func()
+	for i, name := range columns {
+		col, ok := s.ByName[name]
+		if !ok {
+			targets[i] = new(any)
+			continue
+		}
+		f, ok := col.Field(v)
+		if !ok {
+			targets[i] = new(any)
+			continue
+		}
+
+		// Scanners, pointers and interfaces handle NULL themselves; other fields
+		// are scanned through a pointer so that NULL becomes the zero value.
+		if f.Kind() == reflect.Pointer || f.Kind() == reflect.Interface || schema.IsScanner(f.Type()) {
+			targets[i] = f.Addr().Interface()
+			continue
+		}
+		holder := reflect.New(reflect.PointerTo(f.Type()))
+		targets[i] = holder.Interface()
+		fixups = append(fixups, func() {
+			if holder.Elem().IsNil() {
+				f.SetZero()
+			} else {
+				f.Set(holder.Elem().Elem())
+			}
+		})
+	}
+	return targets, func() {
+		for _, fixup := range fixups {
+			fixup()
+		}
+	}
+}
@@ -0,0 +1,10 @@
+//go:build cgo
+
+package sql
+
+// testSQLiteDriver is the SQLite dialect of the tests that need a database;
+// testSQLiteDrivers are the dialects the conformance tests run against.
+const testSQLiteDriver = DriverSQLite
+
+//nolint:grouper // Only One Global Variable
+var testSQLiteDrivers = []string{DriverSQLite, DriverSQLitePure}
@@ -0,0 +1,11 @@
+//go:build !cgo
+
+package sql
+
+// testSQLiteDriver is the SQLite dialect of the tests that need a database;
+// testSQLiteDrivers are the dialects the conformance tests run against.
+// go-sqlite3 needs cgo, so only the pure-Go dialect is available.
+const testSQLiteDriver = DriverSQLitePure
+
+//nolint:grouper // Only One Global Variable
+var testSQLiteDrivers = []string{DriverSQLitePure}
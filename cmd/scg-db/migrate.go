@@ -64,6 +64,9 @@ func secureCreateFile(basePath, targetPath string) (*os.File, error) {
 }
 
 var (
+	// connectionName selects an entry of database.connections; empty means database.default.
+	connectionName string
+
 	migrateCmd = &cobra.Command{
 		Use:   "migrate",
 		Short: "Commands to manage database migrations",
@@ -132,14 +135,38 @@ var (
 )
 
 func init() {
+	migrateCmd.PersistentFlags().StringVar(&connectionName, "connection", "",
+		"named connection from database.connections (default is database.default)")
 	migrateCmd.AddCommand(migrateUpCmd, migrateDownCmd, migrateFreshCmd, migrateCreateCmd)
 }
 
+// connectionConfig builds the config for a named entry of database.connections
+// by reading the config file with config.Load. The file is read again rather
+// than taken from viper, which lowercases every key and would lose connection
+// names such as "Primary". Only that entry is interpolated and validated; the
+// others may reference variables that are not set where the CLI runs.
+// The entry's driver defaults to its name, so `gorm:sqlite: {dsn: app.db}` keeps working.
+func connectionConfig(name string) config.Config {
+	database, err := config.Load(viper.ConfigFileUsed(), config.WithConnection(name))
+	if err == nil {
+		var cfg *config.Config
+		if cfg, err = database.Connection(name); err == nil {
+			return *cfg
+		}
+	}
+	fmt.Printf("Error: invalid database config: %v\n", err)
+	os.Exit(1)
+	return config.Config{}
+}
+
 func runMigrationCommand(direction string) func(*cobra.Command, []string) {
 	return func(cmd *cobra.Command, args []string) {
 		_ = cmd
-		defaultConnection := viper.GetString("database.default")
-		if defaultConnection == "" {
+		name := connectionName
+		if name == "" {
+			name = viper.GetString("database.default")
+		}
+		if name == "" {
 			fmt.Println("Error: 'database.default' connection not set in config.")
 			os.Exit(1)
 		}
@@ -160,11 +187,8 @@ func runMigrationCommand(direction string) func(*cobra.Command, []string) {
 			migrationsPath = "file://" + absPath
 		}
 
-		cfg := config.Config{
-			Driver:         defaultConnection,
-			DSN:            viper.GetString(fmt.Sprintf("database.connections.%s.dsn", defaultConnection)),
-			MigrationsPath: migrationsPath,
-		}
+		cfg := connectionConfig(name)
+		cfg.MigrationsPath = migrationsPath
 
 		migrator, err := migration.NewMigrator(&cfg)
 		if err != nil {
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -134,3 +136,60 @@ func TestMigrationCommandConfigValidation(t *testing.T) {
 		})
 	}
 }
+
+// useConfigFile points viper at a config.yaml holding content, as the CLI does.
+func useConfigFile(t *testing.T, content string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	viper.SetConfigFile(path)
+	require.NoError(t, viper.ReadInConfig())
+}
+
+func TestConnectionConfig(t *testing.T) {
+	useConfigFile(t, `
+database:
+  connections:
+    gorm:sqlite:
+      dsn: app.db
+    analytics:
+      driver: gorm:postgres
+      dsn: host=localhost dbname=analytics
+    reporting:
+      driver: gorm:mysql
+      dsn: ${TEST_SURELY_UNSET}
+`)
+
+	cfg := connectionConfig("gorm:sqlite")
+	require.Equal(t, "gorm:sqlite", cfg.Driver, "driver should default to the connection name")
+	require.Equal(t, "app.db", cfg.DSN)
+
+	cfg = connectionConfig("analytics")
+	require.Equal(t, "gorm:postgres", cfg.Driver)
+	require.Equal(t, "host=localhost dbname=analytics", cfg.DSN,
+		"other connections should not be interpolated")
+}
+
+func TestConnectionConfig_MixedCaseName(t *testing.T) {
+	useConfigFile(t, `
+database:
+  default: Primary
+  connections:
+    Primary:
+      driver: gorm:sqlite
+      dsn: app.db
+`)
+
+	cfg := connectionConfig(viper.GetString("database.default"))
+	require.Equal(t, "gorm:sqlite", cfg.Driver)
+	require.Equal(t, "app.db", cfg.DSN)
+}
+
+func TestMigrateCommand_ConnectionFlag(t *testing.T) {
+	flag := migrateCmd.PersistentFlags().Lookup("connection")
+	require.NotNil(t, flag)
+	require.Empty(t, flag.DefValue)
+}
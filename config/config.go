@@ -2,9 +2,22 @@ package config
 
 import (
 	"errors"
+	"fmt"
+	"maps"
+	"slices"
 	"time"
 )
 
+// Replica routing policies
+const (
+	// ReplicaPolicyRoundRobin cycles through replicas in order.
+	ReplicaPolicyRoundRobin ReplicaPolicy = "round_robin"
+	// ReplicaPolicyRandom picks a replica uniformly at random.
+	ReplicaPolicyRandom ReplicaPolicy = "random"
+	// ReplicaPolicyWeighted picks a replica at random, proportionally to its Weight.
+	ReplicaPolicyWeighted ReplicaPolicy = "weighted"
+)
+
 type (
 	// Config is the single, unified configuration struct for the entire database package.
 	Config struct {
@@ -22,6 +35,13 @@ type (
 		MaxOpenConns    int
 		ConnMaxLifetime time.Duration
 
+		// Replicas lists read replicas. When set, reads are routed to them and
+		// writes and transactions go to the primary described by DSN.
+		Replicas []Replica
+		// ReplicaPolicy selects how reads are spread across Replicas.
+		// It defaults to ReplicaPolicyRoundRobin.
+		ReplicaPolicy ReplicaPolicy
+
 		// Adapter is an optional field to explicitly inject a database adapter, bypassing the registry.
 		Adapter any
 
@@ -30,6 +50,18 @@ type (
 		Settings map[string]any
 	}
 
+	// Replica describes a read-only connection used for read/write splitting.
+	// It shares the driver and pool settings of the primary.
+	Replica struct {
+		// DSN is the connection string for the replica.
+		DSN string
+		// Weight is only used by ReplicaPolicyWeighted; zero counts as one.
+		Weight int
+	}
+
+	// ReplicaPolicy is the strategy used to pick a replica for a read.
+	ReplicaPolicy string
+
 	// Option defines a functional option for modifying a Config.
 	Option func(*Config)
 )
@@ -45,6 +77,15 @@ func New() *Config {
 	}
 }
 
+// Clone returns a copy of c that options can modify without affecting c.
+// Replicas and Settings are copied; the values stored in Settings are shared.
+func (c *Config) Clone() *Config {
+	clone := *c
+	clone.Replicas = slices.Clone(c.Replicas)
+	clone.Settings = maps.Clone(c.Settings)
+	return &clone
+}
+
 // Validate checks if the essential configuration fields are set.
 func (c *Config) Validate() error {
 	if c.Driver == "" {
@@ -53,5 +94,41 @@ func (c *Config) Validate() error {
 	if c.DSN == "" {
 		return errors.New("database dsn is required")
 	}
+	if err := validateDSN(c.Driver, c.DSN); err != nil {
+		return err
+	}
+	return c.validateReplicas()
+}
+
+// validateDSN parses dsn when the dialect of driver is known. DSNs of other
+// drivers, and DSNs given as a secret reference, are left to their adapter.
+func validateDSN(driver, dsn string) error {
+	if Dialect(driver) == "" || IsSecretRef(dsn) {
+		return nil
+	}
+	if _, err := ParseDSN(driver, dsn); err != nil {
+		return fmt.Errorf("invalid database dsn: %w", err)
+	}
+	return nil
+}
+
+// validateReplicas checks the replica list and routing policy.
+func (c *Config) validateReplicas() error {
+	switch c.ReplicaPolicy {
+	case "", ReplicaPolicyRoundRobin, ReplicaPolicyRandom, ReplicaPolicyWeighted:
+	default:
+		return fmt.Errorf("unknown replica policy %q", c.ReplicaPolicy)
+	}
+	for i, replica := range c.Replicas {
+		if replica.DSN == "" {
+			return fmt.Errorf("replica %d: dsn is required", i)
+		}
+		if err := validateDSN(c.Driver, replica.DSN); err != nil {
+			return fmt.Errorf("replica %d: %w", i, err)
+		}
+		if replica.Weight < 0 {
+			return fmt.Errorf("replica %d: weight cannot be negative", i)
+		}
+	}
 	return nil
 }
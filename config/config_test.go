@@ -73,6 +73,23 @@ func TestConfig_Validate(t *testing.T) {
 			wantErr: true,
 			errMsg:  "database dsn is required",
 		},
+		{
+			name: "malformed DSN",
+			config: &Config{
+				Driver: "gorm:mysql",
+				DSN:    "user:pass@localhost",
+			},
+			wantErr: true,
+			errMsg:  "invalid database dsn",
+		},
+		{
+			name: "unknown driver keeps its DSN",
+			config: &Config{
+				Driver: "custom",
+				DSN:    "anything goes",
+			},
+			wantErr: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -122,6 +139,22 @@ func TestConfig_Settings(t *testing.T) {
 	require.Len(t, cfg.Settings, 2)
 }
 
+func TestConfig_Clone(t *testing.T) {
+	cfg := New()
+	cfg.Replicas = []Replica{{DSN: "replica"}}
+	cfg.Settings["key"] = "value"
+
+	clone := cfg.Clone()
+	require.Equal(t, cfg, clone)
+
+	clone.DSN = "changed"
+	clone.Replicas[0].DSN = "changed"
+	clone.Settings["key"] = "changed"
+	require.Empty(t, cfg.DSN)
+	require.Equal(t, "replica", cfg.Replicas[0].DSN)
+	require.Equal(t, "value", cfg.Settings["key"])
+}
+
 func TestConfig_AllFields(t *testing.T) {
 	cfg := &Config{
 		Driver:          "gorm:mysql",
@@ -146,3 +179,82 @@ func TestConfig_AllFields(t *testing.T) {
 	require.Equal(t, "custom-adapter", cfg.Adapter)
 	require.Equal(t, "value", cfg.Settings["key"])
 }
+
+func TestConfig_ValidateReplicas(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  *Config
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name: "replicas with default policy",
+			config: &Config{
+				Driver:   "gorm:sqlite",
+				DSN:      "primary.db",
+				Replicas: []Replica{{DSN: "replica.db"}},
+			},
+		},
+		{
+			name: "weighted replicas",
+			config: &Config{
+				Driver:        "gorm:sqlite",
+				DSN:           "primary.db",
+				Replicas:      []Replica{{DSN: "a.db", Weight: 3}, {DSN: "b.db"}},
+				ReplicaPolicy: ReplicaPolicyWeighted,
+			},
+		},
+		{
+			name: "unknown policy",
+			config: &Config{
+				Driver:        "gorm:sqlite",
+				DSN:           "primary.db",
+				ReplicaPolicy: "fastest",
+			},
+			wantErr: true,
+			errMsg:  `unknown replica policy "fastest"`,
+		},
+		{
+			name: "replica without dsn",
+			config: &Config{
+				Driver:   "gorm:sqlite",
+				DSN:      "primary.db",
+				Replicas: []Replica{{DSN: "a.db"}, {}},
+			},
+			wantErr: true,
+			errMsg:  "replica 1: dsn is required",
+		},
+		{
+			name: "negative weight",
+			config: &Config{
+				Driver:   "gorm:sqlite",
+				DSN:      "primary.db",
+				Replicas: []Replica{{DSN: "a.db", Weight: -1}},
+			},
+			wantErr: true,
+			errMsg:  "replica 0: weight cannot be negative",
+		},
+		{
+			name: "malformed replica dsn",
+			config: &Config{
+				Driver:   "gorm:postgres",
+				DSN:      "host=primary",
+				Replicas: []Replica{{DSN: "host=replica"}, {DSN: "replica"}},
+			},
+			wantErr: true,
+			errMsg:  "replica 1: invalid database dsn",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if tt.wantErr {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tt.errMsg)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
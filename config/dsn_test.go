@@ -0,0 +1,173 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDSN_Format(t *testing.T) {
+	tests := []struct {
+		name string
+		dsn  DSN
+		want string
+	}{
+		{
+			name: "mysql",
+			dsn: DSN{
+				Driver: "gorm:mysql", Host: "db", Port: 3306, User: "app", Password: "secret", Database: "orders",
+				TLSMode: "true", Timezone: "UTC", Params: map[string]string{"parseTime": "true"},
+			},
+			want: "app:secret@tcp(db:3306)/orders?loc=UTC&parseTime=true&tls=true",
+		},
+		{
+			name: "mysql without host",
+			dsn:  DSN{Driver: "gorm:mysql", User: "root", Database: "app"},
+			want: "root@/app",
+		},
+		{
+			name: "mysql unix socket",
+			dsn:  DSN{Driver: "gorm:mysql", Host: "/run/mysqld/mysqld.sock", Database: "app"},
+			want: "unix(/run/mysqld/mysqld.sock)/app",
+		},
+		{
+			name: "postgres",
+			dsn: DSN{
+				Driver: "gorm:postgres", Host: "db", Port: 5432, User: "app", Password: "it's secret", Database: "orders",
+				TLSMode: "disable", Timezone: "Europe/Paris", Params: map[string]string{"application_name": "api"},
+			},
+			want: `host=db port=5432 user=app password='it\'s secret' dbname=orders TimeZone=Europe/Paris ` +
+				`application_name=api sslmode=disable`,
+		},
+		{
+			name: "sqlite",
+			dsn:  DSN{Driver: "gorm:sqlite", Database: "file:app.db", Params: map[string]string{"_fk": "1"}},
+			want: "file:app.db?_fk=1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.dsn.Format()
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err := (&DSN{Driver: "gorm:sqlite"}).Format()
+	require.EqualError(t, err, "sqlite dsn requires a database path")
+	_, err = (&DSN{Driver: "oracle"}).Format()
+	require.EqualError(t, err, `cannot build a dsn for driver "oracle"`)
+}
+
+func TestParseDSN(t *testing.T) {
+	tests := []struct {
+		name   string
+		driver string
+		dsn    string
+		want   DSN
+	}{
+		{
+			name:   "mysql",
+			driver: "gorm:mysql",
+			dsn:    "app:p@ss:word@tcp(db:3306)/orders?charset=utf8mb4&loc=Local&tls=skip-verify",
+			want: DSN{
+				Host: "db", Port: 3306, User: "app", Password: "p@ss:word", Database: "orders",
+				TLSMode: "skip-verify", Timezone: "Local", Params: map[string]string{"charset": "utf8mb4"},
+			},
+		},
+		{
+			name:   "mysql ipv6",
+			driver: "mysql",
+			dsn:    "root@tcp([::1]:3307)/app",
+			want:   DSN{Host: "::1", Port: 3307, User: "root", Database: "app", Params: map[string]string{}},
+		},
+		{
+			name:   "postgres keywords",
+			driver: "gorm:postgres",
+			dsn:    `host=db port=5432 user=app password='it\'s secret' dbname=orders sslmode=require TimeZone=UTC`,
+			want: DSN{
+				Host: "db", Port: 5432, User: "app", Password: "it's secret", Database: "orders",
+				TLSMode: "require", Timezone: "UTC", Params: map[string]string{},
+			},
+		},
+		{
+			name:   "postgres url",
+			driver: "gorm:postgres",
+			dsn:    "postgres://app:s%3Acret@db:5433/orders?sslmode=disable&application_name=api",
+			want: DSN{
+				Host: "db", Port: 5433, User: "app", Password: "s:cret", Database: "orders",
+				TLSMode: "disable", Params: map[string]string{"application_name": "api"},
+			},
+		},
+		{
+			name:   "sqlite",
+			driver: "gorm:sqlite",
+			dsn:    "file::memory:?cache=shared&_loc=auto",
+			want:   DSN{Database: "file::memory:", Timezone: "auto", Params: map[string]string{"cache": "shared"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDSN(tt.driver, tt.dsn)
+			require.NoError(t, err)
+			tt.want.Driver = tt.driver
+			require.Equal(t, &tt.want, got)
+		})
+	}
+}
+
+func TestParseDSN_RoundTrip(t *testing.T) {
+	for driver, dsn := range map[string]string{
+		"gorm:mysql":    "app:secret@tcp(db:3306)/orders?parseTime=true",
+		"gorm:postgres": "host=db port=5432 user=app password=secret dbname=orders sslmode=disable",
+		"gorm:sqlite":   "app.db?_fk=1",
+	} {
+		d, err := ParseDSN(driver, dsn)
+		require.NoError(t, err)
+		formatted, err := d.Format()
+		require.NoError(t, err)
+		require.Equal(t, dsn, formatted, driver)
+	}
+}
+
+func TestParseDSN_Errors(t *testing.T) {
+	tests := []struct {
+		name, driver, dsn, errMsg string
+	}{
+		{"mysql without slash", "gorm:mysql", "app:secret@db", "must contain '/'"},
+		{"mysql bad address", "gorm:mysql", "app:secret@db:3306/app", "malformed address"},
+		{"mysql bad protocol", "gorm:mysql", "app:secret@udp(db)/app", `unsupported protocol "udp"`},
+		{"mysql bad port", "gorm:mysql", "app:secret@tcp(db:http)/app", `invalid port "http"`},
+		{"postgres without equals", "gorm:postgres", "host=db secretpassword", "key=value settings"},
+		{"postgres unterminated quote", "gorm:postgres", "host=db password='secret", "unterminated quoted value"},
+		{"postgres bad url", "gorm:postgres", "postgres://app:secret@db:port/app", "not a valid url"},
+		{"sqlite without path", "gorm:sqlite", "?mode=memory", "requires a database path"},
+		{"unknown driver", "oracle", "whatever", `cannot parse a dsn for driver "oracle"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseDSN(tt.driver, tt.dsn)
+			require.ErrorContains(t, err, tt.errMsg)
+			require.NotContains(t, err.Error(), "secret", "errors must not leak the password")
+		})
+	}
+}
+
+func TestDSN_String(t *testing.T) {
+	d, err := ParseDSN("gorm:postgres", "host=db user=app password=secret dbname=orders sslpassword=key")
+	require.NoError(t, err)
+	require.Equal(t, "host=db user=app password=[REDACTED] dbname=orders sslpassword=[REDACTED]", d.String())
+	require.Equal(t, "secret", d.Password, "String does not modify the DSN")
+
+	require.Equal(t, "app:[REDACTED]@tcp(db)/orders", RedactDSN("gorm:mysql", "app:secret@tcp(db)/orders"))
+	require.Equal(t, "[REDACTED]", RedactDSN("gorm:mysql", "app:secret@db"), "unparsable DSNs are hidden")
+	require.Equal(t, "oracle([REDACTED])", (&DSN{Driver: "oracle", Password: "secret"}).String())
+}
+
+func TestDialect(t *testing.T) {
+	require.Equal(t, DialectMySQL, Dialect("gorm:mysql"))
+	require.Equal(t, DialectPostgres, Dialect("postgres"))
+	require.Equal(t, DialectSQLite, Dialect("sqlite3"))
+	require.Equal(t, DialectPostgres, Dialect("sql:postgres"))
+	require.Empty(t, Dialect("custom"))
+}
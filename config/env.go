@@ -0,0 +1,184 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Prefixes of the variables read by FromEnv.
+const (
+	envDatabasePrefix    = "DATABASE_"
+	envConnectionsPrefix = envDatabasePrefix + "CONNECTIONS_"
+	envSettingsInfix     = "_SETTINGS_"
+)
+
+//nolint:grouper // Lookup tables used only by interpolation and FromEnv
+var (
+	// envReference matches `$$`, `${VAR}` and `${VAR:-default}`.
+	envReference = regexp.MustCompile(`\$\$|\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}`)
+
+	// envConnectionKeys are the connection keys FromEnv recognizes, longest first
+	// so that MAX_OPEN_CONNS is not mistaken for a connection named ..._MAX_OPEN.
+	envConnectionKeys = []string{
+		"conn_max_lifetime", "max_idle_conns", "max_open_conns", "replica_policy",
+		"password", "database", "replicas", "timezone", "tls_mode", "driver", "host", "port", "user", "dsn",
+	}
+)
+
+// FromEnv reads the database section from environment variables named after
+// the YAML keys, in upper case and joined by underscores:
+//
+//	DATABASE_DEFAULT=primary
+//	DATABASE_CONNECTIONS_PRIMARY_DRIVER=gorm:postgres
+//	DATABASE_CONNECTIONS_PRIMARY_DSN=host=db user=app dbname=app
+//	DATABASE_CONNECTIONS_PRIMARY_MAX_OPEN_CONNS=50
+//	DATABASE_CONNECTIONS_PRIMARY_REPLICAS=host=replica1 ...,host=replica2 ...
+//	DATABASE_CONNECTIONS_PRIMARY_SETTINGS_LOG_LEVEL=warn
+//	DATABASE_PATHS_MIGRATIONS=database/migrations
+//
+// Connection names and the default are lower-cased. REPLICAS is a comma
+// separated list of DSNs. Values are converted like in Parse.
+func FromEnv(opts ...LoadOption) (*Database, error) {
+	database := &yaml.Node{Kind: yaml.MappingNode}
+	connections := &yaml.Node{Kind: yaml.MappingNode}
+	paths := &yaml.Node{Kind: yaml.MappingNode}
+
+	environ := os.Environ()
+	slices.Sort(environ)
+	for _, entry := range environ {
+		key, value, _ := strings.Cut(entry, "=")
+		switch {
+		case key == envDatabasePrefix+"DEFAULT":
+			setValue(database, "default", scalarNode(strings.ToLower(value)))
+		case strings.HasPrefix(key, envDatabasePrefix+"PATHS_"):
+			setValue(paths, strings.ToLower(strings.TrimPrefix(key, envDatabasePrefix+"PATHS_")), scalarNode(value))
+		case strings.HasPrefix(key, envConnectionsPrefix):
+			setConnectionValue(connections, strings.TrimPrefix(key, envConnectionsPrefix), value)
+		}
+	}
+
+	setValue(database, "connections", connections)
+	setValue(database, "paths", paths)
+	return decodeDatabase(database, newLoadOptions(opts))
+}
+
+// setConnectionValue stores a DATABASE_CONNECTIONS_<NAME>_<KEY> variable.
+func setConnectionValue(connections *yaml.Node, rest, value string) {
+	if name, setting, found := strings.Cut(rest, envSettingsInfix); found && name != "" {
+		settings := connectionNode(connections, name, "settings")
+		setValue(settings, strings.ToLower(setting), scalarNode(value))
+		return
+	}
+
+	for _, key := range envConnectionKeys {
+		suffix := "_" + strings.ToUpper(key)
+		name, found := strings.CutSuffix(rest, suffix)
+		if !found || name == "" {
+			continue
+		}
+		conn := connectionNode(connections, name, "")
+		if key != "replicas" {
+			setValue(conn, key, scalarNode(value))
+			return
+		}
+		replicas := &yaml.Node{Kind: yaml.SequenceNode}
+		for _, dsn := range strings.Split(value, ",") {
+			replica := &yaml.Node{Kind: yaml.MappingNode}
+			setValue(replica, "dsn", scalarNode(strings.TrimSpace(dsn)))
+			replicas.Content = append(replicas.Content, replica)
+		}
+		setValue(conn, key, replicas)
+		return
+	}
+}
+
+// connectionNode returns the mapping of the named connection, or of one of its
+// nested keys when child is set, creating them as needed.
+func connectionNode(connections *yaml.Node, name, child string) *yaml.Node {
+	name = strings.ToLower(name)
+	conn := mappingValue(connections, name)
+	if conn == nil {
+		conn = &yaml.Node{Kind: yaml.MappingNode}
+		setValue(connections, name, conn)
+	}
+	if child == "" {
+		return conn
+	}
+	nested := mappingValue(conn, child)
+	if nested == nil {
+		nested = &yaml.Node{Kind: yaml.MappingNode}
+		setValue(conn, child, nested)
+	}
+	return nested
+}
+
+// setValue sets key to value in a mapping node.
+func setValue(node *yaml.Node, key string, value *yaml.Node) {
+	if existing := mappingValue(node, key); existing != nil {
+		*existing = *value
+		return
+	}
+	node.Content = append(node.Content, scalarNode(key), value)
+}
+
+// scalarNode returns an untagged plain scalar, whose type is resolved on decode
+// like an unquoted YAML value.
+func scalarNode(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Value: value}
+}
+
+// interpolateNode replaces environment references in every scalar of node.
+func interpolateNode(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		if !strings.Contains(node.Value, "$") {
+			return nil
+		}
+		value, err := interpolate(node.Value)
+		if err != nil {
+			return err
+		}
+		node.Value = value
+		if node.Style == 0 {
+			// Resolve the type of plain scalars from the interpolated value,
+			// so that `max_open_conns: ${POOL_SIZE}` decodes as a number.
+			node.Tag = ""
+		}
+		return nil
+	}
+
+	var errs []error
+	for i, child := range node.Content {
+		if node.Kind == yaml.MappingNode && i%2 == 0 {
+			continue // keys are not interpolated
+		}
+		errs = append(errs, interpolateNode(child))
+	}
+	return errors.Join(errs...)
+}
+
+// interpolate replaces `${VAR}`, `${VAR:-default}` and `$$` in s.
+func interpolate(s string) (string, error) {
+	var errs []error
+	result := envReference.ReplaceAllStringFunc(s, func(match string) string {
+		if match == "$$" {
+			return "$"
+		}
+		groups := envReference.FindStringSubmatch(match)
+		// As in a shell, the default also replaces an empty value.
+		if value, ok := os.LookupEnv(groups[1]); ok && (value != "" || groups[2] == "") {
+			return value
+		}
+		if groups[2] != "" {
+			return groups[3]
+		}
+		errs = append(errs, fmt.Errorf("environment variable %q is not set", groups[1]))
+		return match
+	})
+	return result, errors.Join(errs...)
+}
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromEnv(t *testing.T) {
+	t.Setenv("DATABASE_DEFAULT", "PRIMARY")
+	t.Setenv("DATABASE_CONNECTIONS_PRIMARY_DRIVER", "gorm:postgres")
+	t.Setenv("DATABASE_CONNECTIONS_PRIMARY_DSN", "host=db user=app dbname=app")
+	t.Setenv("DATABASE_CONNECTIONS_PRIMARY_MAX_OPEN_CONNS", "50")
+	t.Setenv("DATABASE_CONNECTIONS_PRIMARY_CONN_MAX_LIFETIME", "5m")
+	t.Setenv("DATABASE_CONNECTIONS_PRIMARY_REPLICAS", "host=replica1, host=replica2")
+	t.Setenv("DATABASE_CONNECTIONS_PRIMARY_SETTINGS_LOG_LEVEL", "warn")
+	t.Setenv("DATABASE_CONNECTIONS_READ_MODEL_DRIVER", "gorm:mysql")
+	t.Setenv("DATABASE_CONNECTIONS_READ_MODEL_HOST", "mysql")
+	t.Setenv("DATABASE_CONNECTIONS_READ_MODEL_DATABASE", "reads")
+	t.Setenv("DATABASE_PATHS_MIGRATIONS", "file:///srv/migrations")
+
+	database, err := FromEnv(WithEnvironment(""))
+	require.NoError(t, err)
+	require.Equal(t, "primary", database.Default)
+	require.Equal(t, "file:///srv/migrations", database.MigrationsPath)
+
+	primary, err := database.Connection("")
+	require.NoError(t, err)
+	require.Equal(t, "gorm:postgres", primary.Driver)
+	require.Equal(t, "host=db user=app dbname=app", primary.DSN)
+	require.Equal(t, 50, primary.MaxOpenConns)
+	require.Equal(t, 5*time.Minute, primary.ConnMaxLifetime)
+	require.Equal(t, []Replica{{DSN: "host=replica1"}, {DSN: "host=replica2"}}, primary.Replicas)
+	require.Equal(t, "warn", primary.Settings["log_level"])
+
+	readModel, err := database.Connection("read_model")
+	require.NoError(t, err)
+	require.Equal(t, "tcp(mysql)/reads", readModel.DSN)
+}
+
+func TestInterpolate(t *testing.T) {
+	t.Setenv("TEST_HOST", "db")
+	t.Setenv("TEST_EMPTY", "")
+
+	tests := []struct {
+		in, want string
+	}{
+		{"host=${TEST_HOST}", "host=db"},
+		{"${TEST_UNSET_VAR:-fallback}", "fallback"},
+		{"${TEST_EMPTY:-fallback}", "fallback"},
+		{"[${TEST_EMPTY}]", "[]"},
+		{"${TEST_UNSET_VAR:-}", ""},
+		{"price: $$5 and $HOME", "price: $5 and $HOME"},
+	}
+	for _, tt := range tests {
+		got, err := interpolate(tt.in)
+		require.NoError(t, err)
+		require.Equal(t, tt.want, got, tt.in)
+	}
+
+	_, err := interpolate("${TEST_UNSET_A} ${TEST_UNSET_B}")
+	require.ErrorContains(t, err, `"TEST_UNSET_A" is not set`)
+	require.ErrorContains(t, err, `"TEST_UNSET_B" is not set`)
+}
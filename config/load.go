@@ -0,0 +1,363 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Environments selected with WithEnvironment. Any other name works as well, as
+// long as database.environments has an entry for it.
+const (
+	EnvDevelopment = "development"
+	EnvTest        = "test"
+	EnvProduction  = "production"
+
+	// EnvironmentVariable selects the environment overlay when WithEnvironment is not given.
+	EnvironmentVariable = "APP_ENV"
+)
+
+type (
+	// Database is the `database` section of config.yaml, shared with the CLI:
+	// the named connections, the default one and the project paths.
+	//
+	//	database:
+	//	  default: primary
+	//	  connections:
+	//	    primary:
+	//	      driver: gorm:postgres
+	//	      dsn: ${DATABASE_URL}
+	//	      max_open_conns: 50
+	//	  paths:
+	//	    models: domain
+	//	    migrations: database/migrations
+	//	  environments:
+	//	    production:
+	//	      connections:
+	//	        primary:
+	//	          max_open_conns: 200
+	Database struct {
+		// Default is the name of the connection used when none is given.
+		Default string
+		// Connections holds the config of every connection, by name.
+		Connections map[string]*Config
+		// ModelsPath is database.paths.models, as written.
+		ModelsPath string
+		// MigrationsPath is database.paths.migrations, as written.
+		MigrationsPath string
+	}
+
+	// LoadOption defines a functional option for Load, Parse, Decode and FromEnv.
+	LoadOption func(*loadOptions)
+
+	loadOptions struct {
+		environment string
+		connection  string
+	}
+
+	// fileDatabase mirrors the YAML layout of the database section.
+	fileDatabase struct {
+		Default     string                    `yaml:"default"`
+		Connections map[string]fileConnection `yaml:"connections"`
+		Paths       struct {
+			Models     string `yaml:"models"`
+			Migrations string `yaml:"migrations"`
+		} `yaml:"paths"`
+	}
+
+	// fileConnection is an entry of database.connections. The DSN is either
+	// given as a whole or built from its components.
+	fileConnection struct {
+		Driver   string            `yaml:"driver"`
+		DSN      string            `yaml:"dsn"`
+		Host     string            `yaml:"host"`
+		Port     int               `yaml:"port"`
+		User     string            `yaml:"user"`
+		Password string            `yaml:"password"`
+		Database string            `yaml:"database"`
+		TLSMode  string            `yaml:"tls_mode"`
+		Timezone string            `yaml:"timezone"`
+		Params   map[string]string `yaml:"params"`
+
+		MaxIdleConns    *int           `yaml:"max_idle_conns"`
+		MaxOpenConns    *int           `yaml:"max_open_conns"`
+		ConnMaxLifetime *time.Duration `yaml:"conn_max_lifetime"`
+
+		Replicas      []fileReplica  `yaml:"replicas"`
+		ReplicaPolicy ReplicaPolicy  `yaml:"replica_policy"`
+		Settings      map[string]any `yaml:"settings"`
+	}
+
+	fileReplica struct {
+		DSN    string `yaml:"dsn"`
+		Weight int    `yaml:"weight"`
+	}
+)
+
+// WithEnvironment selects the overlay of database.environments that is merged
+// over the database section. It defaults to the value of APP_ENV.
+func WithEnvironment(environment string) LoadOption {
+	return func(o *loadOptions) {
+		o.environment = environment
+	}
+}
+
+// WithConnection decodes only the named entry of database.connections. The
+// other entries are dropped before interpolation, so their unset variables and
+// invalid settings are not reported, and Database.Connections holds at most
+// this one connection.
+func WithConnection(name string) LoadOption {
+	return func(o *loadOptions) {
+		o.connection = name
+	}
+}
+
+// Load reads the database section of the YAML file at path. See Parse.
+func Load(path string, opts ...LoadOption) (*Database, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	return Parse(data, opts...)
+}
+
+// Parse reads the database section of a YAML document. The overlay of the
+// selected environment is merged over it, then ${VAR} and ${VAR:-default}
+// references are replaced by environment variables; `$$` stands for a literal `$`.
+// A reference to an unset variable without a default is an error.
+func Parse(data []byte, opts ...LoadOption) (*Database, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+	if root.Kind == yaml.DocumentNode && len(root.Content) > 0 {
+		return decodeDatabase(mappingValue(root.Content[0], "database"), newLoadOptions(opts))
+	}
+	return decodeDatabase(nil, newLoadOptions(opts))
+}
+
+// Decode reads the database section of already parsed settings, such as the
+// ones returned by viper's AllSettings. It behaves like Parse. Viper lowercases
+// every key, so connections decoded from its settings have lowercase names.
+func Decode(settings map[string]any, opts ...LoadOption) (*Database, error) {
+	data, err := yaml.Marshal(settings)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode settings: %w", err)
+	}
+	return Parse(data, opts...)
+}
+
+// Connection returns the config of the named connection. An empty name returns
+// the default connection.
+func (d *Database) Connection(name string) (*Config, error) {
+	if name == "" {
+		name = d.Default
+	}
+	if name == "" {
+		return nil, errors.New("no default connection is configured")
+	}
+	cfg, ok := d.Connections[name]
+	if !ok {
+		return nil, fmt.Errorf("connection %q is not configured", name)
+	}
+	return cfg, nil
+}
+
+func newLoadOptions(opts []LoadOption) loadOptions {
+	o := loadOptions{environment: os.Getenv(EnvironmentVariable)}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// decodeDatabase applies the environment overlay and interpolation to the
+// database node and converts it.
+func decodeDatabase(node *yaml.Node, o loadOptions) (*Database, error) {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil, errors.New("config has no database section")
+	}
+
+	environments := mappingValue(node, "environments")
+	removeKey(node, "environments")
+	if overlay := mappingValue(environments, o.environment); o.environment != "" && overlay != nil {
+		mergeNodes(node, overlay)
+	}
+	if o.connection != "" {
+		keepKey(mappingValue(node, "connections"), o.connection)
+	}
+	if err := interpolateNode(node); err != nil {
+		return nil, err
+	}
+
+	var file fileDatabase
+	if err := node.Decode(&file); err != nil {
+		return nil, fmt.Errorf("failed to decode database config: %w", err)
+	}
+	return file.database(o)
+}
+
+// database converts the file layout into a Database.
+func (f *fileDatabase) database(o loadOptions) (*Database, error) {
+	d := &Database{
+		Default:        f.Default,
+		Connections:    make(map[string]*Config, len(f.Connections)),
+		ModelsPath:     f.Paths.Models,
+		MigrationsPath: f.Paths.Migrations,
+	}
+	migrationsURL, err := migrationsSourceURL(f.Paths.Migrations)
+	if err != nil {
+		return nil, err
+	}
+
+	for name, conn := range f.Connections {
+		cfg, err := conn.config(name)
+		if err != nil {
+			return nil, fmt.Errorf("connection %q: %w", name, err)
+		}
+		if f.Paths.Models != "" {
+			cfg.ModelsPath = f.Paths.Models
+		}
+		cfg.MigrationsPath = migrationsURL
+		d.Connections[name] = cfg
+	}
+
+	// With WithConnection, the default connection may have been dropped.
+	if d.Default != "" && (o.connection == "" || o.connection == d.Default) {
+		if _, ok := d.Connections[d.Default]; !ok {
+			return nil, fmt.Errorf("default connection %q is not configured", d.Default)
+		}
+	}
+	return d, nil
+}
+
+// config converts a connection entry into a Config. The driver defaults to the
+// connection name, so `gorm:sqlite: {dsn: app.db}` works as in the CLI.
+func (c *fileConnection) config(name string) (*Config, error) {
+	cfg := New()
+	cfg.Driver = c.Driver
+	if cfg.Driver == "" {
+		cfg.Driver = name
+	}
+
+	cfg.DSN = c.DSN
+	if cfg.DSN == "" && (c.Host != "" || c.Database != "") {
+		dsn, err := c.dsn(cfg.Driver).Format()
+		if err != nil {
+			return nil, err
+		}
+		cfg.DSN = dsn
+	}
+
+	if c.MaxIdleConns != nil {
+		cfg.MaxIdleConns = *c.MaxIdleConns
+	}
+	if c.MaxOpenConns != nil {
+		cfg.MaxOpenConns = *c.MaxOpenConns
+	}
+	if c.ConnMaxLifetime != nil {
+		cfg.ConnMaxLifetime = *c.ConnMaxLifetime
+	}
+
+	cfg.ReplicaPolicy = c.ReplicaPolicy
+	for _, replica := range c.Replicas {
+		cfg.Replicas = append(cfg.Replicas, Replica(replica))
+	}
+	for key, value := range c.Settings {
+		cfg.Settings[key] = value
+	}
+	return cfg, nil
+}
+
+// dsn returns the DSN components of the entry.
+func (c *fileConnection) dsn(driver string) *DSN {
+	return &DSN{
+		Driver:   driver,
+		Host:     c.Host,
+		Port:     c.Port,
+		User:     c.User,
+		Password: c.Password,
+		Database: c.Database,
+		TLSMode:  c.TLSMode,
+		Timezone: c.Timezone,
+		Params:   c.Params,
+	}
+}
+
+// migrationsSourceURL turns a migrations directory into the file:// source URL
+// expected by the migrator, like the CLI does. URLs are kept as they are.
+func migrationsSourceURL(path string) (string, error) {
+	if path == "" || strings.Contains(path, "://") {
+		return path, nil
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve migrations path: %w", err)
+	}
+	return "file://" + abs, nil
+}
+
+// --- YAML node helpers ---
+
+// mappingValue returns the value of key in a mapping node, or nil.
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// removeKey deletes key from a mapping node.
+func removeKey(node *yaml.Node, key string) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			node.Content = slices.Delete(node.Content, i, i+2)
+			return
+		}
+	}
+}
+
+// keepKey deletes every key but key from a mapping node.
+func keepKey(node *yaml.Node, key string) {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(node.Content); {
+		if node.Content[i].Value == key {
+			i += 2
+			continue
+		}
+		node.Content = slices.Delete(node.Content, i, i+2)
+	}
+}
+
+// mergeNodes deep-merges the overlay mapping into base. Nested mappings are
+// merged key by key; any other value in overlay replaces the one in base.
+func mergeNodes(base, overlay *yaml.Node) {
+	if overlay.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(overlay.Content); i += 2 {
+		key, value := overlay.Content[i], overlay.Content[i+1]
+		existing := mappingValue(base, key.Value)
+		switch {
+		case existing == nil:
+			base.Content = append(base.Content, key, value)
+		case existing.Kind == yaml.MappingNode && value.Kind == yaml.MappingNode:
+			mergeNodes(existing, value)
+		default:
+			*existing = *value
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testConfigYAML = `
+database:
+  default: primary
+  connections:
+    primary:
+      driver: gorm:postgres
+      dsn: ${TEST_PRIMARY_DSN}
+      max_open_conns: ${TEST_POOL_SIZE:-25}
+      conn_max_lifetime: 30m
+      replica_policy: weighted
+      replicas:
+        - dsn: host=replica1
+          weight: 2
+      settings:
+        log_level: warn
+    gorm:sqlite:
+      dsn: app.db
+    reporting:
+      driver: gorm:mysql
+      host: localhost
+      port: 3306
+      user: app
+      password: "${TEST_MYSQL_PASSWORD}"
+      database: reports
+      params:
+        parseTime: "true"
+  paths:
+    models: domain
+    migrations: database/migrations
+  environments:
+    production:
+      connections:
+        primary:
+          max_open_conns: 200
+          settings:
+            log_level: error
+    test:
+      default: gorm:sqlite
+      connections:
+        primary:
+          dsn: ${TEST_UNSET_IN_TEST_ENVIRONMENT}
+`
+
+func TestParse(t *testing.T) {
+	t.Setenv("TEST_PRIMARY_DSN", "host=db user=app dbname=app")
+	t.Setenv("TEST_MYSQL_PASSWORD", "s3cr$t: 42")
+
+	database, err := Parse([]byte(testConfigYAML), WithEnvironment(EnvDevelopment))
+	require.NoError(t, err)
+	require.Equal(t, "primary", database.Default)
+	require.Equal(t, "domain", database.ModelsPath)
+	require.Equal(t, "database/migrations", database.MigrationsPath)
+	require.Len(t, database.Connections, 3)
+
+	primary, err := database.Connection("")
+	require.NoError(t, err)
+	require.Equal(t, "gorm:postgres", primary.Driver)
+	require.Equal(t, "host=db user=app dbname=app", primary.DSN)
+	require.Equal(t, 25, primary.MaxOpenConns, "the default of the reference applies")
+	require.Equal(t, 10, primary.MaxIdleConns, "unset pool settings keep the defaults of New")
+	require.Equal(t, 30*time.Minute, primary.ConnMaxLifetime)
+	require.Equal(t, ReplicaPolicyWeighted, primary.ReplicaPolicy)
+	require.Equal(t, []Replica{{DSN: "host=replica1", Weight: 2}}, primary.Replicas)
+	require.Equal(t, "warn", primary.Settings["log_level"])
+	require.Equal(t, "domain", primary.ModelsPath)
+
+	abs, err := filepath.Abs("database/migrations")
+	require.NoError(t, err)
+	require.Equal(t, "file://"+abs, primary.MigrationsPath)
+
+	sqlite, err := database.Connection("gorm:sqlite")
+	require.NoError(t, err)
+	require.Equal(t, "gorm:sqlite", sqlite.Driver, "driver defaults to the connection name")
+	require.Equal(t, "app.db", sqlite.DSN)
+
+	reporting, err := database.Connection("reporting")
+	require.NoError(t, err)
+	require.Equal(t, "app:s3cr$t: 42@tcp(localhost:3306)/reports?parseTime=true", reporting.DSN)
+
+	_, err = database.Connection("missing")
+	require.EqualError(t, err, `connection "missing" is not configured`)
+}
+
+func TestParse_EnvironmentOverlay(t *testing.T) {
+	t.Setenv("TEST_PRIMARY_DSN", "host=db")
+	t.Setenv("TEST_MYSQL_PASSWORD", "secret")
+	t.Setenv("TEST_POOL_SIZE", "50")
+
+	database, err := Parse([]byte(testConfigYAML), WithEnvironment(EnvProduction))
+	require.NoError(t, err)
+	primary := database.Connections["primary"]
+	require.Equal(t, 200, primary.MaxOpenConns)
+	require.Equal(t, "error", primary.Settings["log_level"])
+	require.Equal(t, "host=db", primary.DSN, "keys missing from the overlay are kept")
+
+	t.Setenv(EnvironmentVariable, EnvTest)
+	_, err = Parse([]byte(testConfigYAML))
+	require.EqualError(t, err, `environment variable "TEST_UNSET_IN_TEST_ENVIRONMENT" is not set`,
+		"APP_ENV selects the overlay")
+}
+
+func TestParse_WithConnection(t *testing.T) {
+	t.Setenv("TEST_PRIMARY_DSN", "host=db")
+	yaml := strings.Replace(testConfigYAML, "    gorm:sqlite:\n",
+		"    broken:\n      dsn: ${TEST_SURELY_UNSET}\n      max_open_conns: many\n    gorm:sqlite:\n", 1)
+
+	_, err := Parse([]byte(yaml), WithEnvironment(""))
+	require.Error(t, err)
+
+	database, err := Parse([]byte(yaml), WithEnvironment(""), WithConnection("gorm:sqlite"))
+	require.NoError(t, err, "other connections should be neither interpolated nor validated")
+	require.Len(t, database.Connections, 1)
+	require.Equal(t, "app.db", database.Connections["gorm:sqlite"].DSN)
+	require.Equal(t, "primary", database.Default)
+
+	_, err = Parse([]byte(yaml), WithEnvironment(""), WithConnection("broken"))
+	require.EqualError(t, err, `environment variable "TEST_SURELY_UNSET" is not set`)
+
+	database, err = Parse([]byte(yaml), WithEnvironment(""), WithConnection("missing"))
+	require.NoError(t, err)
+	_, err = database.Connection("missing")
+	require.EqualError(t, err, `connection "missing" is not configured`)
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		yaml   string
+		errMsg string
+	}{
+		{"no database section", "other: {}", "config has no database section"},
+		{"empty document", "", "config has no database section"},
+		{"invalid yaml", "database: [", "failed to parse config"},
+		{"unknown default", "database:\n  default: nope\n  connections: {a: {dsn: x}}", `default connection "nope"`},
+		{"missing variable", "database:\n  connections: {a: {dsn: '${TEST_SURELY_UNSET}'}}", `"TEST_SURELY_UNSET" is not set`},
+		{"bad pool size", "database:\n  connections: {a: {dsn: x, max_open_conns: many}}", "failed to decode"},
+		{"dsn parts for unknown driver", "database:\n  connections: {a: {host: h}}", `cannot build a dsn for driver "a"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.yaml), WithEnvironment(""))
+			require.ErrorContains(t, err, tt.errMsg)
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("database:\n  connections:\n    gorm:sqlite:\n      dsn: $$HOME.db\n"), 0o600))
+
+	database, err := Load(path)
+	require.NoError(t, err)
+	require.Equal(t, "$HOME.db", database.Connections["gorm:sqlite"].DSN, "$$ escapes a dollar sign")
+
+	_, err = database.Connection("")
+	require.EqualError(t, err, "no default connection is configured")
+
+	_, err = Load(filepath.Join(t.TempDir(), "missing.yaml"))
+	require.ErrorContains(t, err, "failed to read config file")
+}
+
+func TestDecode(t *testing.T) {
+	database, err := Decode(map[string]any{
+		"database": map[string]any{
+			"default":     "gorm:sqlite",
+			"connections": map[string]any{"gorm:sqlite": map[string]any{"dsn": "app.db", "max_idle_conns": 2}},
+			"paths":       map[string]any{"migrations": "file:///srv/migrations"},
+		},
+	})
+	require.NoError(t, err)
+	cfg := database.Connections["gorm:sqlite"]
+	require.Equal(t, 2, cfg.MaxIdleConns)
+	require.Equal(t, "file:///srv/migrations", cfg.MigrationsPath, "source URLs are kept")
+}
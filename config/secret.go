@@ -0,0 +1,164 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// SecretScheme prefixes secret references. A reference names a provider and
+// a secret: `secret://env/DB_PASSWORD` or `secret://file/run/secrets/db_password`.
+const SecretScheme = "secret://"
+
+// Names of the built-in secret providers.
+const (
+	SecretProviderFile = "file"
+	SecretProviderEnv  = "env"
+)
+
+const secretProvidersSetting = "secret_providers"
+
+type (
+	// SecretProvider returns the current value of a named secret. Providers are
+	// asked again every time a connection of the pool is opened, so a rotated
+	// value is used by new connections without restarting the service.
+	SecretProvider interface {
+		Secret(ctx context.Context, name string) (string, error)
+	}
+
+	// SecretProviderFunc adapts a function to the SecretProvider interface.
+	SecretProviderFunc func(ctx context.Context, name string) (string, error)
+
+	// FileSecretProvider reads secrets from files, such as the ones mounted by
+	// Docker or Kubernetes. The name is a path inside Dir, the file system root
+	// when empty; surrounding whitespace, including the trailing newline, is trimmed.
+	FileSecretProvider struct {
+		Dir string
+	}
+
+	// EnvSecretProvider reads secrets from environment variables. The name is
+	// the variable name; an unset variable is an error.
+	EnvSecretProvider struct{}
+)
+
+// Secret calls f(ctx, name).
+func (f SecretProviderFunc) Secret(ctx context.Context, name string) (string, error) {
+	return f(ctx, name)
+}
+
+// Secret reads the file name in p.Dir.
+func (p FileSecretProvider) Secret(_ context.Context, name string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(p.Dir, filepath.Clean("/"+name)))
+	if err != nil {
+		return "", fmt.Errorf("failed to read secret file: %w", err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// Secret returns the value of the environment variable name.
+func (EnvSecretProvider) Secret(_ context.Context, name string) (string, error) {
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return "", fmt.Errorf("environment variable %q is not set", name)
+	}
+	return value, nil
+}
+
+// WithSecretProvider registers provider for the references `secret://<name>/...`.
+// It replaces the built-in provider of the same name, so
+// WithSecretProvider(SecretProviderFile, FileSecretProvider{Dir: "/etc/app"})
+// changes the directory file references are read from.
+func WithSecretProvider(name string, provider SecretProvider) Option {
+	return func(cfg *Config) {
+		if cfg.Settings == nil {
+			cfg.Settings = make(map[string]any)
+		}
+		providers := maps.Clone(SecretProviders(cfg))
+		providers[name] = provider
+		cfg.Settings[secretProvidersSetting] = providers
+	}
+}
+
+// SecretProviders returns the secret providers of cfg by name: the built-in
+// file provider, which reads absolute paths, the environment provider and the
+// ones registered with WithSecretProvider.
+func SecretProviders(cfg *Config) map[string]SecretProvider {
+	if providers, ok := cfg.Settings[secretProvidersSetting].(map[string]SecretProvider); ok {
+		return providers
+	}
+	return map[string]SecretProvider{
+		SecretProviderFile: FileSecretProvider{Dir: "/"},
+		SecretProviderEnv:  EnvSecretProvider{},
+	}
+}
+
+// IsSecretRef reports whether value is a secret reference as a whole.
+func IsSecretRef(value string) bool {
+	return strings.HasPrefix(value, SecretScheme)
+}
+
+// HasSecretRefs reports whether dsn contains secret references.
+func HasSecretRefs(dsn string) bool {
+	return strings.Contains(dsn, SecretScheme)
+}
+
+// ResolveDSN returns dsn with its secret references replaced by their current
+// values. The DSN may be a reference as a whole; for the dialects known to
+// ParseDSN, its user, password, host, database and parameters may also be
+// references, and the resolved values are escaped as the dialect requires.
+// Errors name the reference but never include a secret value.
+func (c *Config) ResolveDSN(ctx context.Context, dsn string) (string, error) {
+	if !HasSecretRefs(dsn) {
+		return dsn, nil
+	}
+	providers := SecretProviders(c)
+	if IsSecretRef(dsn) {
+		return resolveSecret(ctx, providers, dsn)
+	}
+
+	parsed, err := ParseDSN(c.Driver, dsn)
+	if err != nil {
+		return "", fmt.Errorf("cannot resolve the secrets of the dsn: %w", err)
+	}
+	fields := []*string{&parsed.User, &parsed.Password, &parsed.Host, &parsed.Database}
+	for key, value := range parsed.Params {
+		if IsSecretRef(value) {
+			resolved, err := resolveSecret(ctx, providers, value)
+			if err != nil {
+				return "", err
+			}
+			parsed.Params[key] = resolved
+		}
+	}
+	for _, field := range fields {
+		if IsSecretRef(*field) {
+			if *field, err = resolveSecret(ctx, providers, *field); err != nil {
+				return "", err
+			}
+		}
+	}
+	return parsed.Format()
+}
+
+// resolveSecret asks the provider named by ref for its secret.
+func resolveSecret(ctx context.Context, providers map[string]SecretProvider, ref string) (string, error) {
+	provider, name, ok := strings.Cut(strings.TrimPrefix(ref, SecretScheme), "/")
+	if !ok || provider == "" || name == "" {
+		return "", fmt.Errorf("secret reference %q must have the form %s<provider>/<name>", ref, SecretScheme)
+	}
+	p, ok := providers[provider]
+	if !ok || p == nil {
+		return "", fmt.Errorf("secret reference %q: unknown secret provider %q", ref, provider)
+	}
+	value, err := p.Secret(ctx, name)
+	if err != nil {
+		return "", fmt.Errorf("secret reference %q: %w", ref, err)
+	}
+	if value == "" {
+		return "", fmt.Errorf("secret reference %q resolved to an empty value", ref)
+	}
+	return value, nil
+}
@@ -0,0 +1,138 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecretProviders(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "db_password"), []byte("s3cret\n"), 0o600))
+
+	file := FileSecretProvider{Dir: dir}
+	value, err := file.Secret(ctx, "db_password")
+	require.NoError(t, err)
+	require.Equal(t, "s3cret", value, "the trailing newline is trimmed")
+
+	_, err = file.Secret(ctx, "../outside")
+	require.ErrorIs(t, err, os.ErrNotExist, "names cannot leave Dir")
+
+	t.Setenv("TEST_SECRET_VALUE", "from-env")
+	value, err = EnvSecretProvider{}.Secret(ctx, "TEST_SECRET_VALUE")
+	require.NoError(t, err)
+	require.Equal(t, "from-env", value)
+
+	_, err = EnvSecretProvider{}.Secret(ctx, "TEST_SECRET_SURELY_UNSET")
+	require.EqualError(t, err, `environment variable "TEST_SECRET_SURELY_UNSET" is not set`)
+
+	cfg := New()
+	require.Len(t, SecretProviders(cfg), 2, "file and env are built in")
+	WithSecretProvider(SecretProviderFile, file)(cfg)
+	WithSecretProvider("vault", SecretProviderFunc(func(context.Context, string) (string, error) {
+		return "", nil
+	}))(cfg)
+	providers := SecretProviders(cfg)
+	require.Len(t, providers, 3)
+	require.Equal(t, file, providers[SecretProviderFile])
+}
+
+func TestConfig_ResolveDSN(t *testing.T) {
+	ctx := context.Background()
+	secrets := map[string]string{"password": "it's secret", "host": "db.internal", "dsn": "app.db"}
+	cfg := New()
+	WithSecretProvider("test", SecretProviderFunc(func(_ context.Context, name string) (string, error) {
+		if value, ok := secrets[name]; ok {
+			return value, nil
+		}
+		return "", errors.New("no such secret")
+	}))(cfg)
+
+	tests := []struct {
+		name, driver, dsn, want string
+	}{
+		{"without references", "gorm:postgres", "host=db password=plain", "host=db password=plain"},
+		{"whole dsn", "gorm:sqlite", "secret://test/dsn", "app.db"},
+		{
+			"postgres components", "gorm:postgres",
+			"host=secret://test/host user=app password=secret://test/password",
+			`host=db.internal user=app password='it\'s secret'`,
+		},
+		{
+			"mysql password", "gorm:mysql",
+			"app:secret://test/password@tcp(db:3306)/orders",
+			"app:it's secret@tcp(db:3306)/orders",
+		},
+		{"sqlite parameter", "gorm:sqlite", "app.db?_auth_pass=secret://test/password", "app.db?_auth_pass=it%27s+secret"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg.Driver = tt.driver
+			got, err := cfg.ResolveDSN(ctx, tt.dsn)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestConfig_ResolveDSN_Errors(t *testing.T) {
+	ctx := context.Background()
+	t.Setenv("TEST_SECRET_EMPTY", "")
+	t.Setenv("TEST_SECRET_PASSWORD", "hunter2")
+
+	tests := []struct {
+		name, driver, dsn, errMsg string
+	}{
+		{"unknown provider", "gorm:sqlite", "secret://vault/db", `unknown secret provider "vault"`},
+		{"malformed reference", "gorm:sqlite", "secret://env", "must have the form secret://<provider>/<name>"},
+		{"missing secret", "gorm:postgres", "host=db password=secret://env/TEST_SECRET_UNSET", `"TEST_SECRET_UNSET" is not set`},
+		{"empty secret", "gorm:sqlite", "secret://env/TEST_SECRET_EMPTY", "resolved to an empty value"},
+		{"unknown dialect", "custom", "user=secret://env/TEST_SECRET_PASSWORD", "cannot resolve the secrets of the dsn"},
+		{"missing file", "gorm:sqlite", "secret://file/surely/missing/secret", "failed to read secret file"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{Driver: tt.driver}
+			_, err := cfg.ResolveDSN(ctx, tt.dsn)
+			require.ErrorContains(t, err, tt.errMsg)
+			require.NotContains(t, err.Error(), "hunter2")
+		})
+	}
+}
+
+func TestConfig_ValidateSecretReference(t *testing.T) {
+	cfg := &Config{Driver: "gorm:mysql", DSN: "secret://file/run/secrets/mysql_dsn"}
+	require.NoError(t, cfg.Validate(), "a DSN given as a reference is validated once resolved")
+
+	cfg.DSN = "app:secret://env/DB_PASSWORD@tcp(db:3306)/orders"
+	require.NoError(t, cfg.Validate())
+}
+
+func TestParse_SecretReferences(t *testing.T) {
+	t.Setenv("TEST_SECRET_DB_PASSWORD", "p@ss word")
+	database, err := Parse([]byte(`
+database:
+  connections:
+    primary:
+      driver: gorm:postgres
+      host: db
+      user: app
+      password: secret://env/TEST_SECRET_DB_PASSWORD
+      database: orders
+`), WithEnvironment(""))
+	require.NoError(t, err)
+
+	primary := database.Connections["primary"]
+	require.Equal(t, "host=db user=app password=secret://env/TEST_SECRET_DB_PASSWORD dbname=orders", primary.DSN,
+		"references are kept in the config and resolved when connecting")
+	require.NoError(t, primary.Validate())
+
+	dsn, err := primary.ResolveDSN(context.Background(), primary.DSN)
+	require.NoError(t, err)
+	require.Equal(t, "host=db user=app password='p@ss word' dbname=orders", dsn)
+}
@@ -11,4 +11,22 @@ type (
 		Connect(*config.Config) (Connection, error)
 		Name() string
 	}
+
+	// DialectInfo describes the SQL specifics of a driver.
+	DialectInfo struct {
+		// MigrationDriver is the database/sql and golang-migrate driver name used
+		// by the migration package, e.g. "postgres". It is empty when the driver
+		// does not support migrations.
+		MigrationDriver string
+		// TruncateStatement returns the statement that empties table.
+		TruncateStatement func(table string) string
+	}
+
+	// Dialects is implemented by adapters that serve several SQL dialects, so
+	// that the migration and testing packages can look their specifics up.
+	Dialects interface {
+		// DialectInfo returns the specifics of driver, such as "gorm:postgres",
+		// and false when the adapter does not know it.
+		DialectInfo(driver string) (DialectInfo, bool)
+	}
 )
@@ -0,0 +1,44 @@
+package contract
+
+// Optional database features reported by Capabilities.
+const (
+	// CapabilitySavepoints is nested transactions through SAVEPOINT.
+	CapabilitySavepoints Capability = "savepoints"
+	// CapabilityReturning is INSERT, UPDATE and DELETE ... RETURNING.
+	CapabilityReturning Capability = "returning"
+	// CapabilityUpsert is a single-statement insert-or-update, such as
+	// ON CONFLICT or ON DUPLICATE KEY UPDATE.
+	CapabilityUpsert Capability = "upsert"
+	// CapabilityJSON is JSON columns that can be queried with JSON functions.
+	CapabilityJSON Capability = "json"
+	// CapabilityRowLocking is SELECT ... FOR UPDATE and FOR SHARE.
+	CapabilityRowLocking Capability = "row_locking"
+	// CapabilityMigrations is support by the migration package.
+	CapabilityMigrations Capability = "migrations"
+)
+
+type (
+	// Capability names an optional database feature.
+	Capability string
+
+	// Capabilities is implemented by adapters that can tell which optional
+	// features a driver supports, so that callers can fall back to another
+	// strategy instead of failing at runtime with an SQL error.
+	Capabilities interface {
+		// Supports reports whether driver, such as "gorm:postgres", supports
+		// capability. Unknown drivers and capabilities are not supported.
+		Supports(driver string, capability Capability) bool
+	}
+)
+
+// AllCapabilities returns every capability defined by this package.
+func AllCapabilities() []Capability {
+	return []Capability{
+		CapabilitySavepoints,
+		CapabilityReturning,
+		CapabilityUpsert,
+		CapabilityJSON,
+		CapabilityRowLocking,
+		CapabilityMigrations,
+	}
+}
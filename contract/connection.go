@@ -9,9 +9,10 @@ type (
 	Connection interface {
 		GetConnection() any
 		Ping(context.Context) error
+		Stats() Stats
 		Close() error
 		NewRepository(Model) (Repository, error)
-		Transaction(context.Context, func(Connection) error) error
+		Transaction(context.Context, func(Connection) error, ...TxOption) error
 		Select(context.Context, string, ...any) ([]map[string]any, error)
 		Statement(context.Context, string, ...any) (sql.Result, error)
 	}
@@ -5,6 +5,8 @@ type (
 		Up() error
 		Down(int) error
 		Fresh() error
+		// Pending returns the number of migrations that have not been applied yet.
+		Pending() (int, error)
 		Close() (sourceErr, dbErr error)
 	}
 )
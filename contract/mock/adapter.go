@@ -0,0 +1,71 @@
+package mock
+
+import (
+	"github.com/next-trace/scg-database/config"
+	"github.com/next-trace/scg-database/contract"
+)
+
+type (
+	// DBAdapter is a fake contract.DBAdapter.
+	DBAdapter struct {
+		fake
+	}
+
+	// Dialects is a fake contract.Dialects.
+	Dialects struct {
+		fake
+	}
+
+	// Capabilities is a fake contract.Capabilities.
+	Capabilities struct {
+		fake
+	}
+)
+
+var (
+	_ contract.DBAdapter    = (*DBAdapter)(nil)
+	_ contract.Dialects     = (*Dialects)(nil)
+	_ contract.Capabilities = (*Capabilities)(nil)
+)
+
+// NewDBAdapter returns a DBAdapter that fails t on unexpected calls and
+// verifies its expectations when t ends.
+func NewDBAdapter(t TestingT) *DBAdapter {
+	m := &DBAdapter{}
+	m.register(t)
+	return m
+}
+
+// NewDialects returns a Dialects that fails t on unexpected calls and verifies
+// its expectations when t ends.
+func NewDialects(t TestingT) *Dialects {
+	m := &Dialects{}
+	m.register(t)
+	return m
+}
+
+// NewCapabilities returns a Capabilities that fails t on unexpected calls and
+// verifies its expectations when t ends.
+func NewCapabilities(t TestingT) *Capabilities {
+	m := &Capabilities{}
+	m.register(t)
+	return m
+}
+
+func (m *DBAdapter) Connect(cfg *config.Config) (contract.Connection, error) {
+	args := m.Called(cfg)
+	return result[contract.Connection](&m.fake, args, 0), result[error](&m.fake, args, 1)
+}
+
+func (m *DBAdapter) Name() string {
+	return result[string](&m.fake, m.Called(), 0)
+}
+
+func (m *Dialects) DialectInfo(driver string) (contract.DialectInfo, bool) {
+	args := m.Called(driver)
+	return result[contract.DialectInfo](&m.fake, args, 0), result[bool](&m.fake, args, 1)
+}
+
+func (m *Capabilities) Supports(driver string, capability contract.Capability) bool {
+	return result[bool](&m.fake, m.Called(driver, capability), 0)
+}
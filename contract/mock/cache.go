@@ -0,0 +1,42 @@
+package mock
+
+import (
+	"time"
+
+	"github.com/next-trace/scg-database/contract"
+)
+
+type (
+	// Cache is a fake contract.Cache.
+	Cache struct {
+		fake
+	}
+)
+
+//nolint:grouper // Only One Global Variable
+var _ contract.Cache = (*Cache)(nil)
+
+// NewCache returns a Cache that fails t on unexpected calls and verifies its
+// expectations when t ends.
+func NewCache(t TestingT) *Cache {
+	m := &Cache{}
+	m.register(t)
+	return m
+}
+
+func (m *Cache) Get(key string) (any, bool) {
+	args := m.Called(key)
+	return result[any](&m.fake, args, 0), result[bool](&m.fake, args, 1)
+}
+
+func (m *Cache) Set(key string, value any, ttl time.Duration) error {
+	return result[error](&m.fake, m.Called(key, value, ttl), 0)
+}
+
+func (m *Cache) Delete(key string) error {
+	return result[error](&m.fake, m.Called(key), 0)
+}
+
+func (m *Cache) Flush() error {
+	return result[error](&m.fake, m.Called(), 0)
+}
@@ -0,0 +1,97 @@
+package mock
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/next-trace/scg-database/contract"
+)
+
+type (
+	// Connection is a fake contract.Connection.
+	Connection struct {
+		fake
+	}
+
+	// TxHooks is a fake contract.TxHooks. It records the hooks without running
+	// them; use Run to call them.
+	TxHooks struct {
+		fake
+	}
+)
+
+var (
+	_ contract.Connection = (*Connection)(nil)
+	_ contract.TxHooks    = (*TxHooks)(nil)
+)
+
+// NewConnection returns a Connection that fails t on unexpected calls and
+// verifies its expectations when t ends.
+func NewConnection(t TestingT) *Connection {
+	m := &Connection{}
+	m.register(t)
+	return m
+}
+
+// NewTxHooks returns a TxHooks that fails t on unexpected calls and verifies
+// its expectations when t ends.
+func NewTxHooks(t TestingT) *TxHooks {
+	m := &TxHooks{}
+	m.register(t)
+	return m
+}
+
+func (m *Connection) GetConnection() any {
+	return result[any](&m.fake, m.Called(), 0)
+}
+
+func (m *Connection) Ping(ctx context.Context) error {
+	return result[error](&m.fake, m.Called(ctx), 0)
+}
+
+func (m *Connection) Stats() contract.Stats {
+	return result[contract.Stats](&m.fake, m.Called(), 0)
+}
+
+func (m *Connection) Close() error {
+	return result[error](&m.fake, m.Called(), 0)
+}
+
+func (m *Connection) NewRepository(model contract.Model) (contract.Repository, error) {
+	args := m.Called(model)
+	return result[contract.Repository](&m.fake, args, 0), result[error](&m.fake, args, 1)
+}
+
+// Transaction records the call with ctx and fn; the options are not matched.
+// Without a canned error, or with Return(nil), it runs fn with the Connection
+// itself and returns fn's error. With a non-nil error, fn is not run and the
+// error is returned.
+func (m *Connection) Transaction(
+	ctx context.Context,
+	fn func(contract.Connection) error,
+	_ ...contract.TxOption,
+) error {
+	args := m.Called(ctx, fn)
+	if len(args) == 0 || args.Get(0) == nil {
+		return fn(m)
+	}
+	return result[error](&m.fake, args, 0)
+}
+
+func (m *Connection) Select(ctx context.Context, query string, args ...any) ([]map[string]any, error) {
+	results := m.Called(arguments([]any{ctx, query}, args)...)
+	return result[[]map[string]any](&m.fake, results, 0), result[error](&m.fake, results, 1)
+}
+
+func (m *Connection) Statement(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	results := m.Called(arguments([]any{ctx, query}, args)...)
+	return result[sql.Result](&m.fake, results, 0), result[error](&m.fake, results, 1)
+}
+
+func (m *TxHooks) OnCommit(fn func()) {
+	m.Called(fn)
+}
+
+func (m *TxHooks) OnRollback(fn func()) {
+	m.Called(fn)
+}
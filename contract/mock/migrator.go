@@ -0,0 +1,45 @@
+package mock
+
+import (
+	"github.com/next-trace/scg-database/contract"
+)
+
+type (
+	// Migrator is a fake contract.Migrator.
+	Migrator struct {
+		fake
+	}
+)
+
+//nolint:grouper // Only One Global Variable
+var _ contract.Migrator = (*Migrator)(nil)
+
+// NewMigrator returns a Migrator that fails t on unexpected calls and verifies
+// its expectations when t ends.
+func NewMigrator(t TestingT) *Migrator {
+	m := &Migrator{}
+	m.register(t)
+	return m
+}
+
+func (m *Migrator) Up() error {
+	return result[error](&m.fake, m.Called(), 0)
+}
+
+func (m *Migrator) Down(steps int) error {
+	return result[error](&m.fake, m.Called(steps), 0)
+}
+
+func (m *Migrator) Fresh() error {
+	return result[error](&m.fake, m.Called(), 0)
+}
+
+func (m *Migrator) Pending() (int, error) {
+	args := m.Called()
+	return result[int](&m.fake, args, 0), result[error](&m.fake, args, 1)
+}
+
+func (m *Migrator) Close() (sourceErr, dbErr error) {
+	args := m.Called()
+	return result[error](&m.fake, args, 0), result[error](&m.fake, args, 1)
+}
@@ -0,0 +1,123 @@
+// Package mock provides expectation-based fakes of the contract interfaces,
+// built on github.com/stretchr/testify/mock, so that services can unit test code
+// written against contract.Connection, contract.Repository and the other
+// interfaces without hand-writing mocks:
+//
+//	repo := mock.NewRepository(t)
+//	repo.On("Where", "email = ?", "a@example.com")
+//	repo.On("First", mock.Anything).Return(&User{Name: "A"}, nil)
+//
+//	user, err := repo.Where("email = ?", "a@example.com").First(ctx)
+//
+// Every fake embeds mock.Mock: expectations are recorded per method with On,
+// arguments are compared with ==, reflect.DeepEqual or a matcher such as
+// Anything, AnythingOfType and MatchedBy, and Return cans the results. Variadic
+// arguments are passed to On one by one, as in the call.
+//
+// A result missing from Return, or given as nil, is its zero value; a result
+// of the wrong type fails the test. Fluent methods such as With, Where and
+// Limit return the fake itself unless Return gives another value, so chains
+// only need one expectation per call.
+//
+// The constructors fail the test on unexpected calls and verify that every
+// expectation was met when the test ends; use Maybe for optional calls and
+// InOrder or NotBefore to check the order of calls.
+package mock
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// Anything matches any argument.
+const Anything = mock.Anything
+
+type (
+	// Arguments are the arguments of a call, as passed to the function given to
+	// Run, or the results given to Return.
+	Arguments = mock.Arguments
+
+	// TestingT is the part of *testing.T the constructors need.
+	TestingT interface {
+		mock.TestingT
+		Cleanup(func())
+	}
+
+	// fake is embedded by every fake: the testify mock, and the test it
+	// reports to.
+	fake struct {
+		mock.Mock
+		t TestingT
+	}
+)
+
+var (
+	// AnythingOfType matches an argument whose type, as printed by %T without
+	// the leading *, is the given name, e.g. "*context.cancelCtx".
+	AnythingOfType = mock.AnythingOfType
+	// MatchedBy matches an argument for which fn, a func(T) bool, returns true.
+	MatchedBy = mock.MatchedBy
+	// InOrder requires the calls to happen in the given order.
+	InOrder = mock.InOrder
+)
+
+// register makes f fail t on unexpected calls and on canned results of the
+// wrong type, and verifies its expectations when t ends.
+func (f *fake) register(t TestingT) {
+	f.t = t
+	f.Test(t)
+	t.Cleanup(func() { f.AssertExpectations(t) })
+}
+
+// result returns the i-th canned result, or the zero value of T when it is
+// missing or nil. A result of another type fails the test.
+func result[T any](f *fake, args mock.Arguments, i int) T {
+	var zero T
+	if i >= len(args) || args.Get(i) == nil {
+		return zero
+	}
+	value, ok := args.Get(i).(T)
+	if !ok {
+		f.errorf("mock: result %d is a %T, not a %s", i, args.Get(i), reflect.TypeFor[T]())
+	}
+	return value
+}
+
+// chain returns the result of a fluent call: the canned value when there is
+// one, and self otherwise. A canned value of another type fails the test.
+func chain[T any](f *fake, args mock.Arguments, self T) T {
+	if len(args) == 0 || args.Get(0) == nil {
+		return self
+	}
+	value, ok := args.Get(0).(T)
+	if !ok {
+		f.errorf("mock: result 0 is a %T, not a %s", args.Get(0), reflect.TypeFor[T]())
+		return self
+	}
+	return value
+}
+
+// errorf reports a misuse of f to its test. Fakes built without a constructor
+// have no test, and panic instead.
+func (f *fake) errorf(format string, args ...any) {
+	if f.t == nil {
+		panic(fmt.Sprintf(format, args...))
+	}
+	if h, ok := f.t.(interface{ Helper() }); ok {
+		h.Helper()
+	}
+	f.t.Errorf(format, args...)
+}
+
+// arguments returns first followed by the elements of variadic, so that
+// variadic arguments are matched one by one.
+func arguments[T any](first []any, variadic []T) []any {
+	args := make([]any, 0, len(first)+len(variadic))
+	args = append(args, first...)
+	for _, arg := range variadic {
+		args = append(args, arg)
+	}
+	return args
+}
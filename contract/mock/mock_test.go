@@ -0,0 +1,270 @@
+package mock
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/next-trace/scg-database/contract"
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	// recorder is a TestingT that records failures instead of failing the test.
+	recorder struct {
+		errors   []string
+		failed   bool
+		cleanups []func()
+	}
+
+	testUser struct {
+		contract.BaseModel
+		Name string
+	}
+)
+
+func (r *recorder) Logf(string, ...any) {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, format)
+}
+
+// FailNow stops the goroutine, as testing.T.FailNow does.
+func (r *recorder) FailNow() {
+	r.failed = true
+	runtime.Goexit()
+}
+
+func (r *recorder) Cleanup(fn func()) { r.cleanups = append(r.cleanups, fn) }
+
+// finish runs the cleanups, as the end of a test does.
+func (r *recorder) finish() {
+	for _, fn := range r.cleanups {
+		fn()
+	}
+}
+
+// call runs fn in its own goroutine, so that FailNow does not stop the test.
+func call(fn func()) {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fn()
+	}()
+	<-done
+}
+
+func TestRepository_Chain(t *testing.T) {
+	repo := NewRepository(t)
+	ctx := t.Context()
+	want := &testUser{Name: "A"}
+
+	repo.On("With", "Posts", "Profile")
+	repo.On("Where", "age > ?", 18)
+	repo.On("OrderBy", "name", "asc")
+	repo.On("Limit", 10)
+	repo.On("First", Anything).Return(want, nil)
+
+	got, err := repo.With("Posts", "Profile").Where("age > ?", 18).OrderBy("name", "asc").Limit(10).First(ctx)
+	require.NoError(t, err)
+	require.Same(t, want, got)
+
+	scoped := NewRepository(t)
+	repo.On("Unscoped").Return(scoped)
+	require.Same(t, scoped, repo.Unscoped(), "a canned repository replaces the fake")
+}
+
+func TestRepository_Results(t *testing.T) {
+	repo := NewRepository(t)
+	ctx := t.Context()
+	errBoom := errors.New("boom")
+
+	repo.On("Find", Anything, 1).Return(nil, nil)
+	repo.On("FindOrFail", Anything, 1).Return(nil, errBoom)
+	repo.On("Get", Anything).Return([]contract.Model{&testUser{Name: "A"}}, nil)
+	repo.On("Create", Anything, MatchedBy(func(u *testUser) bool { return u.Name == "B" }))
+	repo.On("Delete", Anything, AnythingOfType("*mock.testUser")).Return(errBoom).Once()
+
+	found, err := repo.Find(ctx, 1)
+	require.NoError(t, err)
+	require.Nil(t, found)
+	_, err = repo.FindOrFail(ctx, 1)
+	require.ErrorIs(t, err, errBoom)
+
+	all, err := repo.Get(ctx)
+	require.NoError(t, err)
+	require.Len(t, all, 1)
+
+	require.NoError(t, repo.Create(ctx, &testUser{Name: "B"}), "results missing from Return are zero")
+	require.ErrorIs(t, repo.Delete(ctx, &testUser{}), errBoom)
+	repo.AssertNumberOfCalls(t, "Create", 1)
+}
+
+func TestQueryBuilder(t *testing.T) {
+	qb := NewQueryBuilder(t)
+	ctx := t.Context()
+
+	qb.On("Where", "name IN ?", []any{"A", "B"})
+	qb.On("WhereNull", "deleted_at")
+	qb.On("Count", Anything).Return(int64(2), nil)
+	qb.On("Find", Anything, Anything).Run(func(args Arguments) {
+		*args[1].(*[]testUser) = []testUser{{Name: "A"}}
+	}).Return(nil)
+	qb.On("ToSQL").Return("SELECT 1", []any{}, nil)
+
+	count, err := qb.Where("name IN ?", []any{"A", "B"}).WhereNull("deleted_at").Count(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), count)
+
+	var users []testUser
+	require.NoError(t, qb.Find(ctx, &users))
+	require.Equal(t, "A", users[0].Name)
+
+	query, _, err := qb.ToSQL()
+	require.NoError(t, err)
+	require.Equal(t, "SELECT 1", query)
+
+	repo := NewRepository(t)
+	repo.On("QueryBuilder").Return(qb)
+	require.Same(t, qb, repo.QueryBuilder())
+}
+
+func TestConnection_Transaction(t *testing.T) {
+	conn := NewConnection(t)
+	repo := NewRepository(t)
+	ctx := t.Context()
+	errBoom := errors.New("boom")
+
+	conn.On("Transaction", Anything, Anything).Once()
+	conn.On("NewRepository", &testUser{}).Return(repo, nil)
+	repo.On("Create", Anything, Anything).Return(errBoom)
+
+	err := conn.Transaction(ctx, func(tx contract.Connection) error {
+		require.Same(t, conn, tx, "without a canned error fn runs with the fake")
+		txRepo, err := tx.NewRepository(&testUser{})
+		require.NoError(t, err)
+		return txRepo.Create(ctx, &testUser{})
+	})
+	require.ErrorIs(t, err, errBoom)
+
+	ran := false
+	conn.On("Transaction", Anything, Anything).Return(nil).Once()
+	require.NoError(t, conn.Transaction(ctx, func(contract.Connection) error {
+		ran = true
+		return nil
+	}))
+	require.True(t, ran, "a nil canned error runs fn too")
+
+	conn.On("Transaction", Anything, Anything).Return(errBoom).Once()
+	err = conn.Transaction(ctx, func(contract.Connection) error {
+		t.Fatal("fn should not run when the error is canned")
+		return nil
+	})
+	require.ErrorIs(t, err, errBoom)
+}
+
+func TestConnection_RawQueries(t *testing.T) {
+	conn := NewConnection(t)
+	ctx := t.Context()
+
+	conn.On("Select", Anything, "SELECT * FROM users WHERE id = ?", 1).Return([]map[string]any{{"id": 1}}, nil)
+	conn.On("Stats").Return(contract.Stats{Pools: []contract.PoolStats{{Name: "primary"}}})
+
+	rows, err := conn.Select(ctx, "SELECT * FROM users WHERE id = ?", 1)
+	require.NoError(t, err)
+	require.Equal(t, []map[string]any{{"id": 1}}, rows)
+	require.Equal(t, "primary", conn.Stats().Pools[0].Name)
+}
+
+func TestVerification(t *testing.T) {
+	t.Run("unmet expectations fail at the end of the test", func(t *testing.T) {
+		rec := &recorder{}
+		repo := NewRepository(rec)
+		repo.On("Find", Anything, 1).Return(nil, nil)
+		repo.On("Count", Anything).Maybe()
+
+		rec.finish()
+		require.NotEmpty(t, rec.errors)
+		require.Contains(t, strings.Join(rec.errors, "\n"), "FAIL")
+	})
+
+	t.Run("unexpected calls fail", func(t *testing.T) {
+		rec := &recorder{}
+		conn := NewConnection(rec)
+		call(func() { _ = conn.Ping(context.Background()) })
+		require.True(t, rec.failed)
+	})
+
+	t.Run("calls out of order fail", func(t *testing.T) {
+		rec := &recorder{}
+		migrator := NewMigrator(rec)
+		InOrder(
+			migrator.On("Up").Return(nil),
+			migrator.On("Close").Return(nil, nil),
+		)
+		call(func() { _, _ = migrator.Close() })
+		require.True(t, rec.failed)
+	})
+
+	t.Run("results of the wrong type fail", func(t *testing.T) {
+		rec := &recorder{}
+		repo := NewRepository(rec)
+		repo.On("Find", Anything, 1).Return(&testUser{}, "not an error")
+		repo.On("Limit", 1).Return("not a repository")
+
+		found, err := repo.Find(context.Background(), 1)
+		require.NotNil(t, found, "results of the right type are kept")
+		require.NoError(t, err)
+		require.Same(t, repo, repo.Limit(1))
+		require.Equal(t, []string{"mock: result %d is a %T, not a %s", "mock: result 0 is a %T, not a %s"}, rec.errors)
+	})
+
+	t.Run("results of the wrong type panic without a test", func(t *testing.T) {
+		conn := &Connection{}
+		conn.On("Ping", Anything).Return(42)
+		require.PanicsWithValue(t, "mock: result 0 is a int, not a error", func() {
+			_ = conn.Ping(context.Background())
+		})
+	})
+
+	t.Run("calls in order pass", func(t *testing.T) {
+		migrator := NewMigrator(t)
+		InOrder(
+			migrator.On("Up").Return(nil),
+			migrator.On("Pending").Return(0, nil),
+		)
+		require.NoError(t, migrator.Up())
+		pending, err := migrator.Pending()
+		require.NoError(t, err)
+		require.Zero(t, pending)
+	})
+}
+
+func TestFakes(t *testing.T) {
+	model := NewModel(t)
+	model.On("TableName").Return("users")
+	model.On("Relationships").Return(map[string]contract.Relationship{})
+
+	adapter := NewDBAdapter(t)
+	adapter.On("Name").Return("fake")
+	capabilities := NewCapabilities(t)
+	capabilities.On("Supports", "fake", contract.CapabilityJSON).Return(true)
+	cache := NewCache(t)
+	cache.On("Get", "key").Return("value", true)
+	hooks := NewTxHooks(t)
+	hooks.On("OnCommit", Anything).Run(func(args Arguments) { args[0].(func())() })
+
+	require.Equal(t, "users", model.TableName())
+	require.Empty(t, model.Relationships())
+	require.Equal(t, "fake", adapter.Name())
+	require.True(t, capabilities.Supports("fake", contract.CapabilityJSON))
+	value, ok := cache.Get("key")
+	require.True(t, ok)
+	require.Equal(t, "value", value)
+
+	committed := false
+	hooks.OnCommit(func() { committed = true })
+	require.True(t, committed)
+}
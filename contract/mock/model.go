@@ -0,0 +1,108 @@
+package mock
+
+import (
+	"time"
+
+	"github.com/next-trace/scg-database/contract"
+)
+
+type (
+	// Model is a fake contract.Model. It also implements contract.SoftDelete
+	// and contract.Timestamps; only the methods that are called need
+	// expectations.
+	Model struct {
+		fake
+	}
+
+	// Relationship is a fake contract.Relationship.
+	Relationship struct {
+		fake
+	}
+)
+
+var (
+	_ contract.Model        = (*Model)(nil)
+	_ contract.SoftDelete   = (*Model)(nil)
+	_ contract.Timestamps   = (*Model)(nil)
+	_ contract.Relationship = (*Relationship)(nil)
+)
+
+// NewModel returns a Model that fails t on unexpected calls and verifies its
+// expectations when t ends.
+func NewModel(t TestingT) *Model {
+	m := &Model{}
+	m.register(t)
+	return m
+}
+
+// NewRelationship returns a Relationship that fails t on unexpected calls and
+// verifies its expectations when t ends.
+func NewRelationship(t TestingT) *Relationship {
+	m := &Relationship{}
+	m.register(t)
+	return m
+}
+
+func (m *Model) PrimaryKey() string {
+	return result[string](&m.fake, m.Called(), 0)
+}
+
+func (m *Model) TableName() string {
+	return result[string](&m.fake, m.Called(), 0)
+}
+
+func (m *Model) GetID() any {
+	return result[any](&m.fake, m.Called(), 0)
+}
+
+func (m *Model) SetID(id any) {
+	m.Called(id)
+}
+
+func (m *Model) Relationships() map[string]contract.Relationship {
+	return result[map[string]contract.Relationship](&m.fake, m.Called(), 0)
+}
+
+func (m *Model) GetDeletedAt() *time.Time {
+	return result[*time.Time](&m.fake, m.Called(), 0)
+}
+
+func (m *Model) SetDeletedAt(t *time.Time) {
+	m.Called(t)
+}
+
+func (m *Model) GetCreatedAt() time.Time {
+	return result[time.Time](&m.fake, m.Called(), 0)
+}
+
+func (m *Model) GetUpdatedAt() time.Time {
+	return result[time.Time](&m.fake, m.Called(), 0)
+}
+
+func (m *Model) SetCreatedAt(t time.Time) {
+	m.Called(t)
+}
+
+func (m *Model) SetUpdatedAt(t time.Time) {
+	m.Called(t)
+}
+
+func (m *Relationship) Type() contract.RelationshipType {
+	return result[contract.RelationshipType](&m.fake, m.Called(), 0)
+}
+
+func (m *Relationship) RelatedModel() contract.Model {
+	return result[contract.Model](&m.fake, m.Called(), 0)
+}
+
+func (m *Relationship) ForeignKey() string {
+	return result[string](&m.fake, m.Called(), 0)
+}
+
+func (m *Relationship) OwnerKey() string {
+	return result[string](&m.fake, m.Called(), 0)
+}
+
+func (m *Relationship) ManyToManyJoinTable() string {
+	return result[string](&m.fake, m.Called(), 0)
+}
@@ -0,0 +1,29 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/next-trace/scg-database/contract"
+)
+
+type (
+	// QueryObserver is a fake contract.QueryObserver.
+	QueryObserver struct {
+		fake
+	}
+)
+
+//nolint:grouper // Only One Global Variable
+var _ contract.QueryObserver = (*QueryObserver)(nil)
+
+// NewQueryObserver returns a QueryObserver that fails t on unexpected calls
+// and verifies its expectations when t ends.
+func NewQueryObserver(t TestingT) *QueryObserver {
+	m := &QueryObserver{}
+	m.register(t)
+	return m
+}
+
+func (m *QueryObserver) ObserveQuery(ctx context.Context, event contract.QueryEvent) {
+	m.Called(ctx, event)
+}
@@ -0,0 +1,235 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/next-trace/scg-database/contract"
+)
+
+type (
+	// QueryBuilder is a fake contract.QueryBuilder.
+	QueryBuilder struct {
+		fake
+	}
+
+	// QueryBuilderFactory is a fake contract.QueryBuilderFactory.
+	QueryBuilderFactory struct {
+		fake
+	}
+
+	// QueryBuilderRegistry is a fake contract.QueryBuilderRegistry.
+	QueryBuilderRegistry struct {
+		fake
+	}
+)
+
+var (
+	_ contract.QueryBuilder         = (*QueryBuilder)(nil)
+	_ contract.QueryBuilderFactory  = (*QueryBuilderFactory)(nil)
+	_ contract.QueryBuilderRegistry = (*QueryBuilderRegistry)(nil)
+)
+
+// NewQueryBuilder returns a QueryBuilder that fails t on unexpected calls and
+// verifies its expectations when t ends.
+func NewQueryBuilder(t TestingT) *QueryBuilder {
+	m := &QueryBuilder{}
+	m.register(t)
+	return m
+}
+
+// NewQueryBuilderFactory returns a QueryBuilderFactory that fails t on
+// unexpected calls and verifies its expectations when t ends.
+func NewQueryBuilderFactory(t TestingT) *QueryBuilderFactory {
+	m := &QueryBuilderFactory{}
+	m.register(t)
+	return m
+}
+
+// NewQueryBuilderRegistry returns a QueryBuilderRegistry that fails t on
+// unexpected calls and verifies its expectations when t ends.
+func NewQueryBuilderRegistry(t TestingT) *QueryBuilderRegistry {
+	m := &QueryBuilderRegistry{}
+	m.register(t)
+	return m
+}
+
+// Query building methods
+
+func (m *QueryBuilder) Select(columns ...string) contract.QueryBuilder {
+	return chain[contract.QueryBuilder](&m.fake, m.Called(arguments(nil, columns)...), m)
+}
+
+func (m *QueryBuilder) Where(condition string, args ...any) contract.QueryBuilder {
+	return chain[contract.QueryBuilder](&m.fake, m.Called(arguments([]any{condition}, args)...), m)
+}
+
+func (m *QueryBuilder) WhereIn(column string, values []any) contract.QueryBuilder {
+	return chain[contract.QueryBuilder](&m.fake, m.Called(column, values), m)
+}
+
+func (m *QueryBuilder) WhereNotIn(column string, values []any) contract.QueryBuilder {
+	return chain[contract.QueryBuilder](&m.fake, m.Called(column, values), m)
+}
+
+func (m *QueryBuilder) WhereNull(column string) contract.QueryBuilder {
+	return chain[contract.QueryBuilder](&m.fake, m.Called(column), m)
+}
+
+func (m *QueryBuilder) WhereNotNull(column string) contract.QueryBuilder {
+	return chain[contract.QueryBuilder](&m.fake, m.Called(column), m)
+}
+
+func (m *QueryBuilder) WhereBetween(column string, start, end any) contract.QueryBuilder {
+	return chain[contract.QueryBuilder](&m.fake, m.Called(column, start, end), m)
+}
+
+func (m *QueryBuilder) OrWhere(condition string, args ...any) contract.QueryBuilder {
+	return chain[contract.QueryBuilder](&m.fake, m.Called(arguments([]any{condition}, args)...), m)
+}
+
+// Join methods
+
+func (m *QueryBuilder) Join(table, condition string) contract.QueryBuilder {
+	return chain[contract.QueryBuilder](&m.fake, m.Called(table, condition), m)
+}
+
+func (m *QueryBuilder) LeftJoin(table, condition string) contract.QueryBuilder {
+	return chain[contract.QueryBuilder](&m.fake, m.Called(table, condition), m)
+}
+
+func (m *QueryBuilder) RightJoin(table, condition string) contract.QueryBuilder {
+	return chain[contract.QueryBuilder](&m.fake, m.Called(table, condition), m)
+}
+
+func (m *QueryBuilder) InnerJoin(table, condition string) contract.QueryBuilder {
+	return chain[contract.QueryBuilder](&m.fake, m.Called(table, condition), m)
+}
+
+// Ordering and grouping
+
+func (m *QueryBuilder) OrderBy(column, direction string) contract.QueryBuilder {
+	return chain[contract.QueryBuilder](&m.fake, m.Called(column, direction), m)
+}
+
+func (m *QueryBuilder) GroupBy(columns ...string) contract.QueryBuilder {
+	return chain[contract.QueryBuilder](&m.fake, m.Called(arguments(nil, columns)...), m)
+}
+
+func (m *QueryBuilder) Having(condition string, args ...any) contract.QueryBuilder {
+	return chain[contract.QueryBuilder](&m.fake, m.Called(arguments([]any{condition}, args)...), m)
+}
+
+// Limiting and pagination
+
+func (m *QueryBuilder) Limit(limit int) contract.QueryBuilder {
+	return chain[contract.QueryBuilder](&m.fake, m.Called(limit), m)
+}
+
+func (m *QueryBuilder) Offset(offset int) contract.QueryBuilder {
+	return chain[contract.QueryBuilder](&m.fake, m.Called(offset), m)
+}
+
+// Relationships
+
+func (m *QueryBuilder) With(relations ...string) contract.QueryBuilder {
+	return chain[contract.QueryBuilder](&m.fake, m.Called(arguments(nil, relations)...), m)
+}
+
+func (m *QueryBuilder) WithCount(relations ...string) contract.QueryBuilder {
+	return chain[contract.QueryBuilder](&m.fake, m.Called(arguments(nil, relations)...), m)
+}
+
+// Scopes
+
+func (m *QueryBuilder) Scoped() contract.QueryBuilder {
+	return chain[contract.QueryBuilder](&m.fake, m.Called(), m)
+}
+
+func (m *QueryBuilder) Unscoped() contract.QueryBuilder {
+	return chain[contract.QueryBuilder](&m.fake, m.Called(), m)
+}
+
+// Execution methods record the call; use Run to fill dest.
+
+func (m *QueryBuilder) Find(ctx context.Context, dest any) error {
+	return result[error](&m.fake, m.Called(ctx, dest), 0)
+}
+
+func (m *QueryBuilder) First(ctx context.Context, dest any) error {
+	return result[error](&m.fake, m.Called(ctx, dest), 0)
+}
+
+func (m *QueryBuilder) Get(ctx context.Context, dest any) error {
+	return result[error](&m.fake, m.Called(ctx, dest), 0)
+}
+
+func (m *QueryBuilder) Count(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return result[int64](&m.fake, args, 0), result[error](&m.fake, args, 1)
+}
+
+func (m *QueryBuilder) Exists(ctx context.Context) (bool, error) {
+	args := m.Called(ctx)
+	return result[bool](&m.fake, args, 0), result[error](&m.fake, args, 1)
+}
+
+// Mutation methods
+
+func (m *QueryBuilder) Create(ctx context.Context, value any) error {
+	return result[error](&m.fake, m.Called(ctx, value), 0)
+}
+
+func (m *QueryBuilder) Update(ctx context.Context, values any) error {
+	return result[error](&m.fake, m.Called(ctx, values), 0)
+}
+
+func (m *QueryBuilder) Delete(ctx context.Context) error {
+	return result[error](&m.fake, m.Called(ctx), 0)
+}
+
+// Raw query methods
+
+func (m *QueryBuilder) Raw(sql string, args ...any) contract.QueryBuilder {
+	return chain[contract.QueryBuilder](&m.fake, m.Called(arguments([]any{sql}, args)...), m)
+}
+
+func (m *QueryBuilder) Exec(ctx context.Context, sql string, args ...any) error {
+	return result[error](&m.fake, m.Called(arguments([]any{ctx, sql}, args)...), 0)
+}
+
+// Utility methods
+
+func (m *QueryBuilder) ToSQL() (string, []any, error) {
+	args := m.Called()
+	return result[string](&m.fake, args, 0), result[[]any](&m.fake, args, 1), result[error](&m.fake, args, 2)
+}
+
+func (m *QueryBuilder) Clone() contract.QueryBuilder {
+	return chain[contract.QueryBuilder](&m.fake, m.Called(), m)
+}
+
+func (m *QueryBuilder) Reset() contract.QueryBuilder {
+	return chain[contract.QueryBuilder](&m.fake, m.Called(), m)
+}
+
+// NewQueryBuilder returns the canned query builder, or nil when there is none.
+func (m *QueryBuilderFactory) NewQueryBuilder(model contract.Model, connection any) contract.QueryBuilder {
+	return result[contract.QueryBuilder](&m.fake, m.Called(model, connection), 0)
+}
+
+func (m *QueryBuilderFactory) Name() string {
+	return result[string](&m.fake, m.Called(), 0)
+}
+
+func (m *QueryBuilderRegistry) Register(name string, factory contract.QueryBuilderFactory) {
+	m.Called(name, factory)
+}
+
+func (m *QueryBuilderRegistry) Get(name string) (contract.QueryBuilderFactory, error) {
+	args := m.Called(name)
+	return result[contract.QueryBuilderFactory](&m.fake, args, 0), result[error](&m.fake, args, 1)
+}
+
+func (m *QueryBuilderRegistry) List() []string {
+	return result[[]string](&m.fake, m.Called(), 0)
+}
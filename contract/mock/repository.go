@@ -0,0 +1,121 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/next-trace/scg-database/contract"
+)
+
+type (
+	// Repository is a fake contract.Repository.
+	Repository struct {
+		fake
+	}
+)
+
+//nolint:grouper // Only One Global Variable
+var _ contract.Repository = (*Repository)(nil)
+
+// NewRepository returns a Repository that fails t on unexpected calls and
+// verifies its expectations when t ends.
+func NewRepository(t TestingT) *Repository {
+	m := &Repository{}
+	m.register(t)
+	return m
+}
+
+// --- Query Building ---
+func (m *Repository) With(relations ...string) contract.Repository {
+	return chain[contract.Repository](&m.fake, m.Called(arguments(nil, relations)...), m)
+}
+
+func (m *Repository) Where(query any, args ...any) contract.Repository {
+	return chain[contract.Repository](&m.fake, m.Called(arguments([]any{query}, args)...), m)
+}
+
+func (m *Repository) Unscoped() contract.Repository {
+	return chain[contract.Repository](&m.fake, m.Called(), m)
+}
+
+func (m *Repository) Limit(limit int) contract.Repository {
+	return chain[contract.Repository](&m.fake, m.Called(limit), m)
+}
+
+func (m *Repository) Offset(offset int) contract.Repository {
+	return chain[contract.Repository](&m.fake, m.Called(offset), m)
+}
+
+func (m *Repository) OrderBy(column, direction string) contract.Repository {
+	return chain[contract.Repository](&m.fake, m.Called(column, direction), m)
+}
+
+// --- Read Operations ---
+func (m *Repository) Find(ctx context.Context, id any) (contract.Model, error) {
+	args := m.Called(ctx, id)
+	return result[contract.Model](&m.fake, args, 0), result[error](&m.fake, args, 1)
+}
+
+func (m *Repository) FindOrFail(ctx context.Context, id any) (contract.Model, error) {
+	args := m.Called(ctx, id)
+	return result[contract.Model](&m.fake, args, 0), result[error](&m.fake, args, 1)
+}
+
+func (m *Repository) First(ctx context.Context) (contract.Model, error) {
+	args := m.Called(ctx)
+	return result[contract.Model](&m.fake, args, 0), result[error](&m.fake, args, 1)
+}
+
+func (m *Repository) FirstOrFail(ctx context.Context) (contract.Model, error) {
+	args := m.Called(ctx)
+	return result[contract.Model](&m.fake, args, 0), result[error](&m.fake, args, 1)
+}
+
+func (m *Repository) Get(ctx context.Context) ([]contract.Model, error) {
+	args := m.Called(ctx)
+	return result[[]contract.Model](&m.fake, args, 0), result[error](&m.fake, args, 1)
+}
+
+// Pluck records the call; use Run to fill dest.
+func (m *Repository) Pluck(ctx context.Context, column string, dest any) error {
+	return result[error](&m.fake, m.Called(ctx, column, dest), 0)
+}
+
+// --- Write Operations ---
+func (m *Repository) Create(ctx context.Context, models ...contract.Model) error {
+	return result[error](&m.fake, m.Called(arguments([]any{ctx}, models)...), 0)
+}
+
+func (m *Repository) CreateInBatches(ctx context.Context, models []contract.Model, batchSize int) error {
+	return result[error](&m.fake, m.Called(ctx, models, batchSize), 0)
+}
+
+func (m *Repository) Update(ctx context.Context, models ...contract.Model) error {
+	return result[error](&m.fake, m.Called(arguments([]any{ctx}, models)...), 0)
+}
+
+func (m *Repository) Delete(ctx context.Context, models ...contract.Model) error {
+	return result[error](&m.fake, m.Called(arguments([]any{ctx}, models)...), 0)
+}
+
+func (m *Repository) ForceDelete(ctx context.Context, models ...contract.Model) error {
+	return result[error](&m.fake, m.Called(arguments([]any{ctx}, models)...), 0)
+}
+
+func (m *Repository) FirstOrCreate(
+	ctx context.Context,
+	condition contract.Model,
+	create ...contract.Model,
+) (contract.Model, error) {
+	args := m.Called(arguments([]any{ctx, condition}, create)...)
+	return result[contract.Model](&m.fake, args, 0), result[error](&m.fake, args, 1)
+}
+
+func (m *Repository) UpdateOrCreate(ctx context.Context, condition contract.Model, values any) (contract.Model, error) {
+	args := m.Called(ctx, condition, values)
+	return result[contract.Model](&m.fake, args, 0), result[error](&m.fake, args, 1)
+}
+
+// QueryBuilder returns the canned query builder, or nil when there is none.
+func (m *Repository) QueryBuilder() contract.QueryBuilder {
+	return result[contract.QueryBuilder](&m.fake, m.Called(), 0)
+}
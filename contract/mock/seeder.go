@@ -0,0 +1,27 @@
+package mock
+
+import (
+	"github.com/next-trace/scg-database/contract"
+)
+
+type (
+	// Seeder is a fake contract.Seeder.
+	Seeder struct {
+		fake
+	}
+)
+
+//nolint:grouper // Only One Global Variable
+var _ contract.Seeder = (*Seeder)(nil)
+
+// NewSeeder returns a Seeder that fails t on unexpected calls and verifies its
+// expectations when t ends.
+func NewSeeder(t TestingT) *Seeder {
+	m := &Seeder{}
+	m.register(t)
+	return m
+}
+
+func (m *Seeder) Run(conn contract.Connection) error {
+	return result[error](&m.fake, m.Called(conn), 0)
+}
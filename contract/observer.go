@@ -0,0 +1,41 @@
+package contract
+
+import (
+	"context"
+	"time"
+)
+
+type (
+	// QueryEvent describes one statement executed by a connection.
+	QueryEvent struct {
+		// Operation is the kind of statement: "query", "create", "update",
+		// "delete", "row" or "raw" for statements built by repositories and query
+		// builders, and "exec" for Connection.Statement.
+		Operation string
+		// SQL is the statement with placeholders, without the argument values.
+		SQL string
+		// Args are the statement arguments after redaction.
+		Args []any
+		// Duration is the time the statement took.
+		Duration time.Duration
+		// RowsAffected is the number of rows affected or returned, when known.
+		RowsAffected int64
+		// Err is the error the statement failed with, if any.
+		Err error
+	}
+
+	// QueryObserver is notified after every statement a connection runs. It lets
+	// applications log, measure or trace queries; the library itself never logs.
+	// Implementations must be safe for concurrent use and should return quickly.
+	QueryObserver interface {
+		ObserveQuery(ctx context.Context, event QueryEvent)
+	}
+
+	// QueryObserverFunc adapts a function to the QueryObserver interface.
+	QueryObserverFunc func(ctx context.Context, event QueryEvent)
+)
+
+// ObserveQuery calls f(ctx, event).
+func (f QueryObserverFunc) ObserveQuery(ctx context.Context, event QueryEvent) {
+	f(ctx, event)
+}
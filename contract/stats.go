@@ -0,0 +1,50 @@
+package contract
+
+import (
+	"time"
+)
+
+type (
+	// Stats reports the health of every connection pool behind a Connection.
+	Stats struct {
+		Pools []PoolStats
+	}
+
+	// PoolStats reports the saturation of one connection pool and the outcome
+	// of the last Ping made through it.
+	PoolStats struct {
+		// Name identifies the pool, e.g. "primary" or "replica-0".
+		Name string
+
+		// MaxOpenConnections is the configured limit; zero means unlimited.
+		MaxOpenConnections int
+		// OpenConnections is the number of established connections, in use or idle.
+		OpenConnections int
+		// InUse is the number of connections currently in use.
+		InUse int
+		// Idle is the number of idle connections.
+		Idle int
+		// WaitCount is the total number of times a caller waited for a connection.
+		WaitCount int64
+		// WaitDuration is the total time callers waited for a connection.
+		WaitDuration time.Duration
+
+		// LastPingAt is when the last Ping finished; zero if Ping was never called.
+		LastPingAt time.Time
+		// LastPingLatency is how long the last Ping took.
+		LastPingLatency time.Duration
+		// LastPingError is the error of the last Ping, or nil if it succeeded.
+		LastPingError error
+	}
+)
+
+// Healthy reports whether every pool answered its last Ping. Pools that were
+// never pinged count as healthy.
+func (s Stats) Healthy() bool {
+	for _, pool := range s.Pools {
+		if pool.LastPingError != nil {
+			return false
+		}
+	}
+	return true
+}
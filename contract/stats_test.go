@@ -0,0 +1,20 @@
+package contract
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestStats_Healthy tests the aggregated health of connection pools
+func TestStats_Healthy(t *testing.T) {
+	assert.True(t, Stats{}.Healthy())
+	assert.True(t, Stats{Pools: []PoolStats{{Name: "primary"}}}.Healthy())
+
+	unhealthy := Stats{Pools: []PoolStats{
+		{Name: "primary"},
+		{Name: "replica-0", LastPingError: errors.New("connection refused")},
+	}}
+	assert.False(t, unhealthy.Healthy())
+}
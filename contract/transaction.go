@@ -0,0 +1,135 @@
+package contract
+
+import (
+	"database/sql"
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+type (
+	// TxOptions configures a transaction started by Connection.Transaction.
+	// The zero value starts a read-write transaction at the driver's default
+	// isolation level, without a deadline.
+	TxOptions struct {
+		// Isolation is the transaction isolation level. sql.LevelDefault leaves
+		// the choice to the driver.
+		Isolation sql.IsolationLevel
+		// ReadOnly starts a read-only transaction.
+		ReadOnly bool
+		// Timeout bounds the whole transaction, including the callback. When it
+		// expires the transaction is rolled back. Zero means no timeout. With Retry
+		// set, every attempt gets its own Timeout.
+		Timeout time.Duration
+		// Retry re-runs the whole transaction, callback included, when it fails
+		// with a retryable error. Nil disables retries.
+		Retry *RetryPolicy
+	}
+
+	// RetryPolicy describes how an operation is retried after a failure.
+	RetryPolicy struct {
+		// MaxAttempts is the total number of attempts, the first one included.
+		// Values below 2 disable retries.
+		MaxAttempts int
+		// BaseDelay is the wait before the second attempt. It doubles with every
+		// further attempt.
+		BaseDelay time.Duration
+		// MaxDelay caps the wait between attempts. Zero means no cap.
+		MaxDelay time.Duration
+		// Jitter is the fraction, between 0 and 1, of every wait that is randomized
+		// so that competing clients do not retry in lockstep.
+		Jitter float64
+		// Retryable reports whether an error is worth another attempt. When nil,
+		// the adapter's default applies; for transactions that is deadlocks and
+		// serialization failures.
+		Retryable func(error) bool
+	}
+
+	// TxOption defines a functional option for modifying TxOptions.
+	TxOption func(*TxOptions)
+
+	// TxHooks is implemented by connections that can run callbacks once the
+	// outermost transaction has finished, outside of it.
+	TxHooks interface {
+		// OnCommit registers fn to run after the outermost transaction commits.
+		// Hooks registered in a savepoint that is rolled back never run.
+		OnCommit(fn func())
+		// OnRollback registers fn to run after the work it was registered with is
+		// rolled back, either by the outermost transaction or by its savepoint.
+		OnRollback(fn func())
+	}
+)
+
+// NewTxOptions builds TxOptions from the given options.
+func NewTxOptions(opts ...TxOption) TxOptions {
+	var o TxOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithIsolation sets the isolation level of the transaction.
+func WithIsolation(level sql.IsolationLevel) TxOption {
+	return func(o *TxOptions) {
+		o.Isolation = level
+	}
+}
+
+// WithReadOnly starts the transaction in read-only mode.
+func WithReadOnly() TxOption {
+	return func(o *TxOptions) {
+		o.ReadOnly = true
+	}
+}
+
+// WithTxTimeout sets a deadline for the whole transaction.
+func WithTxTimeout(timeout time.Duration) TxOption {
+	return func(o *TxOptions) {
+		o.Timeout = timeout
+	}
+}
+
+// WithRetry retries the whole transaction according to policy.
+func WithRetry(policy RetryPolicy) TxOption {
+	return func(o *TxOptions) {
+		o.Retry = &policy
+	}
+}
+
+// DefaultRetryPolicy returns a policy of 3 attempts with a 50ms base delay,
+// a 1s cap and 50% jitter.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   50 * time.Millisecond,
+		MaxDelay:    time.Second,
+		Jitter:      0.5,
+	}
+}
+
+// Backoff returns the wait after the given failed attempt, starting at 1:
+// BaseDelay doubled for every earlier attempt, capped by MaxDelay and reduced
+// by a random share of up to Jitter.
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < attempt && delay < math.MaxInt64/2 && (p.MaxDelay <= 0 || delay < p.MaxDelay); i++ {
+		delay *= 2
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	if jitter := min(max(p.Jitter, 0), 1); jitter > 0 && delay > 0 {
+		delay -= time.Duration(rand.Float64() * jitter * float64(delay)) //nolint:gosec // jitter does not need a cryptographic source
+	}
+	return delay
+}
+
+// SQL converts the options to the database/sql form. It returns nil when
+// neither an isolation level nor read-only mode is requested.
+func (o TxOptions) SQL() *sql.TxOptions {
+	if o.Isolation == sql.LevelDefault && !o.ReadOnly {
+		return nil
+	}
+	return &sql.TxOptions{Isolation: o.Isolation, ReadOnly: o.ReadOnly}
+}
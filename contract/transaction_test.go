@@ -0,0 +1,67 @@
+package contract
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestTxOptions tests building and converting transaction options
+func TestTxOptions(t *testing.T) {
+	t.Run("Defaults", func(t *testing.T) {
+		opts := NewTxOptions()
+		assert.Equal(t, TxOptions{}, opts)
+		assert.Nil(t, opts.SQL(), "default options should not force sql.TxOptions")
+	})
+
+	t.Run("AllOptions", func(t *testing.T) {
+		opts := NewTxOptions(
+			WithIsolation(sql.LevelSerializable),
+			WithReadOnly(),
+			WithTxTimeout(time.Second),
+		)
+		assert.Equal(t, sql.LevelSerializable, opts.Isolation)
+		assert.True(t, opts.ReadOnly)
+		assert.Equal(t, time.Second, opts.Timeout)
+		assert.Equal(t, &sql.TxOptions{Isolation: sql.LevelSerializable, ReadOnly: true}, opts.SQL())
+	})
+
+	t.Run("TimeoutOnly", func(t *testing.T) {
+		opts := NewTxOptions(WithTxTimeout(time.Second))
+		assert.Nil(t, opts.SQL())
+	})
+}
+
+// TestRetryPolicy tests the retry option and backoff computation
+func TestRetryPolicy(t *testing.T) {
+	t.Run("WithRetry", func(t *testing.T) {
+		opts := NewTxOptions(WithRetry(DefaultRetryPolicy()))
+		assert.NotNil(t, opts.Retry)
+		assert.Equal(t, 3, opts.Retry.MaxAttempts)
+	})
+
+	t.Run("ExponentialWithCap", func(t *testing.T) {
+		policy := RetryPolicy{BaseDelay: 10 * time.Millisecond, MaxDelay: 50 * time.Millisecond}
+		assert.Equal(t, 10*time.Millisecond, policy.Backoff(1))
+		assert.Equal(t, 20*time.Millisecond, policy.Backoff(2))
+		assert.Equal(t, 40*time.Millisecond, policy.Backoff(3))
+		assert.Equal(t, 50*time.Millisecond, policy.Backoff(4))
+		assert.Equal(t, 50*time.Millisecond, policy.Backoff(100))
+	})
+
+	t.Run("Uncapped", func(t *testing.T) {
+		policy := RetryPolicy{BaseDelay: time.Second}
+		assert.Positive(t, policy.Backoff(200), "large attempts must not overflow")
+	})
+
+	t.Run("Jitter", func(t *testing.T) {
+		policy := RetryPolicy{BaseDelay: 100 * time.Millisecond, Jitter: 0.5}
+		for range 100 {
+			delay := policy.Backoff(1)
+			assert.GreaterOrEqual(t, delay, 50*time.Millisecond)
+			assert.LessOrEqual(t, delay, 100*time.Millisecond)
+		}
+	})
+}
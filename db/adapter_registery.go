@@ -2,6 +2,7 @@ package db
 
 import (
 	"fmt"
+	"sort"
 	"sync"
 
 	"github.com/next-trace/scg-database/contract"
@@ -12,23 +13,48 @@ var (
 	adapters   = make(map[string]contract.DBAdapter)
 )
 
+// AdapterInfo describes an adapter of the registry under one of its names.
+type AdapterInfo struct {
+	// Name is the name the adapter was registered under, e.g. "gorm:postgres".
+	Name string
+	// Adapter is the registered adapter.
+	Adapter contract.DBAdapter
+	// Capabilities lists the features the adapter supports for Name. It is nil
+	// when the adapter does not implement contract.Capabilities.
+	Capabilities []contract.Capability
+}
+
 // RegisterAdapter adds one or more database adapters to the global registry.
+// Like database/sql.Register, it is meant for init code and panics on a nil
+// adapter or a missing name; TryRegisterAdapter returns an error instead.
 func RegisterAdapter(adapter contract.DBAdapter, names ...string) {
-	adaptersMu.Lock()
-	defer adaptersMu.Unlock()
+	if err := TryRegisterAdapter(adapter, names...); err != nil {
+		panic(err.Error())
+	}
+}
+
+// TryRegisterAdapter adds one or more database adapters to the global registry.
+// It fails, without registering anything, on a nil adapter or a missing name.
+// A name that is already registered is taken over by adapter.
+func TryRegisterAdapter(adapter contract.DBAdapter, names ...string) error {
 	if adapter == nil {
-		panic("cannot register a nil database adapter")
+		return NewError("RegisterAdapter", "cannot register a nil database adapter", ErrInvalidAdapter)
 	}
 	if len(names) == 0 {
-		panic("cannot register adapter without at least one name")
+		return NewError("RegisterAdapter", "cannot register adapter without at least one name", ErrInvalidAdapter)
 	}
-
 	for _, name := range names {
 		if name == "" {
-			panic("cannot register adapter with an empty name")
+			return NewError("RegisterAdapter", "cannot register adapter with an empty name", ErrInvalidAdapter)
 		}
+	}
+
+	adaptersMu.Lock()
+	defer adaptersMu.Unlock()
+	for _, name := range names {
 		adapters[name] = adapter
 	}
+	return nil
 }
 
 // GetAdapter retrieves a registered database adapter by its name.
@@ -41,3 +67,59 @@ func GetAdapter(name string) (contract.DBAdapter, error) {
 	}
 	return adapter, nil
 }
+
+// ListAdapters returns every registered name with its adapter and capabilities,
+// sorted by name.
+func ListAdapters() []AdapterInfo {
+	adaptersMu.RLock()
+	defer adaptersMu.RUnlock()
+
+	infos := make([]AdapterInfo, 0, len(adapters))
+	for name, adapter := range adapters {
+		infos = append(infos, AdapterInfo{Name: name, Adapter: adapter, Capabilities: capabilitiesOf(adapter, name)})
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos
+}
+
+// Supports reports whether the adapter registered for driver supports
+// capability. It is false for unknown drivers and for adapters that do not
+// implement contract.Capabilities, so callers fall back to the portable path.
+func Supports(driver string, capability contract.Capability) bool {
+	adapter, err := GetAdapter(driver)
+	if err != nil {
+		return false
+	}
+	capabilities, ok := adapter.(contract.Capabilities)
+	return ok && capabilities.Supports(driver, capability)
+}
+
+// DialectInfo returns the SQL specifics of driver from the adapter registered
+// for it, and false for unknown drivers and for adapters that do not implement
+// contract.Dialects.
+func DialectInfo(driver string) (contract.DialectInfo, bool) {
+	adapter, err := GetAdapter(driver)
+	if err != nil {
+		return contract.DialectInfo{}, false
+	}
+	dialects, ok := adapter.(contract.Dialects)
+	if !ok {
+		return contract.DialectInfo{}, false
+	}
+	return dialects.DialectInfo(driver)
+}
+
+// capabilitiesOf lists the capabilities adapter supports for driver.
+func capabilitiesOf(adapter contract.DBAdapter, driver string) []contract.Capability {
+	capabilities, ok := adapter.(contract.Capabilities)
+	if !ok {
+		return nil
+	}
+	supported := []contract.Capability{}
+	for _, capability := range contract.AllCapabilities() {
+		if capabilities.Supports(driver, capability) {
+			supported = append(supported, capability)
+		}
+	}
+	return supported
+}
@@ -51,3 +51,66 @@ func TestRegisterAdapter(t *testing.T) {
 		require.Same(t, adapter, retrievedAlias)
 	})
 }
+
+type capableAdapter struct{ dummyAdapter }
+
+func (c *capableAdapter) Supports(driver string, capability contract.Capability) bool {
+	return driver == "capable:sql" && capability == contract.CapabilityUpsert
+}
+
+func TestTryRegisterAdapter(t *testing.T) {
+	tests := []struct {
+		name    string
+		adapter contract.DBAdapter
+		names   []string
+		errMsg  string
+	}{
+		{"nil adapter", nil, []string{"nil-adapter"}, "cannot register a nil database adapter"},
+		{"no name", &dummyAdapter{}, nil, "cannot register adapter without at least one name"},
+		{"empty name", &dummyAdapter{}, []string{"try-valid", ""}, "cannot register adapter with an empty name"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := db.TryRegisterAdapter(tt.adapter, tt.names...)
+			require.ErrorIs(t, err, db.ErrInvalidAdapter)
+			require.ErrorContains(t, err, tt.errMsg)
+		})
+	}
+
+	_, err := db.GetAdapter("try-valid")
+	require.Error(t, err, "nothing is registered when a name is invalid")
+
+	require.NoError(t, db.TryRegisterAdapter(&dummyAdapter{}, "try-valid"))
+	_, err = db.GetAdapter("try-valid")
+	require.NoError(t, err)
+}
+
+func TestListAdapters(t *testing.T) {
+	capable := &capableAdapter{}
+	db.RegisterAdapter(capable, "capable:sql", "capable:other")
+	db.RegisterAdapter(&dummyAdapter{}, "list-dummy")
+
+	byName := map[string]db.AdapterInfo{}
+	var names []string
+	for _, info := range db.ListAdapters() {
+		byName[info.Name] = info
+		names = append(names, info.Name)
+	}
+	require.IsNonDecreasing(t, names, "adapters are sorted by name")
+
+	require.Same(t, capable, byName["capable:sql"].Adapter)
+	require.Equal(t, []contract.Capability{contract.CapabilityUpsert}, byName["capable:sql"].Capabilities)
+	require.Empty(t, byName["capable:other"].Capabilities)
+	require.NotNil(t, byName["capable:other"].Capabilities, "capable adapters report an empty list")
+	require.Nil(t, byName["list-dummy"].Capabilities, "other adapters report nothing")
+}
+
+func TestSupports(t *testing.T) {
+	db.RegisterAdapter(&capableAdapter{}, "capable:sql")
+	db.RegisterAdapter(&dummyAdapter{}, "list-dummy")
+
+	require.True(t, db.Supports("capable:sql", contract.CapabilityUpsert))
+	require.False(t, db.Supports("capable:sql", contract.CapabilityReturning))
+	require.False(t, db.Supports("list-dummy", contract.CapabilityUpsert))
+	require.False(t, db.Supports("surely-unregistered", contract.CapabilityUpsert))
+}
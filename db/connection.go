@@ -9,8 +9,27 @@ import (
 
 // Connect is the single, unified function for creating a database connection.
 // It accepts a populated Config struct and optional settings.
+//
+// When cfg.Replicas is set, the returned Connection routes reads to the
+// replicas and writes and transactions to the primary.
+//
+// Options are applied to a copy of cfg, so cfg can be reused with the same
+// options without, for example, registering a query observer twice.
+//
+// Secret references in the DSNs (see config.SecretProvider) are resolved here,
+// so a missing secret fails Connect; adapters resolve them again for every new
+// pool connection to pick up rotated credentials.
 func Connect(cfg *config.Config, opts ...config.Option) (contract.Connection, error) {
+	return ConnectContext(context.Background(), cfg, opts...)
+}
+
+// ConnectContext is Connect bounded by ctx: the connection pings are made with
+// ctx and no further attempt is started once it is done. With WithConnectRetry,
+// failed connect and ping attempts are retried with backoff and the failure is
+// returned as a *RetryError carrying the number of attempts made.
+func ConnectContext(ctx context.Context, cfg *config.Config, opts ...config.Option) (contract.Connection, error) {
 	// Apply functional options, like WithAdapter or WithLogger
+	cfg = cfg.Clone()
 	for _, opt := range opts {
 		opt(cfg)
 	}
@@ -18,31 +37,70 @@ func Connect(cfg *config.Config, opts ...config.Option) (contract.Connection, er
 	if err := cfg.Validate(); err != nil {
 		return nil, NewConfigValidationError(err)
 	}
+	if err := resolveSecrets(ctx, cfg); err != nil {
+		return nil, err
+	}
+
+	adapter, err := resolveAdapter(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := openWithRetry(ctx, adapter, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(cfg.Replicas) == 0 {
+		return conn, nil
+	}
+	return connectReplicas(ctx, adapter, cfg, conn)
+}
 
-	// Determine the adapter, either from injection or the registry
-	var adapter contract.DBAdapter
+// resolveAdapter determines the adapter, either from injection or the registry.
+func resolveAdapter(cfg *config.Config) (contract.DBAdapter, error) {
 	if cfg.Adapter != nil {
-		var ok bool
-		adapter, ok = cfg.Adapter.(contract.DBAdapter)
+		adapter, ok := cfg.Adapter.(contract.DBAdapter)
 		if !ok {
 			return nil, NewInvalidAdapterError()
 		}
-	} else {
+		return adapter, nil
+	}
+
+	adapter, err := GetAdapter(cfg.Driver)
+	if err != nil {
+		return nil, NewAdapterLookupError(err)
+	}
+	return adapter, nil
+}
+
+// openWithRetry opens a connection, retrying according to the policy set with WithConnectRetry.
+func openWithRetry(ctx context.Context, adapter contract.DBAdapter, cfg *config.Config) (contract.Connection, error) {
+	policy := ConnectRetry(cfg)
+	if policy.MaxAttempts < 2 {
+		return open(ctx, adapter, cfg)
+	}
+
+	var conn contract.Connection
+	err := Retry(ctx, "Connect", policy, func(ctx context.Context) error {
 		var err error
-		adapter, err = GetAdapter(cfg.Driver)
-		if err != nil {
-			return nil, NewAdapterLookupError(err)
-		}
+		conn, err = open(ctx, adapter, cfg)
+		return err
+	})
+	if err != nil {
+		return nil, err
 	}
+	return conn, nil
+}
 
-	// Use the adapter to establish the connection
+// open uses the adapter to establish the connection and pings it to ensure it is live.
+func open(ctx context.Context, adapter contract.DBAdapter, cfg *config.Config) (contract.Connection, error) {
 	conn, err := adapter.Connect(cfg)
 	if err != nil {
 		return nil, NewAdapterConnectError(err)
 	}
 
-	// Ping to ensure the connection is live
-	if err := conn.Ping(context.Background()); err != nil {
+	if err := conn.Ping(ctx); err != nil {
 		_ = conn.Close()
 		return nil, NewConnectionPingError(err)
 	}
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/next-trace/scg-database/config"
 	"github.com/next-trace/scg-database/contract"
@@ -18,11 +19,18 @@ type (
 		conn       contract.Connection
 	}
 
+	// configAdapter records the config it was last connected with.
+	configAdapter struct {
+		fakeAdapter
+		cfg *config.Config
+	}
+
 	// fakeConn is a mock connection that allows us to track method calls.
 	fakeConn struct {
 		pingErr     error
 		closeErr    error
 		closeCalled bool // track if Close() was called
+		stats       contract.Stats
 	}
 )
 
@@ -32,14 +40,24 @@ func (f *fakeAdapter) Connect(cfg *config.Config) (contract.Connection, error) {
 
 func (f *fakeAdapter) Name() string { return "fake" }
 
+func (c *configAdapter) Connect(cfg *config.Config) (contract.Connection, error) {
+	c.cfg = cfg
+	return c.fakeAdapter.Connect(cfg)
+}
+
 func (f *fakeConn) Ping(ctx context.Context) error { return f.pingErr }
 func (f *fakeConn) Close() error {
 	f.closeCalled = true
 	return f.closeErr
 }
+func (f *fakeConn) Stats() contract.Stats                                           { return f.stats }
 func (f *fakeConn) GetConnection() any                                              { return nil }
 func (f *fakeConn) NewRepository(model contract.Model) (contract.Repository, error) { return nil, nil }
-func (f *fakeConn) Transaction(ctx context.Context, fn func(contract.Connection) error) error {
+func (f *fakeConn) Transaction(
+	ctx context.Context,
+	fn func(contract.Connection) error,
+	opts ...contract.TxOption,
+) error {
 	return nil
 }
 
@@ -62,6 +80,19 @@ func TestConnect_WithInjectedAdapter(t *testing.T) {
 	require.NotNil(t, conn)
 }
 
+func TestConnect_OptionsApplyToCopy(t *testing.T) {
+	adapter := &configAdapter{fakeAdapter: fakeAdapter{conn: &fakeConn{}}}
+	cfg := &config.Config{Driver: "any", DSN: "any", Adapter: adapter}
+	observer := WithQueryObserver(contract.QueryObserverFunc(func(context.Context, contract.QueryEvent) {}))
+
+	for range 2 {
+		_, err := Connect(cfg, observer)
+		require.NoError(t, err)
+		require.Len(t, QueryObservers(adapter.cfg), 1, "reconnecting should not register the observer twice")
+	}
+	require.Empty(t, QueryObservers(cfg), "options should not modify the caller's config")
+}
+
 func TestConnect_WithRegisteredAdapter(t *testing.T) {
 	// Register a fake adapter for this test
 	adapter := &fakeAdapter{conn: &fakeConn{}}
@@ -110,3 +141,116 @@ func TestConnect_PingFail_CallsClose(t *testing.T) {
 	require.ErrorIs(t, err, pingError, "The wrapping error should contain the ping error")
 	require.True(t, mockConn.closeCalled, "Close() should be called on ping failure")
 }
+
+func TestConnect_ReplicaFailure_ClosesOpenedConnections(t *testing.T) {
+	primary := &fakeConn{}
+	replicaErr := errors.New("replica down")
+	cfg := config.Config{
+		Driver:   "fake",
+		DSN:      "primary",
+		Replicas: []config.Replica{{DSN: "replica"}},
+		Adapter:  &dsnAdapter{conns: map[string]*fakeConn{"primary": primary, "replica": {pingErr: replicaErr}}},
+	}
+
+	_, err := Connect(&cfg)
+	require.Error(t, err)
+	require.ErrorIs(t, err, replicaErr)
+	require.True(t, primary.closeCalled, "primary should be closed when a replica fails")
+}
+
+func TestConnectContext_RetriesUntilReady(t *testing.T) {
+	conn := &fakeConn{pingErr: errors.New("connection refused")}
+	adapter := &flakyAdapter{conn: conn, readyAfter: 3}
+	cfg := config.Config{Driver: "fake", DSN: "any", Adapter: adapter}
+
+	got, err := ConnectContext(t.Context(), &cfg, WithConnectRetry(contract.RetryPolicy{MaxAttempts: 5}))
+	require.NoError(t, err)
+	require.Same(t, conn, got)
+	require.Equal(t, 3, adapter.attempts)
+	require.True(t, conn.closeCalled, "connections that failed to ping are closed")
+}
+
+func TestConnectContext_RetryExhausted(t *testing.T) {
+	pingError := errors.New("connection refused")
+	adapter := &flakyAdapter{conn: &fakeConn{pingErr: pingError}, readyAfter: 10}
+	cfg := config.Config{Driver: "fake", DSN: "any", Adapter: adapter}
+
+	_, err := ConnectContext(t.Context(), &cfg, WithConnectRetry(contract.RetryPolicy{MaxAttempts: 3}))
+	var retryErr *RetryError
+	require.ErrorAs(t, err, &retryErr)
+	require.Equal(t, 3, retryErr.Attempts)
+	require.Equal(t, "Connect", retryErr.Operation)
+	require.ErrorIs(t, err, pingError)
+	require.Contains(t, err.Error(), "initial database ping failed", "the typed ping error is kept")
+}
+
+func TestConnectContext_AdapterErrorIsTyped(t *testing.T) {
+	connectError := errors.New("adapter failed")
+	cfg := config.Config{Driver: "fake", DSN: "any", Adapter: &fakeAdapter{connectErr: connectError}}
+
+	_, err := ConnectContext(t.Context(), &cfg, WithConnectRetry(contract.RetryPolicy{MaxAttempts: 2}))
+	var dbErr *Error
+	require.ErrorAs(t, err, &dbErr)
+	require.Equal(t, "adapter connect failed", dbErr.Message)
+	require.ErrorIs(t, err, connectError)
+}
+
+func TestConnectContext_StopsAtDeadline(t *testing.T) {
+	adapter := &flakyAdapter{conn: &fakeConn{pingErr: errors.New("connection refused")}, readyAfter: 1000}
+	cfg := config.Config{Driver: "fake", DSN: "any", Adapter: adapter}
+	ctx, cancel := context.WithTimeout(t.Context(), 50*time.Millisecond)
+	defer cancel()
+
+	policy := contract.RetryPolicy{MaxAttempts: 1000, BaseDelay: 20 * time.Millisecond}
+	_, err := ConnectContext(ctx, &cfg, WithConnectRetry(policy))
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Less(t, adapter.attempts, 1000)
+}
+
+// flakyAdapter hands out conn, whose ping only succeeds from attempt readyAfter on.
+type flakyAdapter struct {
+	conn       *fakeConn
+	readyAfter int
+	attempts   int
+}
+
+func (f *flakyAdapter) Connect(cfg *config.Config) (contract.Connection, error) {
+	f.attempts++
+	if f.attempts >= f.readyAfter {
+		f.conn.pingErr = nil
+	}
+	return f.conn, nil
+}
+
+func (f *flakyAdapter) Name() string { return "flaky" }
+
+// dsnAdapter hands out a different fake connection per DSN.
+type dsnAdapter struct {
+	conns map[string]*fakeConn
+}
+
+func (d *dsnAdapter) Connect(cfg *config.Config) (contract.Connection, error) {
+	return d.conns[cfg.DSN], nil
+}
+
+func (d *dsnAdapter) Name() string { return "dsn" }
+
+func TestReplicatedConnection_Stats(t *testing.T) {
+	pool := func(name string, inUse int) contract.Stats {
+		return contract.Stats{Pools: []contract.PoolStats{{Name: name, InUse: inUse}}}
+	}
+	conn := &replicatedConnection{
+		primary: &fakeConn{stats: pool("primary", 1)},
+		replicas: []contract.Connection{
+			&fakeConn{stats: pool("primary", 2)},
+			&fakeConn{stats: contract.Stats{Pools: []contract.PoolStats{{Name: "a"}, {Name: "b"}}}},
+		},
+	}
+
+	var names []string
+	for _, p := range conn.Stats().Pools {
+		names = append(names, p.Name)
+	}
+	require.Equal(t, []string{"primary", "replica-0", "replica-1/a", "replica-1/b"}, names)
+	require.Equal(t, 2, conn.Stats().Pools[1].InUse)
+}
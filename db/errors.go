@@ -5,6 +5,9 @@ package db
 import (
 	"errors"
 	"fmt"
+	"strings"
+
+	"github.com/next-trace/scg-database/contract"
 )
 
 var (
@@ -20,6 +23,40 @@ var (
 	ErrAdapterConnect = errors.New("adapter connect failed")
 	// ErrConnectionPing indicates that database connection ping failed.
 	ErrConnectionPing = errors.New("connection ping failed")
+	// ErrUnknownConnection indicates that a named connection is not configured.
+	ErrUnknownConnection = errors.New("unknown connection")
+	// ErrManagerClosed indicates that a connection was requested from a closed Manager.
+	ErrManagerClosed = errors.New("connection manager is closed")
+	// ErrTxHooksUnsupported indicates that a transaction does not support OnCommit and OnRollback hooks.
+	ErrTxHooksUnsupported = errors.New("transaction hooks are not supported")
+	// ErrCircuitOpen indicates that a supervised connection rejected a call because the database is down.
+	ErrCircuitOpen = errors.New("circuit breaker is open")
+	// ErrUnsupportedCapability indicates that the driver does not support a feature the call needs.
+	ErrUnsupportedCapability = errors.New("capability is not supported")
+	// ErrModelType indicates that a model is not of the type a typed repository expects.
+	ErrModelType = errors.New("unexpected model type")
+)
+
+// Errors returned by queries. Adapters translate driver errors into a
+// *DatabaseError whose Kind is one of these, so callers can match them with
+// errors.Is without importing driver packages.
+var (
+	// ErrUniqueViolation indicates that a write violated a unique or primary key constraint.
+	ErrUniqueViolation = errors.New("unique constraint violation")
+	// ErrForeignKeyViolation indicates that a write violated a foreign key constraint.
+	ErrForeignKeyViolation = errors.New("foreign key constraint violation")
+	// ErrNotNullViolation indicates that a write left a NOT NULL column empty.
+	ErrNotNullViolation = errors.New("not null constraint violation")
+	// ErrCheckViolation indicates that a write violated a CHECK constraint.
+	ErrCheckViolation = errors.New("check constraint violation")
+	// ErrDeadlock indicates that the database aborted the statement to resolve a deadlock.
+	ErrDeadlock = errors.New("deadlock detected")
+	// ErrSerializationFailure indicates that a transaction could not be serialized with concurrent ones.
+	ErrSerializationFailure = errors.New("serialization failure")
+	// ErrTimeout indicates that a statement or lock wait timed out.
+	ErrTimeout = errors.New("database operation timed out")
+	// ErrConnectionLost indicates that the connection to the database was lost.
+	ErrConnectionLost = errors.New("database connection lost")
 )
 
 // Error represents a structured database error with context
@@ -29,6 +66,17 @@ type (
 		Message   string
 		Err       error
 	}
+
+	// DatabaseError is a driver error classified by an adapter. Kind is one of the
+	// query error sentinels; Constraint, Table and Column are set when the driver
+	// reports them. It matches both Kind and the original driver error.
+	DatabaseError struct {
+		Kind       error
+		Constraint string
+		Table      string
+		Column     string
+		Err        error
+	}
 )
 
 // Error implements the error interface
@@ -52,6 +100,40 @@ func (e *Error) Is(target error) bool {
 	return errors.Is(e.Err, target)
 }
 
+// Error implements the error interface
+func (e *DatabaseError) Error() string {
+	var details []string
+	if e.Constraint != "" {
+		details = append(details, fmt.Sprintf("constraint %q", e.Constraint))
+	}
+	if e.Table != "" {
+		details = append(details, fmt.Sprintf("table %q", e.Table))
+	}
+	if e.Column != "" {
+		details = append(details, fmt.Sprintf("column %q", e.Column))
+	}
+
+	msg := e.Kind.Error()
+	if len(details) > 0 {
+		msg += " (" + strings.Join(details, ", ") + ")"
+	}
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+// Unwrap returns the kind and the original driver error, so errors.Is and
+// errors.As match either of them.
+func (e *DatabaseError) Unwrap() []error {
+	return []error{e.Kind, e.Err}
+}
+
+// NewDatabaseError creates a DatabaseError of the given kind wrapping the driver error err.
+func NewDatabaseError(kind, err error) *DatabaseError {
+	return &DatabaseError{Kind: kind, Err: err}
+}
+
 // NewError creates a new structured database error
 func NewError(operation, message string, err error) *Error {
 	return &Error{
@@ -85,3 +167,38 @@ func NewAdapterConnectError(err error) error {
 func NewConnectionPingError(err error) error {
 	return NewError("Connect", "initial database ping failed", err)
 }
+
+// NewUnsupportedCapabilityError creates a new Error for a call that needs a capability the driver lacks.
+func NewUnsupportedCapabilityError(operation, driver string, capability contract.Capability) error {
+	return NewError(operation, fmt.Sprintf("driver %q does not support %s", driver, capability), ErrUnsupportedCapability)
+}
+
+// NewSecretResolutionError creates a new Error for secret references of a DSN that cannot be resolved.
+func NewSecretResolutionError(err error) error {
+	return NewError("Connect", "failed to resolve dsn secrets", err)
+}
+
+// NewUnknownConnectionError creates a new Error for a connection name that is not configured.
+func NewUnknownConnectionError(name string) error {
+	return NewError("Manager", fmt.Sprintf("connection %q is not configured", name), ErrUnknownConnection)
+}
+
+// NewManagerClosedError creates a new Error for use of a closed Manager.
+func NewManagerClosedError() error {
+	return NewError("Manager", "manager is closed", ErrManagerClosed)
+}
+
+// NewTxHooksUnsupportedError creates a new Error for a transaction that cannot register hooks.
+func NewTxHooksUnsupportedError(operation string) error {
+	return NewError(operation, "the transaction in the context does not support hooks", ErrTxHooksUnsupported)
+}
+
+// NewCircuitOpenError creates a new Error for a call rejected by an open circuit breaker.
+func NewCircuitOpenError(operation string) error {
+	return NewError(operation, "the database is unavailable", ErrCircuitOpen)
+}
+
+// NewModelTypeError creates a new Error for a model of another type than a typed repository's.
+func NewModelTypeError(operation string, got, want any) error {
+	return NewError(operation, fmt.Sprintf("got %T, expected %T", got, want), ErrModelType)
+}
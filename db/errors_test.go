@@ -289,3 +289,38 @@ func TestNewConnectionPingError(t *testing.T) {
 	assert.Equal(t, "initial database ping failed", dbErr.Message)
 	assert.Equal(t, underlyingErr, dbErr.Err)
 }
+
+func TestDatabaseError(t *testing.T) {
+	driverErr := errors.New(`duplicate key value violates unique constraint "users_email_key"`)
+
+	t.Run("MatchesKindAndDriverError", func(t *testing.T) {
+		err := error(&DatabaseError{Kind: ErrUniqueViolation, Constraint: "users_email_key", Err: driverErr})
+		assert.ErrorIs(t, err, ErrUniqueViolation)
+		assert.ErrorIs(t, err, driverErr)
+		assert.NotErrorIs(t, err, ErrForeignKeyViolation)
+
+		var dbErr *DatabaseError
+		assert.ErrorAs(t, err, &dbErr)
+		assert.Equal(t, "users_email_key", dbErr.Constraint)
+	})
+
+	t.Run("Message", func(t *testing.T) {
+		err := &DatabaseError{
+			Kind:       ErrUniqueViolation,
+			Constraint: "users_email_key",
+			Table:      "users",
+			Column:     "email",
+			Err:        driverErr,
+		}
+		assert.Equal(t, `unique constraint violation (constraint "users_email_key", table "users", column "email"): `+
+			driverErr.Error(), err.Error())
+		assert.Equal(t, "deadlock detected", NewDatabaseError(ErrDeadlock, nil).Error())
+	})
+
+	t.Run("NewDatabaseError", func(t *testing.T) {
+		err := NewDatabaseError(ErrTimeout, driverErr)
+		assert.Equal(t, ErrTimeout, err.Kind)
+		assert.Equal(t, driverErr, err.Err)
+		assert.Empty(t, err.Constraint)
+	})
+}
@@ -0,0 +1,162 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/next-trace/scg-database/config"
+	"github.com/next-trace/scg-database/contract"
+)
+
+type (
+	// Manager is a registry of named connections, mirroring the
+	// `database.default` and `database.connections.<name>` layout used by the CLI.
+	// Each connection is opened with Connect the first time it is requested.
+	Manager struct {
+		mu          sync.Mutex
+		defaultName string
+		configs     map[string]*config.Config
+		opts        []config.Option
+		conns       map[string]contract.Connection
+		pending     map[string]*pendingConnection
+		closed      bool
+	}
+
+	// pendingConnection is a connection being opened. Callers that ask for it
+	// in the meantime wait on done and share its result.
+	pendingConnection struct {
+		done chan struct{}
+		conn contract.Connection
+		err  error
+	}
+)
+
+// NewManager creates a Manager for the given named configs. defaultName must be
+// one of the configured names; opts are applied to a copy of each config when
+// its connection is opened.
+func NewManager(defaultName string, configs map[string]*config.Config, opts ...config.Option) (*Manager, error) {
+	if len(configs) == 0 {
+		return nil, NewError("Manager", "at least one connection is required", ErrConfigValidation)
+	}
+	for name, cfg := range configs {
+		if cfg == nil {
+			return nil, NewError("Manager", fmt.Sprintf("connection %q has no config", name), ErrConfigValidation)
+		}
+	}
+	if _, ok := configs[defaultName]; !ok {
+		return nil, NewUnknownConnectionError(defaultName)
+	}
+
+	return &Manager{
+		defaultName: defaultName,
+		configs:     configs,
+		opts:        opts,
+		conns:       make(map[string]contract.Connection, len(configs)),
+		pending:     make(map[string]*pendingConnection),
+	}, nil
+}
+
+// Connection returns the named connection, opening it on first use.
+// An empty name returns the default connection. The lock is not held while
+// connecting, so opening one connection does not block lookups of the others;
+// concurrent callers asking for the same connection share a single attempt.
+func (m *Manager) Connection(name string) (contract.Connection, error) {
+	if name == "" {
+		name = m.defaultName
+	}
+
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return nil, NewManagerClosedError()
+	}
+	if conn, ok := m.conns[name]; ok {
+		m.mu.Unlock()
+		return conn, nil
+	}
+	if pending, ok := m.pending[name]; ok {
+		m.mu.Unlock()
+		<-pending.done
+		return pending.conn, pending.err
+	}
+	cfg, ok := m.configs[name]
+	if !ok {
+		m.mu.Unlock()
+		return nil, NewUnknownConnectionError(name)
+	}
+	pending := &pendingConnection{done: make(chan struct{})}
+	m.pending[name] = pending
+	m.mu.Unlock()
+
+	pending.conn, pending.err = Connect(cfg, m.opts...)
+
+	m.mu.Lock()
+	delete(m.pending, name)
+	switch {
+	case pending.err != nil:
+	case m.closed:
+		_ = pending.conn.Close()
+		pending.conn, pending.err = nil, NewManagerClosedError()
+	default:
+		m.conns[name] = pending.conn
+	}
+	m.mu.Unlock()
+	close(pending.done)
+
+	return pending.conn, pending.err
+}
+
+// Default returns the default connection, opening it on first use.
+func (m *Manager) Default() (contract.Connection, error) {
+	return m.Connection(m.defaultName)
+}
+
+// DefaultName returns the name of the default connection.
+func (m *Manager) DefaultName() string {
+	return m.defaultName
+}
+
+// Names returns the sorted names of all configured connections.
+func (m *Manager) Names() []string {
+	names := make([]string, 0, len(m.configs))
+	for name := range m.configs {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Stats returns the pool statistics of every connection opened so far, by name.
+func (m *Manager) Stats() map[string]contract.Stats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats := make(map[string]contract.Stats, len(m.conns))
+	for name, conn := range m.conns {
+		stats[name] = conn.Stats()
+	}
+	return stats
+}
+
+// Close closes every connection that has been opened. The Manager cannot be
+// used afterwards; further calls to Connection return ErrManagerClosed.
+func (m *Manager) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.closed {
+		return nil
+	}
+	m.closed = true
+
+	var errs []error
+	for name, conn := range m.conns {
+		if err := conn.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("close connection %q: %w", name, err))
+		}
+	}
+	m.conns = nil
+	return errors.Join(errs...)
+}
@@ -0,0 +1,202 @@
+package db
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/next-trace/scg-database/config"
+	"github.com/next-trace/scg-database/contract"
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	// countingAdapter records how many times Connect was called.
+	countingAdapter struct {
+		conn     *fakeConn
+		err      error
+		connects int
+	}
+
+	// blockingAdapter connects once release is closed, reporting each attempt on started.
+	blockingAdapter struct {
+		conn     *fakeConn
+		started  chan struct{}
+		release  chan struct{}
+		connects atomic.Int32
+	}
+)
+
+func (c *countingAdapter) Connect(cfg *config.Config) (contract.Connection, error) {
+	c.connects++
+	if c.err != nil {
+		return nil, c.err
+	}
+	return c.conn, nil
+}
+
+func (c *countingAdapter) Name() string { return "counting" }
+
+func (b *blockingAdapter) Connect(*config.Config) (contract.Connection, error) {
+	b.connects.Add(1)
+	b.started <- struct{}{}
+	<-b.release
+	return b.conn, nil
+}
+
+func (b *blockingAdapter) Name() string { return "blocking" }
+
+// newTestManager builds a Manager with an "orders" default and an "analytics" connection.
+func newTestManager(t *testing.T) (*Manager, *countingAdapter, *countingAdapter) {
+	t.Helper()
+	orders := &countingAdapter{conn: &fakeConn{}}
+	analytics := &countingAdapter{conn: &fakeConn{}}
+
+	manager, err := NewManager("orders", map[string]*config.Config{
+		"orders":    {Driver: "fake", DSN: "orders", Adapter: orders},
+		"analytics": {Driver: "fake", DSN: "analytics", Adapter: analytics},
+	})
+	require.NoError(t, err)
+	return manager, orders, analytics
+}
+
+func TestNewManager_Validation(t *testing.T) {
+	t.Run("NoConnections", func(t *testing.T) {
+		_, err := NewManager("default", nil)
+		require.ErrorIs(t, err, ErrConfigValidation)
+	})
+
+	t.Run("NilConfig", func(t *testing.T) {
+		_, err := NewManager("main", map[string]*config.Config{"main": nil})
+		require.ErrorIs(t, err, ErrConfigValidation)
+		require.Contains(t, err.Error(), `connection "main" has no config`)
+	})
+
+	t.Run("UnknownDefault", func(t *testing.T) {
+		_, err := NewManager("missing", map[string]*config.Config{"main": {Driver: "fake", DSN: "any"}})
+		require.ErrorIs(t, err, ErrUnknownConnection)
+		require.Contains(t, err.Error(), `connection "missing" is not configured`)
+	})
+}
+
+func TestManager_Connection(t *testing.T) {
+	manager, orders, analytics := newTestManager(t)
+
+	require.Equal(t, []string{"analytics", "orders"}, manager.Names())
+	require.Equal(t, "orders", manager.DefaultName())
+	require.Zero(t, orders.connects, "connections should be opened lazily")
+
+	conn, err := manager.Connection("analytics")
+	require.NoError(t, err)
+	require.Same(t, analytics.conn, conn)
+
+	again, err := manager.Connection("analytics")
+	require.NoError(t, err)
+	require.Same(t, conn, again)
+	require.Equal(t, 1, analytics.connects, "connections should be opened once")
+
+	def, err := manager.Default()
+	require.NoError(t, err)
+	require.Same(t, orders.conn, def)
+
+	byEmptyName, err := manager.Connection("")
+	require.NoError(t, err)
+	require.Same(t, def, byEmptyName)
+
+	_, err = manager.Connection("billing")
+	require.ErrorIs(t, err, ErrUnknownConnection)
+}
+
+func TestManager_ConnectErrorIsNotCached(t *testing.T) {
+	connectErr := errors.New("database is starting")
+	adapter := &countingAdapter{conn: &fakeConn{}, err: connectErr}
+	manager, err := NewManager("main", map[string]*config.Config{
+		"main": {Driver: "fake", DSN: "any", Adapter: adapter},
+	})
+	require.NoError(t, err)
+
+	_, err = manager.Default()
+	require.ErrorIs(t, err, connectErr)
+
+	adapter.err = nil
+	conn, err := manager.Default()
+	require.NoError(t, err)
+	require.NotNil(t, conn)
+	require.Equal(t, 2, adapter.connects)
+}
+
+func TestManager_ConnectDoesNotBlockOpenConnections(t *testing.T) {
+	orders := &countingAdapter{conn: &fakeConn{}}
+	slow := &blockingAdapter{conn: &fakeConn{}, started: make(chan struct{}, 1), release: make(chan struct{})}
+	manager, err := NewManager("orders", map[string]*config.Config{
+		"orders": {Driver: "fake", DSN: "orders", Adapter: orders},
+		"slow":   {Driver: "fake", DSN: "slow", Adapter: slow},
+	})
+	require.NoError(t, err)
+	_, err = manager.Default()
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	conns := make([]contract.Connection, 3)
+	for i := range conns {
+		wg.Go(func() {
+			conns[i], _ = manager.Connection("slow")
+		})
+	}
+	<-slow.started
+
+	conn, err := manager.Default()
+	require.NoError(t, err, "open connections should be returned while another one connects")
+	require.Same(t, orders.conn, conn)
+
+	close(slow.release)
+	wg.Wait()
+	for _, conn := range conns {
+		require.Same(t, slow.conn, conn)
+	}
+	require.Equal(t, int32(1), slow.connects.Load(), "concurrent callers should share one connect")
+}
+
+func TestManager_OptionsDoNotModifyConfig(t *testing.T) {
+	cfg := &config.Config{Driver: "fake", DSN: "any", Adapter: &countingAdapter{conn: &fakeConn{}}}
+	option := func(cfg *config.Config) { cfg.DSN = "changed" }
+	manager, err := NewManager("main", map[string]*config.Config{"main": cfg}, option)
+	require.NoError(t, err)
+
+	_, err = manager.Default()
+	require.NoError(t, err)
+	require.Equal(t, "any", cfg.DSN)
+}
+
+func TestManager_Close(t *testing.T) {
+	manager, orders, analytics := newTestManager(t)
+	orders.conn.closeErr = errors.New("close failed")
+
+	_, err := manager.Default()
+	require.NoError(t, err)
+
+	err = manager.Close()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `close connection "orders"`)
+	require.True(t, orders.conn.closeCalled)
+	require.False(t, analytics.conn.closeCalled, "unopened connections should not be closed")
+
+	require.NoError(t, manager.Close(), "closing twice should be a no-op")
+
+	_, err = manager.Connection("analytics")
+	require.ErrorIs(t, err, ErrManagerClosed)
+}
+
+func TestManager_Stats(t *testing.T) {
+	manager, orders, _ := newTestManager(t)
+	orders.conn.stats = contract.Stats{Pools: []contract.PoolStats{{Name: "primary", InUse: 2}}}
+
+	require.Empty(t, manager.Stats(), "unopened connections have no stats")
+
+	_, err := manager.Default()
+	require.NoError(t, err)
+	stats := manager.Stats()
+	require.Len(t, stats, 1)
+	require.Equal(t, 2, stats["orders"].Pools[0].InUse)
+}
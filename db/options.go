@@ -5,6 +5,16 @@ import (
 	"github.com/next-trace/scg-database/contract"
 )
 
+// Settings keys used by the options in this package.
+const (
+	queryObserversSetting   = "query_observers"
+	queryArgRedactorSetting = "query_arg_redactor"
+	connectRetrySetting     = "connect_retry"
+)
+
+// RedactedArg replaces query arguments in QueryEvent.Args by default.
+const RedactedArg = "[REDACTED]"
+
 // WithAdapter allows injection of a custom DBAdapter at runtime, bypassing the registry.
 // This is the core of the Open/Closed Principle for this package.
 func WithAdapter(adapter contract.DBAdapter) config.Option {
@@ -12,3 +22,70 @@ func WithAdapter(adapter contract.DBAdapter) config.Option {
 		cfg.Adapter = adapter
 	}
 }
+
+// WithQueryObserver registers an observer that adapters notify after every
+// statement. It can be given several times; observers run in registration order.
+func WithQueryObserver(observer contract.QueryObserver) config.Option {
+	return func(cfg *config.Config) {
+		if cfg.Settings == nil {
+			cfg.Settings = make(map[string]any)
+		}
+		existing := QueryObservers(cfg)
+		observers := make([]contract.QueryObserver, 0, len(existing)+1)
+		cfg.Settings[queryObserversSetting] = append(append(observers, existing...), observer)
+	}
+}
+
+// WithQueryArgRedactor sets the function that redacts each query argument
+// before it is passed to observers. By default every non-nil argument is
+// replaced by RedactedArg.
+func WithQueryArgRedactor(redact func(arg any) any) config.Option {
+	return func(cfg *config.Config) {
+		if cfg.Settings == nil {
+			cfg.Settings = make(map[string]any)
+		}
+		cfg.Settings[queryArgRedactorSetting] = redact
+	}
+}
+
+// WithConnectRetry retries the adapter connect and ping of Connect and
+// ConnectContext according to policy, for databases that are still starting up.
+// A nil policy.Retryable retries every failure.
+func WithConnectRetry(policy contract.RetryPolicy) config.Option {
+	return func(cfg *config.Config) {
+		if cfg.Settings == nil {
+			cfg.Settings = make(map[string]any)
+		}
+		cfg.Settings[connectRetrySetting] = policy
+	}
+}
+
+// ConnectRetry returns the policy set with WithConnectRetry. The zero policy
+// makes a single attempt.
+func ConnectRetry(cfg *config.Config) contract.RetryPolicy {
+	policy, _ := cfg.Settings[connectRetrySetting].(contract.RetryPolicy)
+	return policy
+}
+
+// QueryObservers returns the observers registered with WithQueryObserver.
+func QueryObservers(cfg *config.Config) []contract.QueryObserver {
+	observers, _ := cfg.Settings[queryObserversSetting].([]contract.QueryObserver)
+	return observers
+}
+
+// QueryArgRedactor returns the redactor set with WithQueryArgRedactor, or the
+// default one that hides every non-nil argument.
+func QueryArgRedactor(cfg *config.Config) func(arg any) any {
+	if redact, ok := cfg.Settings[queryArgRedactorSetting].(func(arg any) any); ok && redact != nil {
+		return redact
+	}
+	return redactArg
+}
+
+// redactArg is the default query argument redactor.
+func redactArg(arg any) any {
+	if arg == nil {
+		return nil
+	}
+	return RedactedArg
+}
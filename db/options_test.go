@@ -1,11 +1,13 @@
 package db
 
 import (
+	"context"
 	"testing"
 
 	"github.com/next-trace/scg-database/config"
 	"github.com/next-trace/scg-database/contract"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // MockDBAdapter is a mock implementation of contract.DBAdapter for testing
@@ -151,3 +153,40 @@ func TestWithAdapter_NilConfig(t *testing.T) {
 		option(nil)
 	})
 }
+
+func TestWithQueryObserver(t *testing.T) {
+	cfg := &config.Config{}
+	require.Empty(t, QueryObservers(cfg))
+
+	var calls []string
+	first := contract.QueryObserverFunc(func(context.Context, contract.QueryEvent) { calls = append(calls, "first") })
+	second := contract.QueryObserverFunc(func(context.Context, contract.QueryEvent) { calls = append(calls, "second") })
+	WithQueryObserver(first)(cfg)
+	WithQueryObserver(second)(cfg)
+
+	observers := QueryObservers(cfg)
+	require.Len(t, observers, 2)
+	for _, observer := range observers {
+		observer.ObserveQuery(t.Context(), contract.QueryEvent{})
+	}
+	require.Equal(t, []string{"first", "second"}, calls)
+}
+
+func TestQueryArgRedactor(t *testing.T) {
+	cfg := &config.Config{}
+	redact := QueryArgRedactor(cfg)
+	require.Equal(t, RedactedArg, redact("secret"))
+	require.Equal(t, RedactedArg, redact(42))
+	require.Nil(t, redact(nil))
+
+	WithQueryArgRedactor(func(arg any) any { return arg })(cfg)
+	require.Equal(t, "visible", QueryArgRedactor(cfg)("visible"))
+}
+
+func TestWithConnectRetry(t *testing.T) {
+	cfg := &config.Config{}
+	require.Equal(t, contract.RetryPolicy{}, ConnectRetry(cfg), "no retry by default")
+
+	WithConnectRetry(contract.DefaultRetryPolicy())(cfg)
+	require.Equal(t, 3, ConnectRetry(cfg).MaxAttempts)
+}
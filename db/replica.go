@@ -0,0 +1,219 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sync/atomic"
+
+	"github.com/next-trace/scg-database/config"
+	"github.com/next-trace/scg-database/contract"
+)
+
+type (
+	// replicatedConnection implements contract.Connection on top of one primary
+	// and several replica connections. Reads go to a replica chosen by the
+	// configured policy; writes and transactions always go to the primary.
+	replicatedConnection struct {
+		primary  contract.Connection
+		replicas []contract.Connection
+		selector replicaSelector
+	}
+
+	// replicaSelector picks the index of the replica that serves the next read.
+	replicaSelector interface {
+		next() int
+	}
+
+	roundRobinSelector struct {
+		counter atomic.Uint64
+		size    int
+	}
+
+	randomSelector struct {
+		size int
+	}
+
+	weightedSelector struct {
+		// cumulative holds the running sum of the replica weights.
+		cumulative []int
+	}
+
+	// primaryContextKey marks a context whose reads must be served by the primary.
+	primaryContextKey struct{}
+)
+
+// Ensure the implementation satisfies the interface at compile time.
+var _ contract.Connection = (*replicatedConnection)(nil)
+
+// WithPrimary returns a context that forces reads made with it onto the primary,
+// for example to read your own writes right after a commit.
+// It has no effect on connections without replicas.
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryContextKey{}, true)
+}
+
+// PrimaryForced reports whether reads made with ctx must be served by the primary.
+func PrimaryForced(ctx context.Context) bool {
+	forced, _ := ctx.Value(primaryContextKey{}).(bool)
+	return forced
+}
+
+// connectReplicas opens every replica of cfg with the primary's adapter and
+// wraps them, together with the primary, into a routing connection.
+func connectReplicas(
+	ctx context.Context,
+	adapter contract.DBAdapter,
+	cfg *config.Config,
+	primary contract.Connection,
+) (contract.Connection, error) {
+	replicas := make([]contract.Connection, 0, len(cfg.Replicas))
+	for _, replica := range cfg.Replicas {
+		replicaCfg := *cfg
+		replicaCfg.DSN = replica.DSN
+		replicaCfg.Replicas = nil
+
+		conn, err := openWithRetry(ctx, adapter, &replicaCfg)
+		if err != nil {
+			closeAll(append(replicas, primary))
+			return nil, err
+		}
+		replicas = append(replicas, conn)
+	}
+
+	return &replicatedConnection{
+		primary:  primary,
+		replicas: replicas,
+		selector: newReplicaSelector(cfg.ReplicaPolicy, cfg.Replicas),
+	}, nil
+}
+
+// newReplicaSelector builds the selector for the given policy.
+func newReplicaSelector(policy config.ReplicaPolicy, replicas []config.Replica) replicaSelector {
+	switch policy {
+	case config.ReplicaPolicyRandom:
+		return &randomSelector{size: len(replicas)}
+	case config.ReplicaPolicyWeighted:
+		cumulative := make([]int, len(replicas))
+		total := 0
+		for i, replica := range replicas {
+			total += max(replica.Weight, 1)
+			cumulative[i] = total
+		}
+		return &weightedSelector{cumulative: cumulative}
+	default:
+		return &roundRobinSelector{size: len(replicas)}
+	}
+}
+
+func (s *roundRobinSelector) next() int {
+	return int((s.counter.Add(1) - 1) % uint64(s.size)) //nolint:gosec // size is a small positive slice length
+}
+
+func (s *randomSelector) next() int {
+	return rand.IntN(s.size) //nolint:gosec // load balancing does not need a cryptographic source
+}
+
+func (s *weightedSelector) next() int {
+	pick := rand.IntN(s.cumulative[len(s.cumulative)-1]) //nolint:gosec // see randomSelector
+	for i, bound := range s.cumulative {
+		if pick < bound {
+			return i
+		}
+	}
+	return len(s.cumulative) - 1
+}
+
+// closeAll closes every connection, ignoring errors; it is used on failed setup.
+func closeAll(conns []contract.Connection) {
+	for _, conn := range conns {
+		_ = conn.Close()
+	}
+}
+
+// reader returns the connection that should serve a read made with ctx.
+// Reads that may join an ambient transaction (see WithTx) stay on the primary.
+func (c *replicatedConnection) reader(ctx context.Context) contract.Connection {
+	if _, inTx := TxFromContext(ctx); inTx || PrimaryForced(ctx) {
+		return c.primary
+	}
+	return c.replicas[c.selector.next()]
+}
+
+// GetConnection returns the primary's underlying connection.
+func (c *replicatedConnection) GetConnection() any {
+	return c.primary.GetConnection()
+}
+
+// Ping pings the primary and every replica.
+func (c *replicatedConnection) Ping(ctx context.Context) error {
+	errs := []error{c.primary.Ping(ctx)}
+	for _, replica := range c.replicas {
+		errs = append(errs, replica.Ping(ctx))
+	}
+	return errors.Join(errs...)
+}
+
+// Stats reports the primary's pools followed by every replica's, named
+// "primary" and "replica-<n>".
+func (c *replicatedConnection) Stats() contract.Stats {
+	pools := labelPools("primary", c.primary.Stats().Pools)
+	for i, replica := range c.replicas {
+		pools = append(pools, labelPools(fmt.Sprintf("replica-%d", i), replica.Stats().Pools)...)
+	}
+	return contract.Stats{Pools: pools}
+}
+
+// labelPools names pools after the connection they belong to. A connection with
+// several pools keeps their own names as a suffix.
+func labelPools(label string, pools []contract.PoolStats) []contract.PoolStats {
+	labeled := make([]contract.PoolStats, len(pools))
+	for i, pool := range pools {
+		labeled[i] = pool
+		if len(pools) == 1 || pool.Name == "" {
+			labeled[i].Name = label
+		} else {
+			labeled[i].Name = label + "/" + pool.Name
+		}
+	}
+	return labeled
+}
+
+// Close closes the primary and every replica.
+func (c *replicatedConnection) Close() error {
+	errs := []error{c.primary.Close()}
+	for _, replica := range c.replicas {
+		errs = append(errs, replica.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// NewRepository returns a repository that reads from replicas and writes to the primary.
+func (c *replicatedConnection) NewRepository(model contract.Model) (contract.Repository, error) {
+	// Build one primary repository up front so invalid models fail here, as they do without replicas.
+	if _, err := c.primary.NewRepository(model); err != nil {
+		return nil, err
+	}
+	return &replicatedRepository{conn: c, model: model}, nil
+}
+
+// Transaction always runs on the primary, read-only transactions included.
+func (c *replicatedConnection) Transaction(
+	ctx context.Context,
+	fn func(contract.Connection) error,
+	opts ...contract.TxOption,
+) error {
+	return c.primary.Transaction(ctx, fn, opts...)
+}
+
+// Select runs a raw read query on a replica.
+func (c *replicatedConnection) Select(ctx context.Context, query string, bindings ...any) ([]map[string]any, error) {
+	return c.reader(ctx).Select(ctx, query, bindings...)
+}
+
+// Statement runs a raw write query on the primary.
+func (c *replicatedConnection) Statement(ctx context.Context, query string, bindings ...any) (sql.Result, error) {
+	return c.primary.Statement(ctx, query, bindings...)
+}
@@ -0,0 +1,424 @@
+package db
+
+import (
+	"context"
+	"slices"
+
+	"github.com/next-trace/scg-database/contract"
+)
+
+type (
+	// replicatedRepository sends reads to a replica and writes to the primary.
+	// Fluent calls are recorded and replayed on a fresh repository of the
+	// connection picked when the query finally runs.
+	replicatedRepository struct {
+		conn  *replicatedConnection
+		model contract.Model
+		steps []func(contract.Repository) contract.Repository
+	}
+
+	// replicatedQueryBuilder applies the same routing to the fluent query builder.
+	replicatedQueryBuilder struct {
+		repo  *replicatedRepository
+		steps []func(contract.QueryBuilder) contract.QueryBuilder
+	}
+)
+
+// Ensure the implementations satisfy the interfaces at compile time.
+var (
+	_ contract.Repository   = (*replicatedRepository)(nil)
+	_ contract.QueryBuilder = (*replicatedQueryBuilder)(nil)
+)
+
+// --- Repository routing ---
+
+// chain records step so it can be replayed on the connection that runs the query.
+func (r *replicatedRepository) chain(step func(contract.Repository) contract.Repository) contract.Repository {
+	return &replicatedRepository{
+		conn:  r.conn,
+		model: r.model,
+		steps: append(slices.Clip(r.steps), step),
+	}
+}
+
+// on builds the repository for conn and replays the recorded fluent calls on it.
+func (r *replicatedRepository) on(conn contract.Connection) (contract.Repository, error) {
+	repo, err := conn.NewRepository(r.model)
+	if err != nil {
+		return nil, err
+	}
+	for _, step := range r.steps {
+		repo = step(repo)
+	}
+	return repo, nil
+}
+
+// reader returns the repository that should serve a read made with ctx.
+func (r *replicatedRepository) reader(ctx context.Context) (contract.Repository, error) {
+	return r.on(r.conn.reader(ctx))
+}
+
+// writer returns the repository that runs writes on the primary.
+func (r *replicatedRepository) writer() (contract.Repository, error) {
+	return r.on(r.conn.primary)
+}
+
+func (r *replicatedRepository) With(relations ...string) contract.Repository {
+	return r.chain(func(repo contract.Repository) contract.Repository { return repo.With(relations...) })
+}
+
+func (r *replicatedRepository) Where(query any, args ...any) contract.Repository {
+	return r.chain(func(repo contract.Repository) contract.Repository { return repo.Where(query, args...) })
+}
+
+func (r *replicatedRepository) Unscoped() contract.Repository {
+	return r.chain(func(repo contract.Repository) contract.Repository { return repo.Unscoped() })
+}
+
+func (r *replicatedRepository) Limit(limit int) contract.Repository {
+	return r.chain(func(repo contract.Repository) contract.Repository { return repo.Limit(limit) })
+}
+
+func (r *replicatedRepository) Offset(offset int) contract.Repository {
+	return r.chain(func(repo contract.Repository) contract.Repository { return repo.Offset(offset) })
+}
+
+func (r *replicatedRepository) OrderBy(column, direction string) contract.Repository {
+	return r.chain(func(repo contract.Repository) contract.Repository { return repo.OrderBy(column, direction) })
+}
+
+// --- Reads (replica) ---
+
+func (r *replicatedRepository) Find(ctx context.Context, id any) (contract.Model, error) {
+	repo, err := r.reader(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return repo.Find(ctx, id)
+}
+
+func (r *replicatedRepository) FindOrFail(ctx context.Context, id any) (contract.Model, error) {
+	repo, err := r.reader(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return repo.FindOrFail(ctx, id)
+}
+
+func (r *replicatedRepository) First(ctx context.Context) (contract.Model, error) {
+	repo, err := r.reader(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return repo.First(ctx)
+}
+
+func (r *replicatedRepository) FirstOrFail(ctx context.Context) (contract.Model, error) {
+	repo, err := r.reader(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return repo.FirstOrFail(ctx)
+}
+
+func (r *replicatedRepository) Get(ctx context.Context) ([]contract.Model, error) {
+	repo, err := r.reader(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return repo.Get(ctx)
+}
+
+func (r *replicatedRepository) Pluck(ctx context.Context, column string, dest any) error {
+	repo, err := r.reader(ctx)
+	if err != nil {
+		return err
+	}
+	return repo.Pluck(ctx, column, dest)
+}
+
+// --- Writes (primary) ---
+
+func (r *replicatedRepository) Create(ctx context.Context, models ...contract.Model) error {
+	repo, err := r.writer()
+	if err != nil {
+		return err
+	}
+	return repo.Create(ctx, models...)
+}
+
+func (r *replicatedRepository) CreateInBatches(ctx context.Context, models []contract.Model, batchSize int) error {
+	repo, err := r.writer()
+	if err != nil {
+		return err
+	}
+	return repo.CreateInBatches(ctx, models, batchSize)
+}
+
+func (r *replicatedRepository) Update(ctx context.Context, models ...contract.Model) error {
+	repo, err := r.writer()
+	if err != nil {
+		return err
+	}
+	return repo.Update(ctx, models...)
+}
+
+func (r *replicatedRepository) Delete(ctx context.Context, models ...contract.Model) error {
+	repo, err := r.writer()
+	if err != nil {
+		return err
+	}
+	return repo.Delete(ctx, models...)
+}
+
+func (r *replicatedRepository) ForceDelete(ctx context.Context, models ...contract.Model) error {
+	repo, err := r.writer()
+	if err != nil {
+		return err
+	}
+	return repo.ForceDelete(ctx, models...)
+}
+
+// FirstOrCreate may write, so it always runs on the primary.
+func (r *replicatedRepository) FirstOrCreate(
+	ctx context.Context,
+	condition contract.Model,
+	create ...contract.Model,
+) (contract.Model, error) {
+	repo, err := r.writer()
+	if err != nil {
+		return nil, err
+	}
+	return repo.FirstOrCreate(ctx, condition, create...)
+}
+
+// UpdateOrCreate may write, so it always runs on the primary.
+func (r *replicatedRepository) UpdateOrCreate(
+	ctx context.Context,
+	condition contract.Model,
+	values any,
+) (contract.Model, error) {
+	repo, err := r.writer()
+	if err != nil {
+		return nil, err
+	}
+	return repo.UpdateOrCreate(ctx, condition, values)
+}
+
+// QueryBuilder returns a query builder with the same read/write routing.
+func (r *replicatedRepository) QueryBuilder() contract.QueryBuilder {
+	return &replicatedQueryBuilder{repo: r}
+}
+
+// --- QueryBuilder routing ---
+
+// chain records step so it can be replayed on the connection that runs the query.
+func (q *replicatedQueryBuilder) chain(step func(contract.QueryBuilder) contract.QueryBuilder) contract.QueryBuilder {
+	return &replicatedQueryBuilder{
+		repo:  q.repo,
+		steps: append(slices.Clip(q.steps), step),
+	}
+}
+
+// on builds the query builder for conn and replays the recorded fluent calls on it.
+func (q *replicatedQueryBuilder) on(conn contract.Connection) (contract.QueryBuilder, error) {
+	repo, err := q.repo.on(conn)
+	if err != nil {
+		return nil, err
+	}
+	builder := repo.QueryBuilder()
+	for _, step := range q.steps {
+		builder = step(builder)
+	}
+	return builder, nil
+}
+
+// reader returns the query builder that should serve a read made with ctx.
+func (q *replicatedQueryBuilder) reader(ctx context.Context) (contract.QueryBuilder, error) {
+	return q.on(q.repo.conn.reader(ctx))
+}
+
+// writer returns the query builder that runs writes on the primary.
+func (q *replicatedQueryBuilder) writer() (contract.QueryBuilder, error) {
+	return q.on(q.repo.conn.primary)
+}
+
+func (q *replicatedQueryBuilder) Select(columns ...string) contract.QueryBuilder {
+	return q.chain(func(b contract.QueryBuilder) contract.QueryBuilder { return b.Select(columns...) })
+}
+
+func (q *replicatedQueryBuilder) Where(condition string, args ...any) contract.QueryBuilder {
+	return q.chain(func(b contract.QueryBuilder) contract.QueryBuilder { return b.Where(condition, args...) })
+}
+
+func (q *replicatedQueryBuilder) WhereIn(column string, values []any) contract.QueryBuilder {
+	return q.chain(func(b contract.QueryBuilder) contract.QueryBuilder { return b.WhereIn(column, values) })
+}
+
+func (q *replicatedQueryBuilder) WhereNotIn(column string, values []any) contract.QueryBuilder {
+	return q.chain(func(b contract.QueryBuilder) contract.QueryBuilder { return b.WhereNotIn(column, values) })
+}
+
+func (q *replicatedQueryBuilder) WhereNull(column string) contract.QueryBuilder {
+	return q.chain(func(b contract.QueryBuilder) contract.QueryBuilder { return b.WhereNull(column) })
+}
+
+func (q *replicatedQueryBuilder) WhereNotNull(column string) contract.QueryBuilder {
+	return q.chain(func(b contract.QueryBuilder) contract.QueryBuilder { return b.WhereNotNull(column) })
+}
+
+func (q *replicatedQueryBuilder) WhereBetween(column string, start, end any) contract.QueryBuilder {
+	return q.chain(func(b contract.QueryBuilder) contract.QueryBuilder { return b.WhereBetween(column, start, end) })
+}
+
+func (q *replicatedQueryBuilder) OrWhere(condition string, args ...any) contract.QueryBuilder {
+	return q.chain(func(b contract.QueryBuilder) contract.QueryBuilder { return b.OrWhere(condition, args...) })
+}
+
+func (q *replicatedQueryBuilder) Join(table, condition string) contract.QueryBuilder {
+	return q.chain(func(b contract.QueryBuilder) contract.QueryBuilder { return b.Join(table, condition) })
+}
+
+func (q *replicatedQueryBuilder) LeftJoin(table, condition string) contract.QueryBuilder {
+	return q.chain(func(b contract.QueryBuilder) contract.QueryBuilder { return b.LeftJoin(table, condition) })
+}
+
+func (q *replicatedQueryBuilder) RightJoin(table, condition string) contract.QueryBuilder {
+	return q.chain(func(b contract.QueryBuilder) contract.QueryBuilder { return b.RightJoin(table, condition) })
+}
+
+func (q *replicatedQueryBuilder) InnerJoin(table, condition string) contract.QueryBuilder {
+	return q.chain(func(b contract.QueryBuilder) contract.QueryBuilder { return b.InnerJoin(table, condition) })
+}
+
+func (q *replicatedQueryBuilder) OrderBy(column, direction string) contract.QueryBuilder {
+	return q.chain(func(b contract.QueryBuilder) contract.QueryBuilder { return b.OrderBy(column, direction) })
+}
+
+func (q *replicatedQueryBuilder) GroupBy(columns ...string) contract.QueryBuilder {
+	return q.chain(func(b contract.QueryBuilder) contract.QueryBuilder { return b.GroupBy(columns...) })
+}
+
+func (q *replicatedQueryBuilder) Having(condition string, args ...any) contract.QueryBuilder {
+	return q.chain(func(b contract.QueryBuilder) contract.QueryBuilder { return b.Having(condition, args...) })
+}
+
+func (q *replicatedQueryBuilder) Limit(limit int) contract.QueryBuilder {
+	return q.chain(func(b contract.QueryBuilder) contract.QueryBuilder { return b.Limit(limit) })
+}
+
+func (q *replicatedQueryBuilder) Offset(offset int) contract.QueryBuilder {
+	return q.chain(func(b contract.QueryBuilder) contract.QueryBuilder { return b.Offset(offset) })
+}
+
+func (q *replicatedQueryBuilder) With(relations ...string) contract.QueryBuilder {
+	return q.chain(func(b contract.QueryBuilder) contract.QueryBuilder { return b.With(relations...) })
+}
+
+func (q *replicatedQueryBuilder) WithCount(relations ...string) contract.QueryBuilder {
+	return q.chain(func(b contract.QueryBuilder) contract.QueryBuilder { return b.WithCount(relations...) })
+}
+
+func (q *replicatedQueryBuilder) Scoped() contract.QueryBuilder {
+	return q.chain(func(b contract.QueryBuilder) contract.QueryBuilder { return b.Scoped() })
+}
+
+func (q *replicatedQueryBuilder) Unscoped() contract.QueryBuilder {
+	return q.chain(func(b contract.QueryBuilder) contract.QueryBuilder { return b.Unscoped() })
+}
+
+func (q *replicatedQueryBuilder) Raw(query string, args ...any) contract.QueryBuilder {
+	return q.chain(func(b contract.QueryBuilder) contract.QueryBuilder { return b.Raw(query, args...) })
+}
+
+func (q *replicatedQueryBuilder) Clone() contract.QueryBuilder {
+	return q.chain(func(b contract.QueryBuilder) contract.QueryBuilder { return b.Clone() })
+}
+
+func (q *replicatedQueryBuilder) Reset() contract.QueryBuilder {
+	return q.chain(func(b contract.QueryBuilder) contract.QueryBuilder { return b.Reset() })
+}
+
+// --- QueryBuilder reads (replica) ---
+
+func (q *replicatedQueryBuilder) Find(ctx context.Context, dest any) error {
+	builder, err := q.reader(ctx)
+	if err != nil {
+		return err
+	}
+	return builder.Find(ctx, dest)
+}
+
+func (q *replicatedQueryBuilder) First(ctx context.Context, dest any) error {
+	builder, err := q.reader(ctx)
+	if err != nil {
+		return err
+	}
+	return builder.First(ctx, dest)
+}
+
+func (q *replicatedQueryBuilder) Get(ctx context.Context, dest any) error {
+	builder, err := q.reader(ctx)
+	if err != nil {
+		return err
+	}
+	return builder.Get(ctx, dest)
+}
+
+func (q *replicatedQueryBuilder) Count(ctx context.Context) (int64, error) {
+	builder, err := q.reader(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return builder.Count(ctx)
+}
+
+func (q *replicatedQueryBuilder) Exists(ctx context.Context) (bool, error) {
+	builder, err := q.reader(ctx)
+	if err != nil {
+		return false, err
+	}
+	return builder.Exists(ctx)
+}
+
+// --- QueryBuilder writes (primary) ---
+
+func (q *replicatedQueryBuilder) Create(ctx context.Context, value any) error {
+	builder, err := q.writer()
+	if err != nil {
+		return err
+	}
+	return builder.Create(ctx, value)
+}
+
+func (q *replicatedQueryBuilder) Update(ctx context.Context, values any) error {
+	builder, err := q.writer()
+	if err != nil {
+		return err
+	}
+	return builder.Update(ctx, values)
+}
+
+func (q *replicatedQueryBuilder) Delete(ctx context.Context) error {
+	builder, err := q.writer()
+	if err != nil {
+		return err
+	}
+	return builder.Delete(ctx)
+}
+
+func (q *replicatedQueryBuilder) Exec(ctx context.Context, query string, args ...any) error {
+	builder, err := q.writer()
+	if err != nil {
+		return err
+	}
+	return builder.Exec(ctx, query, args...)
+}
+
+func (q *replicatedQueryBuilder) ToSQL() (query string, args []any, err error) {
+	builder, err := q.writer()
+	if err != nil {
+		return "", nil, err
+	}
+	return builder.ToSQL()
+}
@@ -0,0 +1,186 @@
+package db_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	gormadapter "github.com/next-trace/scg-database/adapter/gorm"
+	"github.com/next-trace/scg-database/config"
+	"github.com/next-trace/scg-database/contract"
+	"github.com/next-trace/scg-database/db"
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	// node is a minimal model whose rows tell which database served a read.
+	node struct {
+		ID     uint `gorm:"primaryKey"`
+		Source string
+	}
+)
+
+func (n *node) PrimaryKey() string                              { return "id" }
+func (n *node) TableName() string                               { return "nodes" }
+func (n *node) GetID() any                                      { return n.ID }
+func (n *node) SetID(id any)                                    { n.ID = id.(uint) }
+func (n *node) Relationships() map[string]contract.Relationship { return nil }
+
+// seedDatabase creates a SQLite database file holding one row labelled with source.
+func seedDatabase(t *testing.T, path, source string) {
+	t.Helper()
+	conn, err := db.Connect(&config.Config{Driver: testSQLiteDriver, DSN: path})
+	require.NoError(t, err)
+	defer conn.Close()
+
+	ctx := t.Context()
+	_, err = conn.Statement(ctx, "CREATE TABLE nodes (id INTEGER PRIMARY KEY AUTOINCREMENT, source TEXT)")
+	require.NoError(t, err)
+	_, err = conn.Statement(ctx, "INSERT INTO nodes (source) VALUES (?)", source)
+	require.NoError(t, err)
+}
+
+// connectReplicated opens a primary and the given replicas, each seeded with its own label.
+func connectReplicated(t *testing.T, policy config.ReplicaPolicy, replicas ...config.Replica) contract.Connection {
+	t.Helper()
+	gormadapter.Register()
+
+	dir := t.TempDir()
+	primary := filepath.Join(dir, "primary.db")
+	seedDatabase(t, primary, "primary")
+	for i := range replicas {
+		path := filepath.Join(dir, replicas[i].DSN)
+		seedDatabase(t, path, replicas[i].DSN)
+		replicas[i].DSN = path
+	}
+
+	conn, err := db.Connect(&config.Config{
+		Driver:        testSQLiteDriver,
+		DSN:           primary,
+		Replicas:      replicas,
+		ReplicaPolicy: policy,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+// readSource returns the label of the database that served a repository read.
+func readSource(ctx context.Context, t *testing.T, repo contract.Repository) string {
+	t.Helper()
+	found, err := repo.OrderBy("id", "ASC").First(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, found)
+	return found.(*node).Source
+}
+
+func TestConnect_Replicas_RoutesReadsAndWrites(t *testing.T) {
+	conn := connectReplicated(t, config.ReplicaPolicyRoundRobin, config.Replica{DSN: "replica.db"})
+	ctx := t.Context()
+
+	repo, err := conn.NewRepository(&node{})
+	require.NoError(t, err)
+
+	t.Run("RepositoryReadsUseReplica", func(t *testing.T) {
+		require.Equal(t, "replica.db", readSource(ctx, t, repo))
+
+		found, err := repo.Find(ctx, 1)
+		require.NoError(t, err)
+		require.Equal(t, "replica.db", found.(*node).Source)
+	})
+
+	t.Run("SelectUsesReplica", func(t *testing.T) {
+		rows, err := conn.Select(ctx, "SELECT source FROM nodes")
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		require.Equal(t, "replica.db", rows[0]["source"])
+	})
+
+	t.Run("QueryBuilderReadsUseReplica", func(t *testing.T) {
+		var rows []node
+		require.NoError(t, repo.QueryBuilder().Where("id = ?", 1).Get(ctx, &rows))
+		require.Len(t, rows, 1)
+		require.Equal(t, "replica.db", rows[0].Source)
+	})
+
+	t.Run("WritesUsePrimary", func(t *testing.T) {
+		require.NoError(t, repo.Create(ctx, &node{Source: "written"}))
+		_, err := conn.Statement(ctx, "INSERT INTO nodes (source) VALUES (?)", "statement")
+		require.NoError(t, err)
+
+		primaryRows, err := conn.Select(db.WithPrimary(ctx), "SELECT source FROM nodes ORDER BY id")
+		require.NoError(t, err)
+		require.Len(t, primaryRows, 3)
+
+		replicaRows, err := conn.Select(ctx, "SELECT source FROM nodes")
+		require.NoError(t, err)
+		require.Len(t, replicaRows, 1)
+	})
+
+	t.Run("TransactionUsesPrimary", func(t *testing.T) {
+		err := conn.Transaction(ctx, func(tx contract.Connection) error {
+			rows, err := tx.Select(ctx, "SELECT source FROM nodes WHERE id = 1")
+			require.NoError(t, err)
+			require.Equal(t, "primary", rows[0]["source"])
+			return nil
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("WithPrimaryForcesReads", func(t *testing.T) {
+		primaryCtx := db.WithPrimary(ctx)
+		require.True(t, db.PrimaryForced(primaryCtx))
+		require.False(t, db.PrimaryForced(ctx))
+
+		require.Equal(t, "primary", readSource(primaryCtx, t, repo))
+
+		count, err := repo.QueryBuilder().Count(primaryCtx)
+		require.NoError(t, err)
+		require.Equal(t, int64(3), count)
+	})
+
+	t.Run("PingAndGetConnection", func(t *testing.T) {
+		require.NoError(t, conn.Ping(ctx))
+		require.NotNil(t, conn.GetConnection())
+	})
+}
+
+func TestConnect_Replicas_RoundRobin(t *testing.T) {
+	conn := connectReplicated(t, config.ReplicaPolicyRoundRobin,
+		config.Replica{DSN: "a.db"}, config.Replica{DSN: "b.db"})
+	repo, err := conn.NewRepository(&node{})
+	require.NoError(t, err)
+
+	ctx := t.Context()
+	sources := make([]string, 4)
+	for i := range sources {
+		sources[i] = readSource(ctx, t, repo)
+	}
+	require.Equal(t, []string{"a.db", "b.db", "a.db", "b.db"}, sources)
+}
+
+func TestConnect_Replicas_RandomAndWeighted(t *testing.T) {
+	t.Run("Random", func(t *testing.T) {
+		conn := connectReplicated(t, config.ReplicaPolicyRandom,
+			config.Replica{DSN: "a.db"}, config.Replica{DSN: "b.db"})
+		repo, err := conn.NewRepository(&node{})
+		require.NoError(t, err)
+
+		for range 10 {
+			require.Contains(t, []string{"a.db", "b.db"}, readSource(t.Context(), t, repo))
+		}
+	})
+
+	t.Run("Weighted", func(t *testing.T) {
+		conn := connectReplicated(t, config.ReplicaPolicyWeighted,
+			config.Replica{DSN: "heavy.db", Weight: 50}, config.Replica{DSN: "light.db", Weight: 1})
+		repo, err := conn.NewRepository(&node{})
+		require.NoError(t, err)
+
+		counts := map[string]int{}
+		for range 100 {
+			counts[readSource(t.Context(), t, repo)]++
+		}
+		require.Greater(t, counts["heavy.db"], counts["light.db"])
+	})
+}
@@ -0,0 +1,211 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/next-trace/scg-database/contract"
+)
+
+type (
+	// Repo is a type-safe wrapper of a contract.Repository of T, the pointer
+	// type of a model such as *User. Its reads return T and []T instead of
+	// contract.Model, and its writes only accept T. A model of another type
+	// returned by the underlying repository fails with ErrModelType.
+	//
+	// T is the pointer type, rather than the struct type with reads returning
+	// *T, because models implement contract.Model on their pointer: a struct
+	// type parameter would need a second one for its pointer, and every Repo
+	// type would then be spelled Repo[User, *User].
+	//
+	// Like the repositories it wraps, a Repo is immutable: fluent calls return
+	// a new Repo and leave the receiver unchanged.
+	Repo[T contract.Model] struct {
+		repo contract.Repository
+	}
+)
+
+// NewRepo returns a Repo of T on conn:
+//
+//	users, err := db.NewRepo[*User](conn)
+//	user, err := users.Where("email = ?", email).FirstOrFail(ctx) // user is a *User
+func NewRepo[T contract.Model](conn contract.Connection) (*Repo[T], error) {
+	model, err := newModel[T]()
+	if err != nil {
+		return nil, err
+	}
+	repo, err := conn.NewRepository(model)
+	if err != nil {
+		return nil, err
+	}
+	return RepoOf[T](repo), nil
+}
+
+// RepoOf wraps repo, a repository of T such as one returned by
+// Connection.NewRepository, in a Repo.
+func RepoOf[T contract.Model](repo contract.Repository) *Repo[T] {
+	return &Repo[T]{repo: repo}
+}
+
+// newModel returns a new, empty T to create the repository with. T must be a
+// pointer type: a model held by value cannot receive generated keys.
+func newModel[T contract.Model]() (T, error) {
+	var zero T
+	typ := reflect.TypeFor[T]()
+	if typ.Kind() != reflect.Pointer {
+		return zero, NewError("NewRepo", fmt.Sprintf("%s is not a pointer to a model", typ), ErrModelType)
+	}
+	model, _ := reflect.New(typ.Elem()).Interface().(T)
+	return model, nil
+}
+
+// Repository returns the wrapped repository.
+func (r *Repo[T]) Repository() contract.Repository {
+	return r.repo
+}
+
+// --- Query Building ---
+
+// With eager loads relations.
+func (r *Repo[T]) With(relations ...string) *Repo[T] {
+	return RepoOf[T](r.repo.With(relations...))
+}
+
+// Where adds a condition.
+func (r *Repo[T]) Where(query any, args ...any) *Repo[T] {
+	return RepoOf[T](r.repo.Where(query, args...))
+}
+
+// Unscoped includes soft deleted models.
+func (r *Repo[T]) Unscoped() *Repo[T] {
+	return RepoOf[T](r.repo.Unscoped())
+}
+
+// Limit limits the number of models read.
+func (r *Repo[T]) Limit(limit int) *Repo[T] {
+	return RepoOf[T](r.repo.Limit(limit))
+}
+
+// Offset skips the first offset models.
+func (r *Repo[T]) Offset(offset int) *Repo[T] {
+	return RepoOf[T](r.repo.Offset(offset))
+}
+
+// OrderBy sorts the models by column in direction, "asc" or "desc".
+func (r *Repo[T]) OrderBy(column, direction string) *Repo[T] {
+	return RepoOf[T](r.repo.OrderBy(column, direction))
+}
+
+// QueryBuilder returns the fluent query builder of the wrapped repository.
+func (r *Repo[T]) QueryBuilder() contract.QueryBuilder {
+	return r.repo.QueryBuilder()
+}
+
+// --- Read Operations ---
+
+// Find returns the model with primary key id, or the zero T and no error when
+// there is none.
+func (r *Repo[T]) Find(ctx context.Context, id any) (T, error) {
+	return typed[T]("Find")(r.repo.Find(ctx, id))
+}
+
+// FindOrFail returns the model with primary key id, or ErrRecordNotFound.
+func (r *Repo[T]) FindOrFail(ctx context.Context, id any) (T, error) {
+	return typed[T]("FindOrFail")(r.repo.FindOrFail(ctx, id))
+}
+
+// First returns the first matching model, or the zero T and no error when
+// there is none.
+func (r *Repo[T]) First(ctx context.Context) (T, error) {
+	return typed[T]("First")(r.repo.First(ctx))
+}
+
+// FirstOrFail returns the first matching model, or ErrRecordNotFound.
+func (r *Repo[T]) FirstOrFail(ctx context.Context) (T, error) {
+	return typed[T]("FirstOrFail")(r.repo.FirstOrFail(ctx))
+}
+
+// Get returns every matching model.
+func (r *Repo[T]) Get(ctx context.Context) ([]T, error) {
+	models, err := r.repo.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]T, len(models))
+	for i, model := range models {
+		if result[i], err = typed[T]("Get")(model, nil); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// Pluck reads column of the matching models into dest, a pointer to a slice.
+func (r *Repo[T]) Pluck(ctx context.Context, column string, dest any) error {
+	return r.repo.Pluck(ctx, column, dest)
+}
+
+// --- Write Operations ---
+
+// Create inserts models.
+func (r *Repo[T]) Create(ctx context.Context, models ...T) error {
+	return r.repo.Create(ctx, untyped(models)...)
+}
+
+// CreateInBatches inserts models batchSize at a time.
+func (r *Repo[T]) CreateInBatches(ctx context.Context, models []T, batchSize int) error {
+	return r.repo.CreateInBatches(ctx, untyped(models), batchSize)
+}
+
+// Update saves models.
+func (r *Repo[T]) Update(ctx context.Context, models ...T) error {
+	return r.repo.Update(ctx, untyped(models)...)
+}
+
+// Delete deletes models, softly when T supports soft deletes.
+func (r *Repo[T]) Delete(ctx context.Context, models ...T) error {
+	return r.repo.Delete(ctx, untyped(models)...)
+}
+
+// ForceDelete deletes models permanently.
+func (r *Repo[T]) ForceDelete(ctx context.Context, models ...T) error {
+	return r.repo.ForceDelete(ctx, untyped(models)...)
+}
+
+// FirstOrCreate returns the first model matching condition, creating it from
+// condition, or from create when given, when there is none.
+func (r *Repo[T]) FirstOrCreate(ctx context.Context, condition T, create ...T) (T, error) {
+	return typed[T]("FirstOrCreate")(r.repo.FirstOrCreate(ctx, condition, untyped(create)...))
+}
+
+// UpdateOrCreate updates the first model matching condition with values, or
+// creates it when there is none.
+func (r *Repo[T]) UpdateOrCreate(ctx context.Context, condition T, values any) (T, error) {
+	return typed[T]("UpdateOrCreate")(r.repo.UpdateOrCreate(ctx, condition, values))
+}
+
+// typed returns a function that converts the result of operation to T. A nil
+// model is the zero T; a model of another type fails with ErrModelType.
+func typed[T contract.Model](operation string) func(contract.Model, error) (T, error) {
+	return func(model contract.Model, err error) (T, error) {
+		var zero T
+		if err != nil || model == nil {
+			return zero, err
+		}
+		result, ok := model.(T)
+		if !ok {
+			return zero, NewModelTypeError(operation, model, zero)
+		}
+		return result, nil
+	}
+}
+
+// untyped converts models to the contract.Model values the repository takes.
+func untyped[T contract.Model](models []T) []contract.Model {
+	result := make([]contract.Model, len(models))
+	for i, model := range models {
+		result[i] = model
+	}
+	return result
+}
@@ -0,0 +1,166 @@
+package db_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/next-trace/scg-database/adapter/memory"
+	"github.com/next-trace/scg-database/config"
+	"github.com/next-trace/scg-database/contract"
+	"github.com/next-trace/scg-database/contract/mock"
+	"github.com/next-trace/scg-database/db"
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	// account is the model of the typed repository tests.
+	account struct {
+		ID    uint
+		Name  string
+		Email string
+		Age   int
+		Posts []*post
+	}
+
+	post struct {
+		ID        uint
+		AccountID uint
+		Title     string
+	}
+
+	// valueModel implements contract.Model on its value.
+	valueModel struct{}
+)
+
+func (a *account) PrimaryKey() string { return "id" }
+func (a *account) TableName() string  { return "accounts" }
+func (a *account) GetID() any         { return a.ID }
+func (a *account) SetID(id any)       { a.ID = id.(uint) }
+func (a *account) Relationships() map[string]contract.Relationship {
+	return map[string]contract.Relationship{"Posts": contract.NewHasMany(&post{}, "account_id", "id")}
+}
+
+func (p *post) PrimaryKey() string                              { return "id" }
+func (p *post) TableName() string                               { return "posts" }
+func (p *post) GetID() any                                      { return p.ID }
+func (p *post) SetID(id any)                                    { p.ID = id.(uint) }
+func (p *post) Relationships() map[string]contract.Relationship { return nil }
+
+func (valueModel) PrimaryKey() string                              { return "id" }
+func (valueModel) TableName() string                               { return "values" }
+func (valueModel) GetID() any                                      { return nil }
+func (valueModel) SetID(any)                                       {}
+func (valueModel) Relationships() map[string]contract.Relationship { return nil }
+
+// setupAccounts returns a typed repository of accounts on a new in-memory database.
+func setupAccounts(t *testing.T) (contract.Connection, *db.Repo[*account]) {
+	t.Helper()
+	memory.Register()
+	conn, err := db.Connect(&config.Config{Driver: memory.AdapterName, DSN: memory.AdapterName})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	accounts, err := db.NewRepo[*account](conn)
+	require.NoError(t, err)
+	return conn, accounts
+}
+
+func TestRepo_CRUD(t *testing.T) {
+	_, accounts := setupAccounts(t)
+	ctx := t.Context()
+
+	ada := &account{Name: "Ada", Email: "ada@example.com", Age: 36}
+	require.NoError(t, accounts.Create(ctx, ada, &account{Name: "Bob", Email: "bob@example.com", Age: 17}))
+	require.NotZero(t, ada.ID)
+
+	found, err := accounts.FindOrFail(ctx, ada.ID)
+	require.NoError(t, err)
+	require.Equal(t, "Ada", found.Name)
+
+	missing, err := accounts.Find(ctx, 999)
+	require.NoError(t, err)
+	require.Nil(t, missing)
+	_, err = accounts.FindOrFail(ctx, 999)
+	require.ErrorIs(t, err, db.ErrRecordNotFound)
+
+	found.Age = 37
+	require.NoError(t, accounts.Update(ctx, found))
+	adults, err := accounts.Where("age >= ?", 18).OrderBy("name", "asc").Limit(10).Get(ctx)
+	require.NoError(t, err)
+	require.Len(t, adults, 1)
+	require.Equal(t, 37, adults[0].Age)
+
+	require.NoError(t, accounts.Delete(ctx, found))
+	all, err := accounts.Get(ctx)
+	require.NoError(t, err)
+	require.Len(t, all, 1)
+}
+
+func TestRepo_Chain(t *testing.T) {
+	conn, accounts := setupAccounts(t)
+	ctx := t.Context()
+
+	ada := &account{Name: "Ada", Email: "ada@example.com", Age: 36}
+	require.NoError(t, accounts.CreateInBatches(ctx, []*account{ada, {Name: "Bob", Email: "bob@example.com"}}, 1))
+	posts, err := db.NewRepo[*post](conn)
+	require.NoError(t, err)
+	require.NoError(t, posts.Create(ctx, &post{AccountID: ada.ID, Title: "Notes"}))
+
+	first, err := accounts.With("Posts").OrderBy("age", "desc").FirstOrFail(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "Ada", first.Name)
+	require.Len(t, first.Posts, 1)
+
+	page, err := accounts.OrderBy("name", "asc").Offset(1).Limit(1).Get(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "Bob", page[0].Name)
+
+	count, err := accounts.QueryBuilder().Where("age > ?", 30).Count(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count)
+
+	var names []string
+	require.NoError(t, accounts.OrderBy("name", "asc").Pluck(ctx, "name", &names))
+	require.Equal(t, []string{"Ada", "Bob"}, names)
+
+	created, err := accounts.FirstOrCreate(ctx, &account{Email: "cy@example.com"}, &account{Name: "Cy", Email: "cy@example.com"})
+	require.NoError(t, err)
+	require.Equal(t, "Cy", created.Name)
+	updated, err := accounts.UpdateOrCreate(ctx, &account{Email: "cy@example.com"}, map[string]any{"age": 50})
+	require.NoError(t, err)
+	require.Equal(t, created.ID, updated.ID)
+
+	require.NoError(t, accounts.ForceDelete(ctx, created))
+	require.NoError(t, accounts.Delete(ctx, ada))
+	remaining, err := accounts.Unscoped().Get(ctx)
+	require.NoError(t, err)
+	require.Len(t, remaining, 1)
+}
+
+func TestRepo_Errors(t *testing.T) {
+	ctx := t.Context()
+
+	_, err := db.NewRepo[contract.Model](mock.NewConnection(t))
+	require.ErrorIs(t, err, db.ErrModelType)
+	_, err = db.NewRepo[valueModel](mock.NewConnection(t))
+	require.EqualError(t, err, "db operation 'NewRepo' failed: db_test.valueModel is not a pointer to a model: "+
+		"unexpected model type")
+
+	conn := mock.NewConnection(t)
+	errBoom := errors.New("boom")
+	conn.On("NewRepository", mock.AnythingOfType("*db_test.account")).Return(nil, errBoom)
+	_, err = db.NewRepo[*account](conn)
+	require.ErrorIs(t, err, errBoom)
+
+	repo := mock.NewRepository(t)
+	repo.On("First", mock.Anything).Return(&post{}, nil)
+	repo.On("Get", mock.Anything).Return([]contract.Model{&account{}, &post{}}, nil)
+	accounts := db.RepoOf[*account](repo)
+	require.Same(t, repo, accounts.Repository())
+
+	_, err = accounts.First(ctx)
+	require.ErrorIs(t, err, db.ErrModelType)
+	require.EqualError(t, err, "db operation 'First' failed: got *db_test.post, expected *db_test.account: unexpected model type")
+	_, err = accounts.Get(ctx)
+	require.ErrorIs(t, err, db.ErrModelType)
+}
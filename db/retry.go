@@ -0,0 +1,62 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/next-trace/scg-database/contract"
+)
+
+type (
+	// RetryError reports an operation that failed after one or more attempts.
+	// It unwraps to the error of the last attempt.
+	RetryError struct {
+		Operation string
+		Attempts  int
+		Err       error
+	}
+)
+
+// Error implements the error interface
+func (e *RetryError) Error() string {
+	return fmt.Sprintf("db operation '%s' failed after %d attempt(s): %v", e.Operation, e.Attempts, e.Err)
+}
+
+// Unwrap returns the error of the last attempt
+func (e *RetryError) Unwrap() error {
+	return e.Err
+}
+
+// Retry calls fn until it succeeds, fails with an error that policy does not
+// consider retryable, runs out of attempts or ctx is done. Between attempts it
+// waits for policy.Backoff. A nil policy.Retryable retries every error.
+// Failures are returned as a *RetryError carrying the number of attempts made.
+func Retry(ctx context.Context, operation string, policy contract.RetryPolicy, fn func(context.Context) error) error {
+	maxAttempts := max(policy.MaxAttempts, 1)
+	for attempt := 1; ; attempt++ {
+		err := fn(ctx)
+		if err == nil {
+			return nil
+		}
+		if attempt >= maxAttempts || (policy.Retryable != nil && !policy.Retryable(err)) {
+			return &RetryError{Operation: operation, Attempts: attempt, Err: err}
+		}
+		if waitErr := wait(ctx, policy.Backoff(attempt)); waitErr != nil {
+			return &RetryError{Operation: operation, Attempts: attempt, Err: errors.Join(err, waitErr)}
+		}
+	}
+}
+
+// wait blocks for delay or until ctx is done.
+func wait(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,84 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/next-trace/scg-database/contract"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetry(t *testing.T) {
+	transient := errors.New("transient")
+	permanent := errors.New("permanent")
+	policy := contract.RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   time.Millisecond,
+		Retryable:   func(err error) bool { return errors.Is(err, transient) },
+	}
+
+	t.Run("SucceedsAfterRetries", func(t *testing.T) {
+		calls := 0
+		err := Retry(t.Context(), "Test", policy, func(context.Context) error {
+			calls++
+			if calls < 3 {
+				return transient
+			}
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, 3, calls)
+	})
+
+	t.Run("ExhaustsAttempts", func(t *testing.T) {
+		calls := 0
+		err := Retry(t.Context(), "Test", policy, func(context.Context) error {
+			calls++
+			return transient
+		})
+		var retryErr *RetryError
+		require.ErrorAs(t, err, &retryErr)
+		require.Equal(t, 3, retryErr.Attempts)
+		require.Equal(t, 3, calls)
+		require.ErrorIs(t, err, transient)
+		require.EqualError(t, err, "db operation 'Test' failed after 3 attempt(s): transient")
+	})
+
+	t.Run("StopsOnNonRetryableError", func(t *testing.T) {
+		calls := 0
+		err := Retry(t.Context(), "Test", policy, func(context.Context) error {
+			calls++
+			return permanent
+		})
+		var retryErr *RetryError
+		require.ErrorAs(t, err, &retryErr)
+		require.Equal(t, 1, retryErr.Attempts)
+		require.ErrorIs(t, err, permanent)
+	})
+
+	t.Run("NilClassifierRetriesEverything", func(t *testing.T) {
+		calls := 0
+		err := Retry(t.Context(), "Test", contract.RetryPolicy{MaxAttempts: 2}, func(context.Context) error {
+			calls++
+			return permanent
+		})
+		require.ErrorIs(t, err, permanent)
+		require.Equal(t, 2, calls)
+	})
+
+	t.Run("StopsWhenContextIsDone", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(t.Context())
+		slow := contract.RetryPolicy{MaxAttempts: 5, BaseDelay: time.Hour}
+		err := Retry(ctx, "Test", slow, func(context.Context) error {
+			cancel()
+			return transient
+		})
+		var retryErr *RetryError
+		require.ErrorAs(t, err, &retryErr)
+		require.Equal(t, 1, retryErr.Attempts)
+		require.ErrorIs(t, err, context.Canceled)
+		require.ErrorIs(t, err, transient)
+	})
+}
@@ -0,0 +1,76 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+
+	"github.com/next-trace/scg-database/config"
+)
+
+// secretConnector opens connections with the DSN of a config, resolving its
+// secret references every time.
+type secretConnector struct {
+	driver driver.Driver
+	cfg    config.Config
+	dsn    string
+}
+
+// NewConnector returns a database/sql connector that opens connections of drv
+// with cfg.DSN. Secret references in the DSN are resolved for every new
+// connection, so connections opened after a credential rotation use the new
+// value while the existing ones keep working until they are recycled
+// (see ConnMaxLifetime).
+func NewConnector(drv driver.Driver, cfg *config.Config) driver.Connector {
+	return &secretConnector{driver: drv, cfg: *cfg, dsn: cfg.DSN}
+}
+
+// OpenDB opens a pool of the registered database/sql driver driverName using
+// NewConnector. Adapters use it for DSNs with secret references.
+func OpenDB(driverName string, cfg *config.Config) (*sql.DB, error) {
+	// sql.Open does not connect; it only looks the driver up.
+	lookup, err := sql.Open(driverName, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database driver: %w", err)
+	}
+	drv := lookup.Driver()
+	_ = lookup.Close()
+	return sql.OpenDB(NewConnector(drv, cfg)), nil
+}
+
+// Connect resolves the DSN and opens a connection with it.
+func (c *secretConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	dsn, err := c.cfg.ResolveDSN(ctx, c.dsn)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // ResolveDSN errors name the reference
+	}
+	if dc, ok := c.driver.(driver.DriverContext); ok {
+		connector, err := dc.OpenConnector(dsn)
+		if err != nil {
+			return nil, err //nolint:wrapcheck // driver errors are classified by the adapters
+		}
+		return connector.Connect(ctx) //nolint:wrapcheck // see above
+	}
+	return c.driver.Open(dsn) //nolint:wrapcheck // see above
+}
+
+// Driver returns the underlying driver.
+func (c *secretConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+// resolveSecrets checks that the secret references of the primary and replica
+// DSNs of cfg resolve, so that Connect fails early with a clear error.
+func resolveSecrets(ctx context.Context, cfg *config.Config) error {
+	dsns := []string{cfg.DSN}
+	for _, replica := range cfg.Replicas {
+		dsns = append(dsns, replica.DSN)
+	}
+	for _, dsn := range dsns {
+		if _, err := cfg.ResolveDSN(ctx, dsn); err != nil {
+			return NewSecretResolutionError(err)
+		}
+	}
+	return nil
+}
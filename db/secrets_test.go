@@ -0,0 +1,119 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/next-trace/scg-database/config"
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	// recordingDriver records the DSN of every connection it opens.
+	recordingDriver struct {
+		mu   sync.Mutex
+		dsns []string
+	}
+
+	recordingConn struct{}
+)
+
+func (d *recordingDriver) Open(dsn string) (driver.Conn, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.dsns = append(d.dsns, dsn)
+	return recordingConn{}, nil
+}
+
+func (d *recordingDriver) opened() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]string(nil), d.dsns...)
+}
+
+func (recordingConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (recordingConn) Close() error                        { return nil }
+func (recordingConn) Begin() (driver.Tx, error)           { return nil, errors.New("not supported") }
+
+func TestNewConnector_PicksUpRotatedSecrets(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		password = "first"
+	)
+	cfg := &config.Config{Driver: "gorm:postgres", DSN: "host=db user=app password=secret://vault/db"}
+	config.WithSecretProvider("vault", config.SecretProviderFunc(func(context.Context, string) (string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		return password, nil
+	}))(cfg)
+
+	drv := &recordingDriver{}
+	pool := sqlOpenDB(t, NewConnector(drv, cfg))
+	pool.SetMaxIdleConns(0)
+
+	conn, err := pool.Conn(t.Context())
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+
+	mu.Lock()
+	password = "second"
+	mu.Unlock()
+
+	conn, err = pool.Conn(t.Context())
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+
+	require.Equal(t, []string{
+		"host=db user=app password=first",
+		"host=db user=app password=second",
+	}, drv.opened(), "every new connection resolves the secret again")
+}
+
+func TestNewConnector_ResolveError(t *testing.T) {
+	cfg := &config.Config{Driver: "gorm:sqlite", DSN: "secret://env/TEST_CONNECTOR_SURELY_UNSET"}
+	drv := &recordingDriver{}
+	pool := sqlOpenDB(t, NewConnector(drv, cfg))
+
+	err := pool.PingContext(t.Context())
+	require.ErrorContains(t, err, `"TEST_CONNECTOR_SURELY_UNSET" is not set`)
+	require.Empty(t, drv.opened())
+}
+
+func TestOpenDB_UnknownDriver(t *testing.T) {
+	_, err := OpenDB("surely-not-registered", &config.Config{DSN: "x"})
+	require.ErrorContains(t, err, "failed to open database driver")
+}
+
+func TestConnect_ResolvesSecrets(t *testing.T) {
+	t.Setenv("TEST_CONNECT_PASSWORD", "s3cret")
+	cfg := config.Config{
+		Driver:   "gorm:postgres",
+		DSN:      "host=db password=secret://env/TEST_CONNECT_PASSWORD",
+		Replicas: []config.Replica{{DSN: "host=replica password=secret://env/TEST_CONNECT_REPLICA_PASSWORD"}},
+		Adapter:  &fakeAdapter{conn: &fakeConn{}},
+	}
+
+	_, err := Connect(&cfg)
+	var dbErr *Error
+	require.ErrorAs(t, err, &dbErr)
+	require.Equal(t, "failed to resolve dsn secrets", dbErr.Message)
+	require.ErrorContains(t, err, `"TEST_CONNECT_REPLICA_PASSWORD" is not set`)
+	require.NotContains(t, err.Error(), "s3cret")
+
+	t.Setenv("TEST_CONNECT_REPLICA_PASSWORD", "s3cret")
+	conn, err := Connect(&cfg)
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+}
+
+// sqlOpenDB opens a pool over connector that is closed when the test ends.
+func sqlOpenDB(t *testing.T, connector driver.Connector) *sql.DB {
+	t.Helper()
+	pool := sql.OpenDB(connector)
+	t.Cleanup(func() { _ = pool.Close() })
+	return pool
+}
@@ -0,0 +1,8 @@
+//go:build cgo
+
+package db_test
+
+import gormadapter "github.com/next-trace/scg-database/adapter/gorm"
+
+// testSQLiteDriver is the SQLite dialect of the tests that need a database.
+const testSQLiteDriver = gormadapter.GormDriverSQLite
@@ -0,0 +1,9 @@
+//go:build !cgo
+
+package db_test
+
+import gormadapter "github.com/next-trace/scg-database/adapter/gorm"
+
+// testSQLiteDriver is the SQLite dialect of the tests that need a database.
+// go-sqlite3 needs cgo, so only the pure-Go dialect is available.
+const testSQLiteDriver = gormadapter.GormDriverSQLitePure
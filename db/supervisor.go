@@ -0,0 +1,332 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/next-trace/scg-database/contract"
+)
+
+// States of the circuit breaker of a SupervisedConnection.
+const (
+	// CircuitClosed lets every call through. It is the initial state.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen rejects every call with ErrCircuitOpen until the open duration elapses.
+	CircuitOpen
+	// CircuitHalfOpen rejects calls while a ping probes whether the database has recovered.
+	CircuitHalfOpen
+)
+
+// Defaults used by Supervise.
+const (
+	DefaultSupervisorInterval     = 5 * time.Second
+	DefaultSupervisorPingTimeout  = 2 * time.Second
+	DefaultSupervisorOpenDuration = 10 * time.Second
+	DefaultSupervisorThreshold    = 3
+)
+
+type (
+	// CircuitState is the state of the circuit breaker of a SupervisedConnection.
+	CircuitState int
+
+	// SupervisorOption configures Supervise.
+	SupervisorOption func(*SupervisedConnection)
+
+	// SupervisedConnection wraps a contract.Connection with a circuit breaker fed
+	// by periodic pings and by the outcome of calls. Once the database is
+	// considered down, calls fail fast with ErrCircuitOpen instead of waiting on
+	// a broken pool.
+	SupervisedConnection struct {
+		conn         contract.Connection
+		interval     time.Duration
+		pingTimeout  time.Duration
+		openDuration time.Duration
+		threshold    int
+		onChange     func(from, to CircuitState, cause error)
+
+		mu       sync.Mutex
+		state    CircuitState
+		failures int
+		openedAt time.Time
+
+		stop      chan struct{}
+		done      chan struct{}
+		closeOnce sync.Once
+		closeErr  error
+	}
+)
+
+// Ensure the implementation satisfies the interface at compile time.
+var _ contract.Connection = (*SupervisedConnection)(nil)
+
+// Supervise wraps conn and starts pinging it in the background. After threshold
+// consecutive failed pings or calls that lost the connection, the circuit opens
+// and calls fail with ErrCircuitOpen. Once the open duration has elapsed, the
+// circuit turns half-open and the next ping decides whether it closes again or
+// stays open. Close stops the supervisor and closes conn.
+func Supervise(conn contract.Connection, opts ...SupervisorOption) *SupervisedConnection {
+	s := &SupervisedConnection{
+		conn:         conn,
+		interval:     DefaultSupervisorInterval,
+		pingTimeout:  DefaultSupervisorPingTimeout,
+		openDuration: DefaultSupervisorOpenDuration,
+		threshold:    DefaultSupervisorThreshold,
+		stop:         make(chan struct{}),
+		done:         make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	go s.run()
+	return s
+}
+
+// WithPingInterval sets how often the supervisor pings the database.
+func WithPingInterval(interval time.Duration) SupervisorOption {
+	return func(s *SupervisedConnection) {
+		if interval > 0 {
+			s.interval = interval
+		}
+	}
+}
+
+// WithPingTimeout bounds every ping made by the supervisor.
+func WithPingTimeout(timeout time.Duration) SupervisorOption {
+	return func(s *SupervisedConnection) {
+		if timeout > 0 {
+			s.pingTimeout = timeout
+		}
+	}
+}
+
+// WithFailureThreshold sets the number of consecutive failures that opens the circuit.
+func WithFailureThreshold(threshold int) SupervisorOption {
+	return func(s *SupervisedConnection) {
+		if threshold > 0 {
+			s.threshold = threshold
+		}
+	}
+}
+
+// WithOpenDuration sets how long the circuit stays open before it probes for recovery.
+func WithOpenDuration(duration time.Duration) SupervisorOption {
+	return func(s *SupervisedConnection) {
+		if duration > 0 {
+			s.openDuration = duration
+		}
+	}
+}
+
+// WithStateChange registers fn to receive every state change, with the error
+// that opened the circuit when there is one. fn runs synchronously while the
+// breaker is locked, so it must return quickly and must not use the connection.
+func WithStateChange(fn func(from, to CircuitState, cause error)) SupervisorOption {
+	return func(s *SupervisedConnection) {
+		s.onChange = fn
+	}
+}
+
+// String returns the name of the state.
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// State returns the current state of the circuit breaker.
+func (s *SupervisedConnection) State() CircuitState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state
+}
+
+// run pings the database every interval until Close is called.
+func (s *SupervisedConnection) run() {
+	defer close(s.done)
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			s.check()
+		}
+	}
+}
+
+// check pings the database, turning an open circuit half-open first once its
+// open duration has elapsed.
+func (s *SupervisedConnection) check() {
+	s.mu.Lock()
+	if s.state == CircuitOpen {
+		if time.Since(s.openedAt) < s.openDuration {
+			s.mu.Unlock()
+			return
+		}
+		s.setState(CircuitHalfOpen, nil)
+	}
+	s.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.pingTimeout)
+	defer cancel()
+	s.record(s.conn.Ping(ctx), true)
+}
+
+// record updates the breaker with a failure, or a success when err is nil.
+// Only probes, the supervisor's own pings, can close a circuit.
+func (s *SupervisedConnection) record(err error, probe bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err == nil {
+		s.failures = 0
+		if probe && s.state != CircuitClosed {
+			s.setState(CircuitClosed, nil)
+		}
+		return
+	}
+
+	switch s.state {
+	case CircuitClosed:
+		s.failures++
+		if s.failures >= s.threshold {
+			s.trip(err)
+		}
+	case CircuitHalfOpen:
+		if probe {
+			s.trip(err)
+		}
+	case CircuitOpen:
+	}
+}
+
+// trip opens the circuit. The caller must hold s.mu.
+func (s *SupervisedConnection) trip(cause error) {
+	s.openedAt = time.Now()
+	s.failures = 0
+	s.setState(CircuitOpen, cause)
+}
+
+// setState moves the breaker to state and notifies the callback. The caller must hold s.mu.
+func (s *SupervisedConnection) setState(state CircuitState, cause error) {
+	from := s.state
+	s.state = state
+	if s.onChange != nil && from != state {
+		s.onChange(from, state, cause)
+	}
+}
+
+// allow returns ErrCircuitOpen unless the circuit is closed.
+func (s *SupervisedConnection) allow(operation string) error {
+	if s.State() != CircuitClosed {
+		return NewCircuitOpenError(operation)
+	}
+	return nil
+}
+
+// guard runs fn when the circuit is closed and records whether it lost the connection.
+func (s *SupervisedConnection) guard(operation string, fn func() error) error {
+	if err := s.allow(operation); err != nil {
+		return err
+	}
+	err := fn()
+	if errors.Is(err, ErrConnectionLost) {
+		s.record(err, false)
+	} else {
+		s.record(nil, false)
+	}
+	return err
+}
+
+// guarded is guard for calls that return a value.
+func guarded[T any](s *SupervisedConnection, operation string, fn func() (T, error)) (T, error) {
+	var result T
+	err := s.guard(operation, func() error {
+		var err error
+		result, err = fn()
+		return err
+	})
+	return result, err
+}
+
+// GetConnection returns the underlying connection handle.
+func (s *SupervisedConnection) GetConnection() any {
+	return s.conn.GetConnection()
+}
+
+// Ping pings the database. Its failures count towards opening the circuit,
+// unless ctx was canceled or expired.
+func (s *SupervisedConnection) Ping(ctx context.Context) error {
+	if err := s.allow("Ping"); err != nil {
+		return err
+	}
+	err := s.conn.Ping(ctx)
+	if ctx.Err() == nil {
+		s.record(err, false)
+	}
+	return err
+}
+
+// Stats returns the statistics of the wrapped connection.
+func (s *SupervisedConnection) Stats() contract.Stats {
+	return s.conn.Stats()
+}
+
+// Close stops the supervisor and closes the wrapped connection. Only the first
+// call closes it; later calls return the same result.
+func (s *SupervisedConnection) Close() error {
+	s.closeOnce.Do(func() {
+		close(s.stop)
+		<-s.done
+		s.closeErr = s.conn.Close()
+	})
+	return s.closeErr
+}
+
+// NewRepository returns a repository whose queries are guarded by the circuit breaker.
+func (s *SupervisedConnection) NewRepository(model contract.Model) (contract.Repository, error) {
+	if err := s.allow("NewRepository"); err != nil {
+		return nil, err
+	}
+	repo, err := s.conn.NewRepository(model)
+	if err != nil {
+		return nil, err
+	}
+	return &supervisedRepository{conn: s, repo: repo}, nil
+}
+
+// Transaction runs fn in a transaction when the circuit is closed.
+func (s *SupervisedConnection) Transaction(
+	ctx context.Context,
+	fn func(contract.Connection) error,
+	opts ...contract.TxOption,
+) error {
+	return s.guard("Transaction", func() error {
+		return s.conn.Transaction(ctx, fn, opts...)
+	})
+}
+
+// Select runs a raw query when the circuit is closed.
+func (s *SupervisedConnection) Select(ctx context.Context, query string, bindings ...any) ([]map[string]any, error) {
+	return guarded(s, "Select", func() ([]map[string]any, error) {
+		return s.conn.Select(ctx, query, bindings...)
+	})
+}
+
+// Statement runs a raw statement when the circuit is closed.
+func (s *SupervisedConnection) Statement(ctx context.Context, query string, bindings ...any) (sql.Result, error) {
+	return guarded(s, "Statement", func() (sql.Result, error) {
+		return s.conn.Statement(ctx, query, bindings...)
+	})
+}
@@ -0,0 +1,268 @@
+package db
+
+import (
+	"context"
+
+	"github.com/next-trace/scg-database/contract"
+)
+
+type (
+	// supervisedRepository runs the queries of a repository through the circuit
+	// breaker of its SupervisedConnection.
+	supervisedRepository struct {
+		conn *SupervisedConnection
+		repo contract.Repository
+	}
+
+	// supervisedQueryBuilder applies the same guard to the fluent query builder.
+	supervisedQueryBuilder struct {
+		conn *SupervisedConnection
+		qb   contract.QueryBuilder
+	}
+)
+
+// Ensure the implementations satisfy the interfaces at compile time.
+var (
+	_ contract.Repository   = (*supervisedRepository)(nil)
+	_ contract.QueryBuilder = (*supervisedQueryBuilder)(nil)
+)
+
+// --- Repository ---
+
+func (r *supervisedRepository) wrap(repo contract.Repository) contract.Repository {
+	return &supervisedRepository{conn: r.conn, repo: repo}
+}
+
+func (r *supervisedRepository) With(relations ...string) contract.Repository {
+	return r.wrap(r.repo.With(relations...))
+}
+
+func (r *supervisedRepository) Where(query any, args ...any) contract.Repository {
+	return r.wrap(r.repo.Where(query, args...))
+}
+
+func (r *supervisedRepository) Unscoped() contract.Repository {
+	return r.wrap(r.repo.Unscoped())
+}
+
+func (r *supervisedRepository) Limit(limit int) contract.Repository {
+	return r.wrap(r.repo.Limit(limit))
+}
+
+func (r *supervisedRepository) Offset(offset int) contract.Repository {
+	return r.wrap(r.repo.Offset(offset))
+}
+
+func (r *supervisedRepository) OrderBy(column, direction string) contract.Repository {
+	return r.wrap(r.repo.OrderBy(column, direction))
+}
+
+func (r *supervisedRepository) Find(ctx context.Context, id any) (contract.Model, error) {
+	return guarded(r.conn, "Find", func() (contract.Model, error) { return r.repo.Find(ctx, id) })
+}
+
+func (r *supervisedRepository) FindOrFail(ctx context.Context, id any) (contract.Model, error) {
+	return guarded(r.conn, "FindOrFail", func() (contract.Model, error) { return r.repo.FindOrFail(ctx, id) })
+}
+
+func (r *supervisedRepository) First(ctx context.Context) (contract.Model, error) {
+	return guarded(r.conn, "First", func() (contract.Model, error) { return r.repo.First(ctx) })
+}
+
+func (r *supervisedRepository) FirstOrFail(ctx context.Context) (contract.Model, error) {
+	return guarded(r.conn, "FirstOrFail", func() (contract.Model, error) { return r.repo.FirstOrFail(ctx) })
+}
+
+func (r *supervisedRepository) Get(ctx context.Context) ([]contract.Model, error) {
+	return guarded(r.conn, "Get", func() ([]contract.Model, error) { return r.repo.Get(ctx) })
+}
+
+func (r *supervisedRepository) Pluck(ctx context.Context, column string, dest any) error {
+	return r.conn.guard("Pluck", func() error { return r.repo.Pluck(ctx, column, dest) })
+}
+
+func (r *supervisedRepository) Create(ctx context.Context, models ...contract.Model) error {
+	return r.conn.guard("Create", func() error { return r.repo.Create(ctx, models...) })
+}
+
+func (r *supervisedRepository) CreateInBatches(ctx context.Context, models []contract.Model, batchSize int) error {
+	return r.conn.guard("CreateInBatches", func() error { return r.repo.CreateInBatches(ctx, models, batchSize) })
+}
+
+func (r *supervisedRepository) Update(ctx context.Context, models ...contract.Model) error {
+	return r.conn.guard("Update", func() error { return r.repo.Update(ctx, models...) })
+}
+
+func (r *supervisedRepository) Delete(ctx context.Context, models ...contract.Model) error {
+	return r.conn.guard("Delete", func() error { return r.repo.Delete(ctx, models...) })
+}
+
+func (r *supervisedRepository) ForceDelete(ctx context.Context, models ...contract.Model) error {
+	return r.conn.guard("ForceDelete", func() error { return r.repo.ForceDelete(ctx, models...) })
+}
+
+func (r *supervisedRepository) FirstOrCreate(
+	ctx context.Context,
+	condition contract.Model,
+	create ...contract.Model,
+) (contract.Model, error) {
+	return guarded(r.conn, "FirstOrCreate", func() (contract.Model, error) {
+		return r.repo.FirstOrCreate(ctx, condition, create...)
+	})
+}
+
+func (r *supervisedRepository) UpdateOrCreate(
+	ctx context.Context,
+	condition contract.Model,
+	values any,
+) (contract.Model, error) {
+	return guarded(r.conn, "UpdateOrCreate", func() (contract.Model, error) {
+		return r.repo.UpdateOrCreate(ctx, condition, values)
+	})
+}
+
+func (r *supervisedRepository) QueryBuilder() contract.QueryBuilder {
+	return &supervisedQueryBuilder{conn: r.conn, qb: r.repo.QueryBuilder()}
+}
+
+// --- Query builder ---
+
+func (q *supervisedQueryBuilder) wrap(qb contract.QueryBuilder) contract.QueryBuilder {
+	return &supervisedQueryBuilder{conn: q.conn, qb: qb}
+}
+
+func (q *supervisedQueryBuilder) Select(columns ...string) contract.QueryBuilder {
+	return q.wrap(q.qb.Select(columns...))
+}
+
+func (q *supervisedQueryBuilder) Where(query string, args ...any) contract.QueryBuilder {
+	return q.wrap(q.qb.Where(query, args...))
+}
+
+func (q *supervisedQueryBuilder) WhereIn(column string, values []any) contract.QueryBuilder {
+	return q.wrap(q.qb.WhereIn(column, values))
+}
+
+func (q *supervisedQueryBuilder) WhereNotIn(column string, values []any) contract.QueryBuilder {
+	return q.wrap(q.qb.WhereNotIn(column, values))
+}
+
+func (q *supervisedQueryBuilder) WhereNull(column string) contract.QueryBuilder {
+	return q.wrap(q.qb.WhereNull(column))
+}
+
+func (q *supervisedQueryBuilder) WhereNotNull(column string) contract.QueryBuilder {
+	return q.wrap(q.qb.WhereNotNull(column))
+}
+
+func (q *supervisedQueryBuilder) WhereBetween(column string, start, end any) contract.QueryBuilder {
+	return q.wrap(q.qb.WhereBetween(column, start, end))
+}
+
+func (q *supervisedQueryBuilder) OrWhere(query string, args ...any) contract.QueryBuilder {
+	return q.wrap(q.qb.OrWhere(query, args...))
+}
+
+func (q *supervisedQueryBuilder) Join(table, condition string) contract.QueryBuilder {
+	return q.wrap(q.qb.Join(table, condition))
+}
+
+func (q *supervisedQueryBuilder) LeftJoin(table, condition string) contract.QueryBuilder {
+	return q.wrap(q.qb.LeftJoin(table, condition))
+}
+
+func (q *supervisedQueryBuilder) RightJoin(table, condition string) contract.QueryBuilder {
+	return q.wrap(q.qb.RightJoin(table, condition))
+}
+
+func (q *supervisedQueryBuilder) InnerJoin(table, condition string) contract.QueryBuilder {
+	return q.wrap(q.qb.InnerJoin(table, condition))
+}
+
+func (q *supervisedQueryBuilder) OrderBy(column, direction string) contract.QueryBuilder {
+	return q.wrap(q.qb.OrderBy(column, direction))
+}
+
+func (q *supervisedQueryBuilder) GroupBy(columns ...string) contract.QueryBuilder {
+	return q.wrap(q.qb.GroupBy(columns...))
+}
+
+func (q *supervisedQueryBuilder) Having(query string, args ...any) contract.QueryBuilder {
+	return q.wrap(q.qb.Having(query, args...))
+}
+
+func (q *supervisedQueryBuilder) Limit(limit int) contract.QueryBuilder {
+	return q.wrap(q.qb.Limit(limit))
+}
+
+func (q *supervisedQueryBuilder) Offset(offset int) contract.QueryBuilder {
+	return q.wrap(q.qb.Offset(offset))
+}
+
+func (q *supervisedQueryBuilder) With(relations ...string) contract.QueryBuilder {
+	return q.wrap(q.qb.With(relations...))
+}
+
+func (q *supervisedQueryBuilder) WithCount(relations ...string) contract.QueryBuilder {
+	return q.wrap(q.qb.WithCount(relations...))
+}
+
+func (q *supervisedQueryBuilder) Scoped() contract.QueryBuilder {
+	return q.wrap(q.qb.Scoped())
+}
+
+func (q *supervisedQueryBuilder) Unscoped() contract.QueryBuilder {
+	return q.wrap(q.qb.Unscoped())
+}
+
+func (q *supervisedQueryBuilder) Raw(query string, args ...any) contract.QueryBuilder {
+	return q.wrap(q.qb.Raw(query, args...))
+}
+
+func (q *supervisedQueryBuilder) Clone() contract.QueryBuilder {
+	return q.wrap(q.qb.Clone())
+}
+
+func (q *supervisedQueryBuilder) Reset() contract.QueryBuilder {
+	return q.wrap(q.qb.Reset())
+}
+
+func (q *supervisedQueryBuilder) ToSQL() (string, []any, error) {
+	return q.qb.ToSQL()
+}
+
+func (q *supervisedQueryBuilder) Find(ctx context.Context, dest any) error {
+	return q.conn.guard("Find", func() error { return q.qb.Find(ctx, dest) })
+}
+
+func (q *supervisedQueryBuilder) First(ctx context.Context, dest any) error {
+	return q.conn.guard("First", func() error { return q.qb.First(ctx, dest) })
+}
+
+func (q *supervisedQueryBuilder) Get(ctx context.Context, dest any) error {
+	return q.conn.guard("Get", func() error { return q.qb.Get(ctx, dest) })
+}
+
+func (q *supervisedQueryBuilder) Count(ctx context.Context) (int64, error) {
+	return guarded(q.conn, "Count", func() (int64, error) { return q.qb.Count(ctx) })
+}
+
+func (q *supervisedQueryBuilder) Exists(ctx context.Context) (bool, error) {
+	return guarded(q.conn, "Exists", func() (bool, error) { return q.qb.Exists(ctx) })
+}
+
+func (q *supervisedQueryBuilder) Create(ctx context.Context, value any) error {
+	return q.conn.guard("Create", func() error { return q.qb.Create(ctx, value) })
+}
+
+func (q *supervisedQueryBuilder) Update(ctx context.Context, values any) error {
+	return q.conn.guard("Update", func() error { return q.qb.Update(ctx, values) })
+}
+
+func (q *supervisedQueryBuilder) Delete(ctx context.Context) error {
+	return q.conn.guard("Delete", func() error { return q.qb.Delete(ctx) })
+}
+
+func (q *supervisedQueryBuilder) Exec(ctx context.Context, query string, args ...any) error {
+	return q.conn.guard("Exec", func() error { return q.qb.Exec(ctx, query, args...) })
+}
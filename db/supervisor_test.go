@@ -0,0 +1,216 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/next-trace/scg-database/contract"
+	"github.com/stretchr/testify/require"
+)
+
+// switchConn is a connection whose database can be taken down and brought back.
+type switchConn struct {
+	fakeConn
+	mu     sync.Mutex
+	down   bool
+	pings  int
+	closes int
+}
+
+func (c *switchConn) setDown(down bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.down = down
+}
+
+func (c *switchConn) err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.down {
+		return NewDatabaseError(ErrConnectionLost, errors.New("connection refused"))
+	}
+	return nil
+}
+
+func (c *switchConn) Ping(ctx context.Context) error {
+	c.mu.Lock()
+	c.pings++
+	c.mu.Unlock()
+	return c.err()
+}
+
+func (c *switchConn) Close() error {
+	c.mu.Lock()
+	c.closes++
+	c.mu.Unlock()
+	return c.fakeConn.Close()
+}
+
+func (c *switchConn) Select(ctx context.Context, query string, bindings ...any) ([]map[string]any, error) {
+	return nil, c.err()
+}
+
+func (c *switchConn) Statement(ctx context.Context, query string, bindings ...any) (sql.Result, error) {
+	return nil, errors.New("syntax error")
+}
+
+// stateRecorder collects the state changes reported by a supervisor.
+type stateRecorder struct {
+	mu      sync.Mutex
+	changes []string
+}
+
+func (r *stateRecorder) record(from, to CircuitState, cause error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.changes = append(r.changes, from.String()+"->"+to.String())
+}
+
+func (r *stateRecorder) get() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.changes...)
+}
+
+func TestSupervise_OpensAndRecovers(t *testing.T) {
+	conn := &switchConn{}
+	recorder := &stateRecorder{}
+	s := Supervise(conn,
+		WithPingInterval(5*time.Millisecond),
+		WithFailureThreshold(2),
+		WithOpenDuration(20*time.Millisecond),
+		WithStateChange(recorder.record),
+	)
+	t.Cleanup(func() { _ = s.Close() })
+
+	_, err := s.Select(t.Context(), "SELECT 1")
+	require.NoError(t, err)
+
+	conn.setDown(true)
+	require.Eventually(t, func() bool { return s.State() == CircuitOpen }, time.Second, time.Millisecond)
+
+	_, err = s.Select(t.Context(), "SELECT 1")
+	require.ErrorIs(t, err, ErrCircuitOpen)
+	require.ErrorIs(t, s.Ping(t.Context()), ErrCircuitOpen)
+	require.ErrorIs(t, s.Transaction(t.Context(), func(contract.Connection) error { return nil }), ErrCircuitOpen)
+	_, err = s.NewRepository(nil)
+	require.ErrorIs(t, err, ErrCircuitOpen)
+
+	conn.setDown(false)
+	require.Eventually(t, func() bool { return s.State() == CircuitClosed }, time.Second, time.Millisecond)
+	_, err = s.Select(t.Context(), "SELECT 1")
+	require.NoError(t, err)
+
+	changes := recorder.get()
+	require.Equal(t, "closed->open", changes[0])
+	require.Equal(t, []string{"open->half-open", "half-open->closed"}, changes[len(changes)-2:])
+}
+
+func TestSupervise_HalfOpenProbeFailureReopens(t *testing.T) {
+	conn := &switchConn{down: true}
+	recorder := &stateRecorder{}
+	s := Supervise(conn,
+		WithPingInterval(5*time.Millisecond),
+		WithFailureThreshold(1),
+		WithOpenDuration(5*time.Millisecond),
+		WithStateChange(recorder.record),
+	)
+	t.Cleanup(func() { _ = s.Close() })
+
+	require.Eventually(t, func() bool { return len(recorder.get()) >= 3 }, time.Second, time.Millisecond)
+	require.Equal(t, []string{"closed->open", "open->half-open", "half-open->open"}, recorder.get()[:3])
+}
+
+func TestSupervise_CallFailures(t *testing.T) {
+	conn := &switchConn{}
+	s := Supervise(conn, WithPingInterval(time.Hour), WithFailureThreshold(2))
+	t.Cleanup(func() { _ = s.Close() })
+
+	for range 5 {
+		_, err := s.Statement(t.Context(), "SELEC 1")
+		require.Error(t, err)
+	}
+	require.Equal(t, CircuitClosed, s.State(), "query errors do not open the circuit")
+
+	conn.setDown(true)
+	_, err := s.Select(t.Context(), "SELECT 1")
+	require.ErrorIs(t, err, ErrConnectionLost)
+	require.Equal(t, CircuitClosed, s.State())
+	_, err = s.Select(t.Context(), "SELECT 1")
+	require.ErrorIs(t, err, ErrConnectionLost)
+	require.Equal(t, CircuitOpen, s.State(), "lost connections open the circuit")
+
+	conn.setDown(false)
+	_, err = s.Select(t.Context(), "SELECT 1")
+	require.ErrorIs(t, err, ErrCircuitOpen, "only the supervisor's probe closes the circuit")
+}
+
+func TestSupervise_Repository(t *testing.T) {
+	conn := &switchConn{}
+	s := Supervise(conn, WithPingInterval(time.Hour), WithFailureThreshold(1))
+	t.Cleanup(func() { _ = s.Close() })
+
+	repo, err := s.NewRepository(nil)
+	require.NoError(t, err)
+
+	conn.setDown(true)
+	_, _ = s.Select(t.Context(), "SELECT 1")
+	require.Equal(t, CircuitOpen, s.State())
+
+	_, err = repo.Find(t.Context(), 1)
+	require.ErrorIs(t, err, ErrCircuitOpen)
+	require.ErrorIs(t, repo.Create(t.Context()), ErrCircuitOpen)
+}
+
+func TestSupervise_Close(t *testing.T) {
+	conn := &switchConn{}
+	s := Supervise(conn, WithPingInterval(time.Millisecond))
+	require.Eventually(t, func() bool { return pingCount(conn) > 0 }, time.Second, time.Millisecond)
+
+	require.NoError(t, s.Close())
+	require.True(t, conn.closeCalled)
+	pings := pingCount(conn)
+	time.Sleep(10 * time.Millisecond)
+	require.Equal(t, pings, pingCount(conn), "the supervisor stops pinging once closed")
+	require.NoError(t, s.Close(), "Close is idempotent")
+	require.Equal(t, 1, conn.closes, "the connection is closed once")
+}
+
+func TestSupervise_CloseReturnsFirstResult(t *testing.T) {
+	conn := &switchConn{}
+	conn.closeErr = errors.New("close failed")
+	s := Supervise(conn)
+
+	var wg sync.WaitGroup
+	errs := make([]error, 4)
+	for i := range errs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = s.Close()
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		require.ErrorIs(t, err, conn.closeErr)
+	}
+	require.Equal(t, 1, conn.closes)
+}
+
+func TestCircuitState_String(t *testing.T) {
+	require.Equal(t, "closed", CircuitClosed.String())
+	require.Equal(t, "open", CircuitOpen.String())
+	require.Equal(t, "half-open", CircuitHalfOpen.String())
+	require.Equal(t, "unknown", CircuitState(42).String())
+}
+
+func pingCount(c *switchConn) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.pings
+}
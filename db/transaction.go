@@ -0,0 +1,74 @@
+package db
+
+import (
+	"context"
+
+	"github.com/next-trace/scg-database/contract"
+)
+
+type (
+	// txContextKey stores the ambient transaction in a context.
+	txContextKey struct{}
+)
+
+// WithTx returns a context that carries tx as the ambient transaction.
+// Repositories, query builders and raw queries of the same database join tx when
+// they are given this context, even if they were created from the root connection.
+func WithTx(ctx context.Context, tx contract.Connection) context.Context {
+	return context.WithValue(ctx, txContextKey{}, tx)
+}
+
+// TxFromContext returns the ambient transaction stored by WithTx, if any.
+func TxFromContext(ctx context.Context) (contract.Connection, bool) {
+	tx, ok := ctx.Value(txContextKey{}).(contract.Connection)
+	return tx, ok && tx != nil
+}
+
+// InTransaction runs fn in a transaction on conn and passes it a context that
+// carries the transaction, so every call made with that context joins it.
+// If ctx already carries a transaction of the same database, the adapter nests
+// the new one inside it.
+func InTransaction(
+	ctx context.Context,
+	conn contract.Connection,
+	fn func(ctx context.Context) error,
+	opts ...contract.TxOption,
+) error {
+	return conn.Transaction(ctx, func(tx contract.Connection) error {
+		return fn(WithTx(ctx, tx))
+	}, opts...)
+}
+
+// OnCommit registers fn to run after the ambient transaction in ctx commits.
+// Without a transaction in ctx, fn runs immediately. It returns
+// ErrTxHooksUnsupported when the transaction does not implement contract.TxHooks.
+func OnCommit(ctx context.Context, fn func()) error {
+	tx, ok := TxFromContext(ctx)
+	if !ok {
+		fn()
+		return nil
+	}
+	hooks, ok := tx.(contract.TxHooks)
+	if !ok {
+		return NewTxHooksUnsupportedError("OnCommit")
+	}
+	hooks.OnCommit(fn)
+	return nil
+}
+
+// OnRollback registers fn to run after the ambient transaction in ctx, or the
+// savepoint it belongs to, is rolled back. Without a transaction in ctx, fn never
+// runs. It returns ErrTxHooksUnsupported when the transaction does not implement
+// contract.TxHooks.
+func OnRollback(ctx context.Context, fn func()) error {
+	tx, ok := TxFromContext(ctx)
+	if !ok {
+		return nil
+	}
+	hooks, ok := tx.(contract.TxHooks)
+	if !ok {
+		return NewTxHooksUnsupportedError("OnRollback")
+	}
+	hooks.OnRollback(fn)
+	return nil
+}
@@ -0,0 +1,92 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/next-trace/scg-database/contract"
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	// txRecordingConn runs Transaction callbacks with a fixed tx connection.
+	txRecordingConn struct {
+		fakeConn
+		tx   contract.Connection
+		opts []contract.TxOption
+	}
+
+	// hookConn records registered transaction hooks.
+	hookConn struct {
+		fakeConn
+		commits, rollbacks int
+	}
+)
+
+func (c *hookConn) OnCommit(func())   { c.commits++ }
+func (c *hookConn) OnRollback(func()) { c.rollbacks++ }
+
+func (c *txRecordingConn) Transaction(
+	ctx context.Context,
+	fn func(contract.Connection) error,
+	opts ...contract.TxOption,
+) error {
+	c.opts = opts
+	return fn(c.tx)
+}
+
+func TestWithTx(t *testing.T) {
+	_, ok := TxFromContext(t.Context())
+	require.False(t, ok)
+
+	tx := &fakeConn{}
+	got, ok := TxFromContext(WithTx(t.Context(), tx))
+	require.True(t, ok)
+	require.Same(t, tx, got)
+
+	_, ok = TxFromContext(WithTx(t.Context(), nil))
+	require.False(t, ok, "a nil transaction should not be reported")
+}
+
+func TestInTransaction(t *testing.T) {
+	tx := &fakeConn{}
+	conn := &txRecordingConn{tx: tx}
+	fnErr := errors.New("fn failed")
+
+	err := InTransaction(t.Context(), conn, func(ctx context.Context) error {
+		got, ok := TxFromContext(ctx)
+		require.True(t, ok)
+		require.Same(t, tx, got)
+		return fnErr
+	}, contract.WithReadOnly())
+	require.ErrorIs(t, err, fnErr)
+	require.Len(t, conn.opts, 1, "options should be passed to Transaction")
+}
+
+func TestTxHooks(t *testing.T) {
+	t.Run("NoTransaction", func(t *testing.T) {
+		ran := false
+		require.NoError(t, OnCommit(t.Context(), func() { ran = true }))
+		require.True(t, ran, "without a transaction OnCommit should run immediately")
+
+		ran = false
+		require.NoError(t, OnRollback(t.Context(), func() { ran = true }))
+		require.False(t, ran, "without a transaction OnRollback should never run")
+	})
+
+	t.Run("Registers", func(t *testing.T) {
+		tx := &hookConn{}
+		ctx := WithTx(t.Context(), tx)
+		require.NoError(t, OnCommit(ctx, func() {}))
+		require.NoError(t, OnRollback(ctx, func() {}))
+		require.Equal(t, 1, tx.commits)
+		require.Equal(t, 1, tx.rollbacks)
+	})
+
+	t.Run("Unsupported", func(t *testing.T) {
+		ctx := WithTx(t.Context(), &fakeConn{})
+		require.ErrorIs(t, OnCommit(ctx, func() {}), ErrTxHooksUnsupported)
+		require.ErrorIs(t, OnRollback(ctx, func() {}), ErrTxHooksUnsupported)
+	})
+}
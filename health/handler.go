@@ -0,0 +1,215 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/next-trace/scg-database/contract"
+	"github.com/next-trace/scg-database/db"
+)
+
+const (
+	// StatusUp reports a passing check, or a report whose checks all pass.
+	StatusUp = "up"
+	// StatusDown reports a failing check, or a report with at least one failing check.
+	StatusDown = "down"
+	// DefaultTimeout bounds every check when no timeout is configured.
+	DefaultTimeout = 2 * time.Second
+)
+
+type (
+	// Handler is an http.Handler that runs its checks concurrently on every
+	// request and writes a JSON Report. It responds with 200 when every check
+	// passes and 503 otherwise, which suits Kubernetes liveness and readiness probes.
+	Handler struct {
+		checks  []check
+		timeout time.Duration
+	}
+
+	// Option configures a Handler.
+	Option func(*Handler)
+
+	// Report is the JSON body written by Handler.
+	Report struct {
+		Status string        `json:"status"`
+		Checks []CheckResult `json:"checks"`
+	}
+
+	// CheckResult is the outcome of a single check.
+	CheckResult struct {
+		Name      string         `json:"name"`
+		Kind      string         `json:"kind"`
+		Status    string         `json:"status"`
+		Latency   time.Duration  `json:"-"`
+		LatencyMS float64        `json:"latency_ms"`
+		Error     string         `json:"error,omitempty"`
+		Details   map[string]any `json:"details,omitempty"`
+	}
+
+	// check is a named probe. run returns optional details for the report.
+	check struct {
+		name string
+		kind string
+		run  func(ctx context.Context) (map[string]any, error)
+	}
+
+	// outcome carries the result of a check out of its goroutine.
+	outcome struct {
+		details map[string]any
+		err     error
+	}
+)
+
+// NewHandler creates a Handler with the given checks. A Handler without checks always reports up.
+func NewHandler(opts ...Option) *Handler {
+	h := &Handler{timeout: DefaultTimeout}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// WithTimeout bounds every check. A check that does not finish in time fails.
+func WithTimeout(timeout time.Duration) Option {
+	return func(h *Handler) {
+		if timeout > 0 {
+			h.timeout = timeout
+		}
+	}
+}
+
+// WithConnection checks that conn answers Ping.
+func WithConnection(name string, conn contract.Connection) Option {
+	return func(h *Handler) {
+		h.add(name, "connection", func(ctx context.Context) (map[string]any, error) {
+			return nil, conn.Ping(ctx)
+		})
+	}
+}
+
+// WithManager checks every connection of m, opening the ones not opened yet.
+func WithManager(m *db.Manager) Option {
+	return func(h *Handler) {
+		for _, name := range m.Names() {
+			h.add(name, "connection", func(ctx context.Context) (map[string]any, error) {
+				conn, err := m.Connection(name)
+				if err != nil {
+					return nil, err
+				}
+				return nil, conn.Ping(ctx)
+			})
+		}
+	}
+}
+
+// WithMigrations checks that migrator has no pending migrations. Use it on
+// readiness probes so that traffic waits until the schema is up to date.
+func WithMigrations(name string, migrator contract.Migrator) Option {
+	return func(h *Handler) {
+		h.add(name, "migrations", func(context.Context) (map[string]any, error) {
+			pending, err := migrator.Pending()
+			if err != nil {
+				return nil, err
+			}
+			details := map[string]any{"pending": pending}
+			if pending > 0 {
+				return details, fmt.Errorf("%d pending migration(s)", pending)
+			}
+			return details, nil
+		})
+	}
+}
+
+// WithCheck adds a custom check that fails when fn returns an error.
+func WithCheck(name string, fn func(ctx context.Context) error) Option {
+	return func(h *Handler) {
+		h.add(name, "custom", func(ctx context.Context) (map[string]any, error) {
+			return nil, fn(ctx)
+		})
+	}
+}
+
+// add appends a check to the handler.
+func (h *Handler) add(name, kind string, run func(ctx context.Context) (map[string]any, error)) {
+	h.checks = append(h.checks, check{name: name, kind: kind, run: run})
+}
+
+// Check runs every check concurrently and returns the report, with results in
+// the order the checks were added.
+func (h *Handler) Check(ctx context.Context) Report {
+	report := Report{Status: StatusUp, Checks: make([]CheckResult, len(h.checks))}
+
+	var wg sync.WaitGroup
+	for i, c := range h.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			report.Checks[i] = h.run(ctx, c)
+		}()
+	}
+	wg.Wait()
+
+	for _, result := range report.Checks {
+		if result.Status != StatusUp {
+			report.Status = StatusDown
+		}
+	}
+	return report
+}
+
+// run executes c with the handler timeout. Checks that ignore their context,
+// such as Migrator.Pending, are abandoned once the timeout expires.
+func (h *Handler) run(ctx context.Context, c check) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan outcome, 1)
+	go func() {
+		details, err := c.run(ctx)
+		done <- outcome{details: details, err: err}
+	}()
+
+	var out outcome
+	select {
+	case out = <-done:
+	case <-ctx.Done():
+		out.err = ctx.Err()
+	}
+
+	latency := time.Since(start)
+	result := CheckResult{
+		Name:      c.name,
+		Kind:      c.kind,
+		Status:    StatusUp,
+		Latency:   latency,
+		LatencyMS: float64(latency.Microseconds()) / 1000,
+		Details:   out.details,
+	}
+	if out.err != nil {
+		result.Status = StatusDown
+		result.Error = out.err.Error()
+	}
+	return result
+}
+
+// ServeHTTP implements http.Handler.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	report := h.Check(r.Context())
+
+	status := http.StatusOK
+	if report.Status != StatusUp {
+		status = http.StatusServiceUnavailable
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	if r.Method == http.MethodHead {
+		return
+	}
+	_ = json.NewEncoder(w).Encode(report)
+}
@@ -0,0 +1,151 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	gormadapter "github.com/next-trace/scg-database/adapter/gorm"
+	"github.com/next-trace/scg-database/config"
+	"github.com/next-trace/scg-database/contract"
+	"github.com/next-trace/scg-database/db"
+	"github.com/next-trace/scg-database/migration"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSQLiteConn connects to a file-backed SQLite database and returns it with its DSN.
+func newSQLiteConn(t *testing.T) (contract.Connection, string) {
+	t.Helper()
+	dsn := filepath.Join(t.TempDir(), "test.db")
+	conn, err := (&gormadapter.Adapter{}).Connect(&config.Config{Driver: testSQLiteDriver, DSN: dsn})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn, dsn
+}
+
+// newMigrator creates a migrator for dsn with a single users migration.
+func newMigrator(t *testing.T, dsn string) contract.Migrator {
+	t.Helper()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "1_users.up.sql"), []byte("CREATE TABLE users (id INTEGER);"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "1_users.down.sql"), []byte("DROP TABLE users;"), 0o600))
+
+	m, err := migration.NewMigrator(&config.Config{Driver: testSQLiteDriver, DSN: dsn, MigrationsPath: "file://" + dir})
+	require.NoError(t, err)
+	t.Cleanup(func() { _, _ = m.Close() })
+	return m
+}
+
+// serve runs h against a request with the given method and decodes the report.
+func serve(t *testing.T, h http.Handler, method string) (*httptest.ResponseRecorder, Report) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequestWithContext(t.Context(), method, "/readyz", nil))
+
+	var report Report
+	if method != http.MethodHead {
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+	}
+	return rec, report
+}
+
+func TestHandler_Healthy(t *testing.T) {
+	conn, dsn := newSQLiteConn(t)
+	migrator := newMigrator(t, dsn)
+	require.NoError(t, migrator.Up())
+
+	h := NewHandler(WithConnection("main", conn), WithMigrations("main-migrations", migrator))
+	rec, report := serve(t, h, http.MethodGet)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	assert.Equal(t, StatusUp, report.Status)
+	require.Len(t, report.Checks, 2)
+
+	assert.Equal(t, "main", report.Checks[0].Name)
+	assert.Equal(t, "connection", report.Checks[0].Kind)
+	assert.Equal(t, StatusUp, report.Checks[0].Status)
+	assert.Empty(t, report.Checks[0].Error)
+
+	assert.Equal(t, "main-migrations", report.Checks[1].Name)
+	assert.Equal(t, "migrations", report.Checks[1].Kind)
+	assert.Equal(t, StatusUp, report.Checks[1].Status)
+	assert.InDelta(t, 0, report.Checks[1].Details["pending"], 0)
+}
+
+func TestHandler_PendingMigrations(t *testing.T) {
+	conn, dsn := newSQLiteConn(t)
+	h := NewHandler(WithConnection("main", conn), WithMigrations("main-migrations", newMigrator(t, dsn)))
+	rec, report := serve(t, h, http.MethodGet)
+
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, StatusDown, report.Status)
+	assert.Equal(t, StatusUp, report.Checks[0].Status, "the connection itself is healthy")
+	assert.Equal(t, StatusDown, report.Checks[1].Status)
+	assert.Equal(t, "1 pending migration(s)", report.Checks[1].Error)
+	assert.InDelta(t, 1, report.Checks[1].Details["pending"], 0)
+}
+
+func TestHandler_ClosedConnection(t *testing.T) {
+	conn, _ := newSQLiteConn(t)
+	require.NoError(t, conn.Close())
+
+	rec, report := serve(t, NewHandler(WithConnection("main", conn)), http.MethodGet)
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, StatusDown, report.Checks[0].Status)
+	assert.Contains(t, report.Checks[0].Error, "database is closed")
+}
+
+func TestHandler_Timeout(t *testing.T) {
+	h := NewHandler(
+		WithTimeout(20*time.Millisecond),
+		WithCheck("slow", func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}),
+		WithCheck("stuck", func(context.Context) error {
+			time.Sleep(time.Second)
+			return nil
+		}),
+	)
+
+	start := time.Now()
+	report := h.Check(t.Context())
+	assert.Less(t, time.Since(start), time.Second, "checks ignoring their context are abandoned")
+	assert.Equal(t, StatusDown, report.Status)
+	for _, result := range report.Checks {
+		assert.Equal(t, "custom", result.Kind)
+		assert.Equal(t, context.DeadlineExceeded.Error(), result.Error)
+		assert.GreaterOrEqual(t, result.Latency, 20*time.Millisecond)
+		assert.Positive(t, result.LatencyMS)
+	}
+}
+
+func TestHandler_Manager(t *testing.T) {
+	gormadapter.Register()
+	dir := t.TempDir()
+	m, err := db.NewManager("primary", map[string]*config.Config{
+		"primary":   {Driver: testSQLiteDriver, DSN: filepath.Join(dir, "primary.db")},
+		"analytics": {Driver: testSQLiteDriver, DSN: filepath.Join(dir, "analytics.db")},
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = m.Close() })
+
+	rec, report := serve(t, NewHandler(WithManager(m)), http.MethodGet)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	require.Len(t, report.Checks, 2)
+	assert.Equal(t, "analytics", report.Checks[0].Name, "connections are checked in name order")
+	assert.Equal(t, "primary", report.Checks[1].Name)
+}
+
+func TestHandler_Head(t *testing.T) {
+	rec, _ := serve(t, NewHandler(), http.MethodHead)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Body.String())
+}
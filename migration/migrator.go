@@ -4,16 +4,20 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"os"
 	"strings"
 
 	"github.com/golang-migrate/migrate/v4"
 	"github.com/golang-migrate/migrate/v4/database"
 	"github.com/golang-migrate/migrate/v4/database/mysql"
 	"github.com/golang-migrate/migrate/v4/database/postgres"
-	_ "github.com/golang-migrate/migrate/v4/database/sqlite3" // driver
-	_ "github.com/golang-migrate/migrate/v4/source/file"      // driver
+	"github.com/golang-migrate/migrate/v4/database/sqlite"
+	"github.com/golang-migrate/migrate/v4/database/sqlite3"
+	"github.com/golang-migrate/migrate/v4/source"
+	_ "github.com/golang-migrate/migrate/v4/source/file" // driver
 	"github.com/next-trace/scg-database/config"
 	"github.com/next-trace/scg-database/contract"
+	"github.com/next-trace/scg-database/db"
 )
 
 // Database driver constants
@@ -21,6 +25,7 @@ const (
 	DriverMySQL    = "mysql"
 	DriverPostgres = "postgres"
 	DriverSQLite   = "sqlite"
+	DriverSQLite3  = "sqlite3"
 )
 
 // validateConfigForMigration validates the configuration required for migrations
@@ -37,13 +42,22 @@ func validateConfigForMigration(cfg *config.Config) error {
 	return nil
 }
 
-// mapDriverName maps composite driver names to SQL driver names
+// mapDriverName maps composite driver names to SQL driver names. Drivers whose
+// adapter describes its dialects (see contract.Dialects) use the migration
+// driver registered with the dialect.
 func mapDriverName(driver string) string {
+	if dialect, ok := db.DialectInfo(driver); ok && dialect.MigrationDriver != "" {
+		return dialect.MigrationDriver
+	}
 	switch driver {
 	case "gorm:mysql", DriverMySQL:
 		return DriverMySQL
 	case "gorm:postgres", DriverPostgres:
 		return DriverPostgres
+	case "gorm:sqlite", DriverSQLite3:
+		return DriverSQLite3
+	case "gorm:sqlite-pure", DriverSQLite:
+		return DriverSQLite
 	default:
 		// Assume the driver name is compatible if not a known composite
 		return driver
@@ -64,7 +78,8 @@ type (
 
 	// Migrator is the main migration runner.
 	Migrator struct {
-		migrate *migrate.Migrate
+		migrate   *migrate.Migrate
+		sourceURL string
 	}
 )
 
@@ -75,6 +90,11 @@ func (f *migrationDriverFactory) createDriver(driverName string, sqlDB *sql.DB)
 		return mysql.WithInstance(sqlDB, &mysql.Config{})
 	case DriverPostgres:
 		return postgres.WithInstance(sqlDB, &postgres.Config{})
+	case DriverSQLite3:
+		return sqlite3.WithInstance(sqlDB, &sqlite3.Config{})
+	case DriverSQLite:
+		// The pure-Go driver, usable without cgo.
+		return sqlite.WithInstance(sqlDB, &sqlite.Config{})
 	default:
 		return nil, fmt.Errorf("unsupported migration driver: %s", driverName)
 	}
@@ -113,7 +133,7 @@ func NewMigrator(cfg *config.Config) (contract.Migrator, error) {
 		return nil, fmt.Errorf("failed to create migrate instance: %w", err)
 	}
 
-	return &Migrator{migrate: m}, nil
+	return &Migrator{migrate: m, sourceURL: cfg.MigrationsPath}, nil
 }
 
 // Up applies all available up migrations.
@@ -153,6 +173,38 @@ func (m *Migrator) Fresh() error {
 	return err
 }
 
+// Pending returns the number of available migrations that have not been applied.
+// A database left dirty by a failed migration is reported as an error.
+func (m *Migrator) Pending() (int, error) {
+	current, dirty, err := m.migrate.Version()
+	applied := !errors.Is(err, migrate.ErrNilVersion)
+	if err != nil && applied {
+		return 0, err
+	}
+	if dirty {
+		return 0, fmt.Errorf("database is dirty at migration version %d", current)
+	}
+
+	src, err := source.Open(m.sourceURL)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open migration source: %w", err)
+	}
+	defer func() { _ = src.Close() }()
+
+	pending := 0
+	version, err := src.First()
+	for err == nil {
+		if !applied || version > current {
+			pending++
+		}
+		version, err = src.Next(version)
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return 0, fmt.Errorf("failed to read migration source: %w", err)
+	}
+	return pending, nil
+}
+
 // Close closes the underlying source and database connections.
 func (m *Migrator) Close() (sourceErr, dbErr error) {
 	return m.migrate.Close()
@@ -2,10 +2,14 @@ package migration
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/next-trace/scg-database/config"
+	"github.com/next-trace/scg-database/contract"
+	"github.com/next-trace/scg-database/db"
 	"github.com/stretchr/testify/require"
 )
 
@@ -33,8 +37,8 @@ func TestNewMigrator_InvalidConfig(t *testing.T) {
 		},
 		{
 			name:        "Unsupported Driver",
-			cfg:         config.Config{Driver: "sqlite", DSN: "test.db", MigrationsPath: "file://migrations"},
-			expectedErr: "failed to open database for migration: sql: unknown driver \"sqlite\" (forgotten import?)",
+			cfg:         config.Config{Driver: "oracle", DSN: "test.db", MigrationsPath: "file://migrations"},
+			expectedErr: "failed to open database for migration: sql: unknown driver \"oracle\" (forgotten import?)",
 		},
 		{
 			name: "Postgres Driver (invalid DSN format)",
@@ -108,6 +112,11 @@ func TestNewMigrator_DriverMapping(t *testing.T) {
 			inputDriver:    "gorm:sqlite",
 			expectedDriver: "sqlite3",
 		},
+		{
+			name:           "Pure-Go SQLite driver mapping",
+			inputDriver:    "gorm:sqlite-pure",
+			expectedDriver: "sqlite",
+		},
 		{
 			name:           "Direct MySQL driver",
 			inputDriver:    "mysql",
@@ -125,9 +134,14 @@ func TestNewMigrator_DriverMapping(t *testing.T) {
 			// We can't easily test the full migrator creation without a real DB,
 			// but we can test that the driver mapping logic works by checking
 			// the error messages
+			dsn := "invalid-dsn-to-trigger-error"
+			if tc.expectedDriver == DriverSQLite3 || tc.expectedDriver == DriverSQLite {
+				// SQLite accepts any file name; a missing directory makes it fail instead.
+				dsn = filepath.Join(t.TempDir(), "missing", "test.db")
+			}
 			cfg := config.Config{
 				Driver:         tc.inputDriver,
-				DSN:            "invalid-dsn-to-trigger-error",
+				DSN:            dsn,
 				MigrationsPath: "file://migrations",
 			}
 
@@ -224,3 +238,69 @@ func TestMigrator_Down_EdgeCases(t *testing.T) {
 		})
 	}
 }
+
+// TestMigrator_Pending tests counting unapplied migrations against SQLite.
+func TestMigrator_Pending(t *testing.T) {
+	for _, driver := range testSQLiteDrivers {
+		t.Run(driver, func(t *testing.T) {
+			testMigratorPending(t, driver)
+		})
+	}
+}
+
+func testMigratorPending(t *testing.T, driver string) {
+	t.Helper()
+	dir := t.TempDir()
+	for _, name := range []string{"1_users.up.sql", "1_users.down.sql", "2_posts.up.sql", "2_posts.down.sql"} {
+		stmt := "SELECT 1;"
+		switch name {
+		case "1_users.up.sql":
+			stmt = "CREATE TABLE users (id INTEGER PRIMARY KEY);"
+		case "2_posts.up.sql":
+			stmt = "CREATE TABLE posts (id INTEGER PRIMARY KEY);"
+		}
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(stmt), 0o600))
+	}
+
+	m, err := NewMigrator(&config.Config{
+		Driver:         driver,
+		DSN:            filepath.Join(t.TempDir(), "test.db"),
+		MigrationsPath: "file://" + dir,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _, _ = m.Close() })
+
+	pending, err := m.Pending()
+	require.NoError(t, err)
+	require.Equal(t, 2, pending, "nothing is applied on a new database")
+
+	require.NoError(t, m.Up())
+	pending, err = m.Pending()
+	require.NoError(t, err)
+	require.Equal(t, 0, pending)
+
+	require.NoError(t, m.Down(1))
+	pending, err = m.Pending()
+	require.NoError(t, err)
+	require.Equal(t, 1, pending)
+}
+
+// dialectAdapter describes a driver whose migrations run on Postgres.
+type dialectAdapter struct{}
+
+func (dialectAdapter) Name() string { return "dialect-fake" }
+
+func (dialectAdapter) Connect(*config.Config) (contract.Connection, error) { return nil, nil }
+
+func (dialectAdapter) DialectInfo(driver string) (contract.DialectInfo, bool) {
+	return contract.DialectInfo{MigrationDriver: "postgres"}, driver == "fake:cockroach"
+}
+
+func TestMapDriverName_RegisteredDialect(t *testing.T) {
+	db.RegisterAdapter(dialectAdapter{}, "fake:cockroach", "fake:unknown")
+
+	require.Equal(t, DriverPostgres, mapDriverName("fake:cockroach"))
+	require.Equal(t, "fake:unknown", mapDriverName("fake:unknown"), "unknown dialects keep their name")
+	require.Equal(t, DriverSQLite3, mapDriverName("gorm:sqlite"), "built-in names do not need the registry")
+	require.Equal(t, DriverSQLite, mapDriverName("gorm:sqlite-pure"))
+}
@@ -0,0 +1,8 @@
+//go:build cgo
+
+package migration
+
+// testSQLiteDrivers are the SQLite dialects the migration tests run against.
+//
+//nolint:grouper // Only One Global Variable
+var testSQLiteDrivers = []string{"gorm:sqlite", "gorm:sqlite-pure"}
@@ -0,0 +1,9 @@
+//go:build !cgo
+
+package migration
+
+// testSQLiteDrivers are the SQLite dialects the migration tests run against.
+// go-sqlite3 needs cgo, so only the pure-Go dialect is available.
+//
+//nolint:grouper // Only One Global Variable
+var testSQLiteDrivers = []string{"gorm:sqlite-pure"}
@@ -36,11 +36,16 @@ func (m *mockResult) RowsAffected() (int64, error) { return 0, nil }
 func (m *mockConnection) GetConnection() any             { return nil }
 func (m *mockConnection) Ping(ctx context.Context) error { return nil }
 func (m *mockConnection) Close() error                   { return nil }
+func (m *mockConnection) Stats() contract.Stats          { return contract.Stats{} }
 func (m *mockConnection) NewRepository(model contract.Model) (contract.Repository, error) {
 	return nil, nil
 }
 
-func (m *mockConnection) Transaction(ctx context.Context, fn func(txConnection contract.Connection) error) error {
+func (m *mockConnection) Transaction(
+	ctx context.Context,
+	fn func(txConnection contract.Connection) error,
+	opts ...contract.TxOption,
+) error {
 	return nil
 }
 
@@ -0,0 +1,388 @@
+// Package conformance provides a test kit that checks a contract.DBAdapter
+// behaves like the GORM adapter. RunConformance runs a shared suite of
+// behaviours, from CRUD to transactions and relationships, against the
+// connections returned by a Factory:
+//
+//	func TestConformance(t *testing.T) {
+//		conformance.RunConformance(t, func(t *testing.T) contract.Connection {
+//			conn, err := db.Connect(&config.Config{Driver: "my:driver", DSN: newDatabase(t)})
+//			require.NoError(t, err)
+//			t.Cleanup(func() { _ = conn.Close() })
+//			// create the tables of conformance.Models()
+//			return conn
+//		})
+//	}
+package conformance
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/next-trace/scg-database/contract"
+	"github.com/next-trace/scg-database/db"
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	// Factory returns a connection to a new, empty database with the tables of
+	// Models. It is called once per behaviour, so behaviours do not share data,
+	// and should close the connection with t.Cleanup.
+	Factory func(t *testing.T) contract.Connection
+)
+
+// RunConformance runs the conformance suite against the connections of
+// factory, one subtest per behaviour.
+func RunConformance(t *testing.T, factory Factory) {
+	t.Helper()
+	behaviours := []struct {
+		name string
+		run  func(t *testing.T, conn contract.Connection)
+	}{
+		{"CRUD", testCRUD},
+		{"FindVersusFindOrFail", testFindOrFail},
+		{"SoftDelete", testSoftDelete},
+		{"FirstOrCreate", testFirstOrCreate},
+		{"UpdateOrCreate", testUpdateOrCreate},
+		{"Transaction", testTransaction},
+		{"QueryBuilderFilters", testQueryBuilderFilters},
+		{"Pagination", testPagination},
+		{"Relationships", testRelationships},
+	}
+	for _, behaviour := range behaviours {
+		t.Run(behaviour.name, func(t *testing.T) {
+			behaviour.run(t, factory(t))
+		})
+	}
+}
+
+// newUser returns the i-th test user, aged 20+i.
+func newUser(i int) *User {
+	return &User{Name: fmt.Sprintf("User %d", i), Email: fmt.Sprintf("user%d@example.com", i), Age: 20 + i}
+}
+
+// repository returns a repository of model on conn.
+func repository(t *testing.T, conn contract.Connection, model contract.Model) contract.Repository {
+	t.Helper()
+	repo, err := conn.NewRepository(model)
+	require.NoError(t, err)
+	return repo
+}
+
+// seedUsers creates n users, aged 21 to 20+n, and returns their repository.
+func seedUsers(t *testing.T, conn contract.Connection, n int) contract.Repository {
+	t.Helper()
+	repo := repository(t, conn, &User{})
+	for i := 1; i <= n; i++ {
+		require.NoError(t, repo.Create(t.Context(), newUser(i)))
+	}
+	return repo
+}
+
+// names returns the names of users, which must be *User models.
+func names(t *testing.T, users []contract.Model) []string {
+	t.Helper()
+	result := make([]string, 0, len(users))
+	for _, model := range users {
+		user, ok := model.(*User)
+		require.True(t, ok, "expected *User, got %T", model)
+		result = append(result, user.Name)
+	}
+	return result
+}
+
+func testCRUD(t *testing.T, conn contract.Connection) {
+	ctx := t.Context()
+	repo := repository(t, conn, &User{})
+
+	user := newUser(1)
+	require.NoError(t, repo.Create(ctx, user))
+	require.NotZero(t, user.ID, "Create sets the generated primary key")
+	require.False(t, user.CreatedAt.IsZero(), "Create sets CreatedAt")
+	require.False(t, user.UpdatedAt.IsZero(), "Create sets UpdatedAt")
+
+	found, err := repo.Find(ctx, user.ID)
+	require.NoError(t, err)
+	require.Equal(t, []string{"User 1"}, names(t, []contract.Model{found}))
+	require.Equal(t, "user1@example.com", found.(*User).Email)
+
+	require.NoError(t, repo.CreateInBatches(ctx, []contract.Model{newUser(2), newUser(3), newUser(4)}, 2))
+	all, err := repo.OrderBy("id", "asc").Get(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"User 1", "User 2", "User 3", "User 4"}, names(t, all))
+
+	user.Name = "Renamed"
+	user.Age = 0 // zero fields are not written
+	require.NoError(t, repo.Update(ctx, user))
+	found, err = repo.FindOrFail(ctx, user.ID)
+	require.NoError(t, err)
+	require.Equal(t, "Renamed", found.(*User).Name)
+	require.Equal(t, 21, found.(*User).Age)
+
+	var emails []string
+	require.NoError(t, repo.Where("age > ?", 22).OrderBy("id", "asc").Pluck(ctx, "email", &emails))
+	require.Equal(t, []string{"user3@example.com", "user4@example.com"}, emails)
+
+	require.NoError(t, repo.ForceDelete(ctx, user))
+	found, err = repo.Unscoped().Find(ctx, user.ID)
+	require.NoError(t, err)
+	require.Nil(t, found, "ForceDelete removes the row")
+}
+
+func testFindOrFail(t *testing.T, conn contract.Connection) {
+	ctx := t.Context()
+	repo := repository(t, conn, &User{})
+
+	found, err := repo.Find(ctx, 999)
+	require.NoError(t, err, "Find does not fail for a missing row")
+	require.Nil(t, found)
+	_, err = repo.FindOrFail(ctx, 999)
+	require.ErrorIs(t, err, db.ErrRecordNotFound)
+
+	first, err := repo.First(ctx)
+	require.NoError(t, err)
+	require.Nil(t, first)
+	_, err = repo.FirstOrFail(ctx)
+	require.ErrorIs(t, err, db.ErrRecordNotFound)
+
+	user := newUser(1)
+	require.NoError(t, repo.Create(ctx, user))
+	found, err = repo.FindOrFail(ctx, user.ID)
+	require.NoError(t, err)
+	require.Equal(t, user.ID, found.(*User).ID)
+	first, err = repo.FirstOrFail(ctx)
+	require.NoError(t, err)
+	require.Equal(t, user.ID, first.(*User).ID)
+}
+
+func testSoftDelete(t *testing.T, conn contract.Connection) {
+	ctx := t.Context()
+	repo := repository(t, conn, &User{})
+	first, second := newUser(1), newUser(2)
+	require.NoError(t, repo.Create(ctx, first, second))
+
+	require.NoError(t, repo.Delete(ctx, first))
+	found, err := repo.Find(ctx, first.ID)
+	require.NoError(t, err)
+	require.Nil(t, found, "soft deleted rows are hidden")
+	all, err := repo.Get(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"User 2"}, names(t, all))
+
+	trashed, err := repo.Unscoped().Find(ctx, first.ID)
+	require.NoError(t, err)
+	require.NotNil(t, trashed, "Unscoped finds soft deleted rows")
+	require.NotNil(t, trashed.(*User).GetDeletedAt())
+	count, err := repo.QueryBuilder().Unscoped().Count(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), count)
+
+	require.NoError(t, repo.ForceDelete(ctx, first))
+	trashed, err = repo.Unscoped().Find(ctx, first.ID)
+	require.NoError(t, err)
+	require.Nil(t, trashed, "ForceDelete removes soft deleted rows")
+
+	posts := repository(t, conn, &Post{})
+	post := &Post{UserID: second.ID, Title: "Hello"}
+	require.NoError(t, posts.Create(ctx, post))
+	require.NoError(t, posts.Delete(ctx, post))
+	gone, err := posts.Unscoped().Find(ctx, post.ID)
+	require.NoError(t, err)
+	require.Nil(t, gone, "models without soft deletes are deleted")
+}
+
+func testFirstOrCreate(t *testing.T, conn contract.Connection) {
+	ctx := t.Context()
+	repo := repository(t, conn, &User{})
+
+	created, err := repo.FirstOrCreate(ctx, &User{Email: "new@example.com"}, &User{Name: "New", Age: 30})
+	require.NoError(t, err)
+	require.NotZero(t, created.(*User).ID)
+	require.Equal(t, "new@example.com", created.(*User).Email, "the condition fields are created")
+	require.Equal(t, "New", created.(*User).Name)
+
+	found, err := repo.FirstOrCreate(ctx, &User{Email: "new@example.com"})
+	require.NoError(t, err)
+	require.Equal(t, created.(*User).ID, found.(*User).ID, "an existing row is returned")
+	require.Equal(t, 30, found.(*User).Age)
+
+	count, err := repo.QueryBuilder().Count(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count)
+}
+
+func testUpdateOrCreate(t *testing.T, conn contract.Connection) {
+	ctx := t.Context()
+	repo := repository(t, conn, &User{})
+
+	created, err := repo.UpdateOrCreate(ctx, &User{Email: "a@example.com"}, map[string]any{"name": "A", "age": 30})
+	require.NoError(t, err)
+	require.NotZero(t, created.(*User).ID)
+	require.Equal(t, "A", created.(*User).Name)
+
+	updated, err := repo.UpdateOrCreate(ctx, &User{Email: "a@example.com"}, map[string]any{"name": "B"})
+	require.NoError(t, err)
+	require.Equal(t, created.(*User).ID, updated.(*User).ID, "an existing row is updated")
+	require.Equal(t, "B", updated.(*User).Name)
+
+	found, err := repo.FindOrFail(ctx, created.(*User).ID)
+	require.NoError(t, err)
+	require.Equal(t, "B", found.(*User).Name)
+	require.Equal(t, 30, found.(*User).Age, "columns without values are kept")
+
+	count, err := repo.QueryBuilder().Count(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count)
+}
+
+func testTransaction(t *testing.T, conn contract.Connection) {
+	ctx := t.Context()
+	repo := repository(t, conn, &User{})
+	require.NoError(t, repo.Create(ctx, newUser(1)))
+
+	txErr := errors.New("rollback")
+	err := conn.Transaction(ctx, func(tx contract.Connection) error {
+		txRepo := repository(t, tx, &User{})
+		require.NoError(t, txRepo.Create(ctx, newUser(2)))
+		user, err := txRepo.Where("name = ?", "User 1").FirstOrFail(ctx)
+		require.NoError(t, err)
+		user.(*User).Name = "Renamed"
+		require.NoError(t, txRepo.Update(ctx, user))
+
+		all, err := txRepo.Get(ctx)
+		require.NoError(t, err)
+		require.Len(t, all, 2, "the transaction sees its own writes")
+		return txErr
+	})
+	require.ErrorIs(t, err, txErr)
+	all, err := repo.Get(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"User 1"}, names(t, all), "a failing transaction is rolled back")
+
+	err = conn.Transaction(ctx, func(tx contract.Connection) error {
+		return repository(t, tx, &User{}).Create(ctx, newUser(3))
+	}, contract.WithTxTimeout(time.Minute))
+	require.NoError(t, err)
+	all, err = repo.OrderBy("id", "asc").Get(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"User 1", "User 3"}, names(t, all), "a successful transaction is committed")
+}
+
+func testQueryBuilderFilters(t *testing.T, conn contract.Connection) {
+	ctx := t.Context()
+	repo := seedUsers(t, conn, 3)
+	require.NoError(t, repo.Delete(ctx, &User{ID: 3}))
+
+	tests := []struct {
+		name  string
+		qb    func(qb contract.QueryBuilder) contract.QueryBuilder
+		count int64
+	}{
+		{"Where", func(qb contract.QueryBuilder) contract.QueryBuilder { return qb.Where("age > ?", 21) }, 1},
+		{"OrWhere", func(qb contract.QueryBuilder) contract.QueryBuilder {
+			return qb.Where("age = ?", 21).OrWhere("age = ?", 22)
+		}, 2},
+		{"WhereIn", func(qb contract.QueryBuilder) contract.QueryBuilder {
+			return qb.WhereIn("name", []any{"User 1", "User 3"})
+		}, 1},
+		{"WhereNotIn", func(qb contract.QueryBuilder) contract.QueryBuilder {
+			return qb.WhereNotIn("name", []any{"User 1"})
+		}, 1},
+		{"WhereNull", func(qb contract.QueryBuilder) contract.QueryBuilder { return qb.WhereNull("deleted_at") }, 2},
+		{"WhereNotNull", func(qb contract.QueryBuilder) contract.QueryBuilder {
+			return qb.Unscoped().WhereNotNull("deleted_at")
+		}, 1},
+		{"WhereBetween", func(qb contract.QueryBuilder) contract.QueryBuilder {
+			return qb.Unscoped().WhereBetween("age", 22, 23)
+		}, 2},
+		{"Unscoped", func(qb contract.QueryBuilder) contract.QueryBuilder { return qb.Unscoped() }, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			count, err := tt.qb(repo.QueryBuilder()).Count(ctx)
+			require.NoError(t, err)
+			require.Equal(t, tt.count, count)
+		})
+	}
+
+	exists, err := repo.QueryBuilder().Where("name = ?", "nobody").Exists(ctx)
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	var user User
+	require.NoError(t, repo.QueryBuilder().Where("age > ?", 21).First(ctx, &user))
+	require.Equal(t, "User 2", user.Name)
+	var missing User
+	require.ErrorIs(t, repo.QueryBuilder().Where("age > ?", 100).First(ctx, &missing), db.ErrRecordNotFound)
+
+	require.NoError(t, repo.QueryBuilder().Where("name = ?", "User 1").Update(ctx, map[string]any{"age": 40}))
+	found, err := repo.Where("name = ?", "User 1").FirstOrFail(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 40, found.(*User).Age)
+
+	require.NoError(t, repo.QueryBuilder().Where("name = ?", "User 1").Delete(ctx))
+	count, err := repo.QueryBuilder().Count(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count, "QueryBuilder deletes are soft deletes")
+}
+
+func testPagination(t *testing.T, conn contract.Connection) {
+	ctx := t.Context()
+	repo := seedUsers(t, conn, 5)
+
+	page, err := repo.OrderBy("age", "desc").Limit(2).Offset(1).Get(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"User 4", "User 3"}, names(t, page))
+
+	page, err = repo.OrderBy("age", "asc").Limit(2).Offset(4).Get(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"User 5"}, names(t, page), "the last page may be short")
+
+	var users []User
+	require.NoError(t, repo.QueryBuilder().OrderBy("name", "asc").Limit(2).Offset(2).Find(ctx, &users))
+	require.Len(t, users, 2)
+	require.Equal(t, "User 3", users[0].Name)
+	require.Equal(t, "User 4", users[1].Name)
+
+	var pointers []*User
+	require.NoError(t, repo.QueryBuilder().Where("age > ?", 22).OrderBy("id", "desc").Limit(10).Get(ctx, &pointers))
+	require.Len(t, pointers, 3)
+	require.Equal(t, "User 5", pointers[0].Name)
+}
+
+func testRelationships(t *testing.T, conn contract.Connection) {
+	ctx := t.Context()
+	users := repository(t, conn, &User{})
+	alice, bob := newUser(1), newUser(2)
+	require.NoError(t, users.Create(ctx, alice, bob))
+
+	posts := repository(t, conn, &Post{})
+	require.NoError(t, posts.Create(ctx,
+		&Post{UserID: alice.ID, Title: "A1"},
+		&Post{UserID: alice.ID, Title: "A2"},
+		&Post{UserID: bob.ID, Title: "B1"},
+	))
+	require.NoError(t, repository(t, conn, &Profile{}).Create(ctx, &Profile{UserID: bob.ID, Bio: "Bio"}))
+
+	loaded, err := users.With("Posts", "Profile").OrderBy("id", "asc").Get(ctx)
+	require.NoError(t, err)
+	require.Len(t, loaded, 2)
+	a, b := loaded[0].(*User), loaded[1].(*User)
+	require.Len(t, a.Posts, 2, "has-many relationships are loaded")
+	require.Nil(t, a.Profile)
+	require.Len(t, b.Posts, 1)
+	require.Equal(t, "B1", b.Posts[0].Title)
+	require.NotNil(t, b.Profile, "has-one relationships are loaded")
+	require.Equal(t, "Bio", b.Profile.Bio)
+
+	post, err := posts.With("Author").Where("title = ?", "B1").FirstOrFail(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, post.(*Post).Author, "belongs-to relationships are loaded")
+	require.Equal(t, "User 2", post.(*Post).Author.Name)
+
+	var withPosts []User
+	require.NoError(t, users.QueryBuilder().With("Posts").Where("id = ?", alice.ID).Find(ctx, &withPosts))
+	require.Len(t, withPosts, 1)
+	require.Len(t, withPosts[0].Posts, 2, "the query builder loads relationships too")
+}
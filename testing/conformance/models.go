@@ -0,0 +1,93 @@
+package conformance
+
+import (
+	"time"
+
+	"github.com/next-trace/scg-database/contract"
+	"gorm.io/gorm"
+)
+
+type (
+	// User is the main model of the suite: it has timestamps, soft deletes, and
+	// has-many and has-one relationships. Its table is users.
+	//
+	// DeletedAt is a gorm.DeletedAt so that the GORM adapter, which only soft
+	// deletes through that type, can run the suite; to other adapters it is a
+	// nullable time implementing sql.Scanner and driver.Valuer.
+	User struct {
+		ID        uint
+		Name      string
+		Email     string
+		Age       int
+		CreatedAt time.Time
+		UpdatedAt time.Time
+		DeletedAt gorm.DeletedAt `gorm:"index"`
+		Posts     []*Post
+		Profile   *Profile
+	}
+
+	// Post belongs to a User. Its table is posts.
+	Post struct {
+		ID     uint
+		UserID uint
+		Title  string
+		Author *User `gorm:"foreignKey:UserID"`
+	}
+
+	// Profile is the optional profile of a User. Its table is profiles.
+	Profile struct {
+		ID     uint
+		UserID uint
+		Bio    string
+	}
+)
+
+// Models returns the models whose tables a Factory must create.
+func Models() []contract.Model {
+	return []contract.Model{&User{}, &Post{}, &Profile{}}
+}
+
+func (m *User) PrimaryKey() string { return "id" }
+func (m *User) TableName() string  { return "users" }
+func (m *User) GetID() any         { return m.ID }
+func (m *User) SetID(id any)       { m.ID, _ = id.(uint) }
+func (m *User) Relationships() map[string]contract.Relationship {
+	return map[string]contract.Relationship{
+		"Posts":   contract.NewHasMany(&Post{}, "user_id", "id"),
+		"Profile": contract.NewHasOne(&Profile{}, "user_id", "id"),
+	}
+}
+
+func (m *User) GetCreatedAt() time.Time  { return m.CreatedAt }
+func (m *User) GetUpdatedAt() time.Time  { return m.UpdatedAt }
+func (m *User) SetCreatedAt(t time.Time) { m.CreatedAt = t }
+func (m *User) SetUpdatedAt(t time.Time) { m.UpdatedAt = t }
+
+func (m *User) GetDeletedAt() *time.Time {
+	if !m.DeletedAt.Valid {
+		return nil
+	}
+	return &m.DeletedAt.Time
+}
+
+func (m *User) SetDeletedAt(t *time.Time) {
+	if t == nil {
+		m.DeletedAt = gorm.DeletedAt{}
+		return
+	}
+	m.DeletedAt = gorm.DeletedAt{Time: *t, Valid: true}
+}
+
+func (m *Post) PrimaryKey() string { return "id" }
+func (m *Post) TableName() string  { return "posts" }
+func (m *Post) GetID() any         { return m.ID }
+func (m *Post) SetID(id any)       { m.ID, _ = id.(uint) }
+func (m *Post) Relationships() map[string]contract.Relationship {
+	return map[string]contract.Relationship{"Author": contract.NewBelongsTo(&User{}, "user_id", "id")}
+}
+
+func (m *Profile) PrimaryKey() string                              { return "id" }
+func (m *Profile) TableName() string                               { return "profiles" }
+func (m *Profile) GetID() any                                      { return m.ID }
+func (m *Profile) SetID(id any)                                    { m.ID, _ = id.(uint) }
+func (m *Profile) Relationships() map[string]contract.Relationship { return nil }
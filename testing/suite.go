@@ -203,12 +203,9 @@ func (s *DatabaseTestSuite) TruncateTable(tableName string) {
 	ctx := context.Background()
 	query := "TRUNCATE TABLE " + tableName
 
-	// Handle different database dialects
-	switch s.Config.Driver {
-	case "gorm:sqlite":
-		query = "DELETE FROM " + tableName
-	case "gorm:postgres":
-		query = "TRUNCATE TABLE " + tableName + " RESTART IDENTITY CASCADE"
+	// Use the statement of the dialect when the adapter describes it
+	if dialect, ok := db.DialectInfo(s.Config.Driver); ok && dialect.TruncateStatement != nil {
+		query = dialect.TruncateStatement(tableName)
 	}
 
 	_, err := s.Connection.Statement(ctx, query)
@@ -49,7 +49,11 @@ func (m *MockConnection) NewRepository(model contract.Model) (contract.Repositor
 	return args.Get(0).(contract.Repository), args.Error(1)
 }
 
-func (m *MockConnection) Transaction(ctx context.Context, fn func(contract.Connection) error) error {
+func (m *MockConnection) Transaction(
+	ctx context.Context,
+	fn func(contract.Connection) error,
+	_ ...contract.TxOption,
+) error {
 	args := m.Called(ctx, fn)
 	return args.Error(0)
 }
@@ -74,6 +78,11 @@ func (m *MockConnection) Ping(ctx context.Context) error {
 	return args.Error(0)
 }
 
+func (m *MockConnection) Stats() contract.Stats {
+	args := m.Called()
+	return args.Get(0).(contract.Stats)
+}
+
 func (m *MockConnection) GetConnection() any {
 	args := m.Called()
 	return args.Get(0)
@@ -1,16 +1,19 @@
 package testing
 
 import (
+	"path/filepath"
 	"testing"
 	"time"
 
+	gormadapter "github.com/next-trace/scg-database/adapter/gorm"
 	"github.com/next-trace/scg-database/contract"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewDatabaseTestSuite(t *testing.T) {
 	cfg := DatabaseTestConfig{
-		Driver:          "gorm:sqlite",
+		Driver:          testSQLiteDriver,
 		DSN:             ":memory:",
 		MigrationsPath:  "/migrations",
 		SeedsPath:       "/seeds",
@@ -31,7 +34,7 @@ func TestNewDatabaseTestSuite(t *testing.T) {
 
 func TestNewDatabaseTestSuiteWithDefaultTimeout(t *testing.T) {
 	cfg := DatabaseTestConfig{
-		Driver: "gorm:sqlite",
+		Driver: testSQLiteDriver,
 		DSN:    ":memory:",
 	}
 
@@ -87,7 +90,7 @@ func TestRunTestSuiteFunction(t *testing.T) {
 func TestDatabaseTestSuite_SetupSuite(t *testing.T) {
 	// Create a test suite with SQLite in-memory database
 	cfg := DatabaseTestConfig{
-		Driver: "gorm:sqlite",
+		Driver: testSQLiteDriver,
 		DSN:    ":memory:",
 	}
 
@@ -98,14 +101,14 @@ func TestDatabaseTestSuite_SetupSuite(t *testing.T) {
 	assert.NotPanics(t, func() {
 		// We can't actually call SetupSuite without a real database adapter
 		// but we can test the configuration is set up correctly
-		assert.Equal(t, "gorm:sqlite", ts.Config.Driver)
+		assert.Equal(t, testSQLiteDriver, ts.Config.Driver)
 		assert.Equal(t, ":memory:", ts.Config.DSN)
 	})
 }
 
 func TestDatabaseTestSuite_CreateRepository(t *testing.T) {
 	cfg := DatabaseTestConfig{
-		Driver: "gorm:sqlite",
+		Driver: testSQLiteDriver,
 		DSN:    ":memory:",
 	}
 
@@ -120,7 +123,7 @@ func TestDatabaseTestSuite_CreateRepository(t *testing.T) {
 
 func TestDatabaseTestSuite_ExecuteInTransaction(t *testing.T) {
 	cfg := DatabaseTestConfig{
-		Driver: "gorm:sqlite",
+		Driver: testSQLiteDriver,
 		DSN:    ":memory:",
 	}
 
@@ -137,7 +140,7 @@ func TestDatabaseTestSuite_ExecuteInTransaction(t *testing.T) {
 
 func TestDatabaseTestSuite_TruncateTable(t *testing.T) {
 	cfg := DatabaseTestConfig{
-		Driver: "gorm:sqlite",
+		Driver: testSQLiteDriver,
 		DSN:    ":memory:",
 	}
 
@@ -152,7 +155,7 @@ func TestDatabaseTestSuite_TruncateTable(t *testing.T) {
 
 func TestDatabaseTestSuite_SeedData(t *testing.T) {
 	cfg := DatabaseTestConfig{
-		Driver: "gorm:sqlite",
+		Driver: testSQLiteDriver,
 		DSN:    ":memory:",
 	}
 
@@ -167,7 +170,7 @@ func TestDatabaseTestSuite_SeedData(t *testing.T) {
 
 func TestDatabaseTestSuite_AssertRecordExists(t *testing.T) {
 	cfg := DatabaseTestConfig{
-		Driver: "gorm:sqlite",
+		Driver: testSQLiteDriver,
 		DSN:    ":memory:",
 	}
 
@@ -182,7 +185,7 @@ func TestDatabaseTestSuite_AssertRecordExists(t *testing.T) {
 
 func TestDatabaseTestSuite_AssertRecordNotExists(t *testing.T) {
 	cfg := DatabaseTestConfig{
-		Driver: "gorm:sqlite",
+		Driver: testSQLiteDriver,
 		DSN:    ":memory:",
 	}
 
@@ -197,7 +200,7 @@ func TestDatabaseTestSuite_AssertRecordNotExists(t *testing.T) {
 
 func TestDatabaseTestSuite_AssertTableEmpty(t *testing.T) {
 	cfg := DatabaseTestConfig{
-		Driver: "gorm:sqlite",
+		Driver: testSQLiteDriver,
 		DSN:    ":memory:",
 	}
 
@@ -212,7 +215,7 @@ func TestDatabaseTestSuite_AssertTableEmpty(t *testing.T) {
 
 func TestDatabaseTestSuite_GetRawConnection(t *testing.T) {
 	cfg := DatabaseTestConfig{
-		Driver: "gorm:sqlite",
+		Driver: testSQLiteDriver,
 		DSN:    ":memory:",
 	}
 
@@ -226,7 +229,7 @@ func TestDatabaseTestSuite_GetRawConnection(t *testing.T) {
 
 func TestDatabaseTestSuite_TearDownSuite(t *testing.T) {
 	cfg := DatabaseTestConfig{
-		Driver: "gorm:sqlite",
+		Driver: testSQLiteDriver,
 		DSN:    ":memory:",
 	}
 
@@ -250,7 +253,7 @@ func TestDatabaseTestSuite_TearDownSuite(t *testing.T) {
 
 func TestDatabaseTestSuite_SetupAndTearDownTest(t *testing.T) {
 	cfg := DatabaseTestConfig{
-		Driver: "gorm:sqlite",
+		Driver: testSQLiteDriver,
 		DSN:    ":memory:",
 	}
 
@@ -264,6 +267,29 @@ func TestDatabaseTestSuite_SetupAndTearDownTest(t *testing.T) {
 	})
 }
 
+func TestDatabaseTestSuite_SQLitePure(t *testing.T) {
+	gormadapter.Register()
+	ts := NewDatabaseTestSuite(&DatabaseTestConfig{
+		Driver:  gormadapter.GormDriverSQLitePure,
+		DSN:     filepath.Join(t.TempDir(), "suite.db"),
+		Timeout: 5 * time.Second,
+	})
+	ts.SetT(t)
+	ts.SetupSuite()
+	t.Cleanup(ts.TearDownSuite)
+
+	_, err := ts.Connection.Statement(t.Context(), "CREATE TABLE test_models (id INTEGER PRIMARY KEY, name TEXT)")
+	require.NoError(t, err)
+
+	ts.SeedData(&TestModel{ID: 1, Name: "seeded"})
+	ts.AssertRecordExists(&TestModel{}, 1)
+	require.NotNil(t, ts.GetRawConnection())
+
+	ts.TruncateTable("test_models")
+	ts.AssertTableEmpty("test_models")
+	ts.AssertRecordNotExists(&TestModel{}, 1)
+}
+
 // TestModel for testing purposes
 type (
 	TestModel struct {
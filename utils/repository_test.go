@@ -5,10 +5,11 @@ import (
 	"errors"
 	"testing"
 
+	gormadapter "github.com/next-trace/scg-database/adapter/gorm"
+	"github.com/next-trace/scg-database/config"
 	"github.com/next-trace/scg-database/contract"
 	"github.com/next-trace/scg-database/db"
 	"github.com/stretchr/testify/assert"
-	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
 
@@ -117,7 +118,7 @@ func (r *BelongsToManyRelationship) ManyToManyJoinTable() string     { return r.
 
 // Helper function to create a test GORM DB
 func createTestDB() *gorm.DB {
-	database, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	database, err := gormadapter.New(&config.Config{Driver: testSQLiteDriver, DSN: ":memory:"})
 	if err != nil {
 		panic("failed to connect database")
 	}